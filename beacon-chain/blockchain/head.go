@@ -100,25 +100,54 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 	}
 
 	// A chain re-org occurred, so we fire an event notifying the rest of the services.
-	if bytesutil.ToBytes32(newHeadBlock.Block.ParentRoot) != s.headRoot() {
-		log.WithFields(logrus.Fields{
-			"newSlot": fmt.Sprintf("%d", newHeadBlock.Block.Slot),
-			"oldSlot": fmt.Sprintf("%d", s.headSlot()),
-		}).Debug("Chain reorg occurred")
-		s.stateNotifier.StateFeed().Send(&feed.Event{
-			Type: statefeed.Reorg,
-			Data: &statefeed.ReorgData{
-				NewSlot: newHeadBlock.Block.Slot,
-				OldSlot: s.headSlot(),
-			},
-		})
-
-		reorgCount.Inc()
+	oldHeadRoot := s.headRoot()
+	oldHeadSlot := s.headSlot()
+	if bytesutil.ToBytes32(newHeadBlock.Block.ParentRoot) != oldHeadRoot {
+		ancestorRoot, ancestorSlot, err := s.commonAncestor(ctx, oldHeadRoot, oldHeadSlot, headRoot, newHeadBlock.Block.Slot)
+		if err != nil {
+			// The reorg is still reported, with the depth known so far as a lower bound.
+			log.WithError(err).Warn("Could not determine common ancestor of old and new head")
+		}
+		if ancestorRoot != oldHeadRoot {
+			depth := oldHeadSlot - ancestorSlot
+			log.WithFields(logrus.Fields{
+				"newSlot":      fmt.Sprintf("%d", newHeadBlock.Block.Slot),
+				"oldSlot":      fmt.Sprintf("%d", oldHeadSlot),
+				"ancestorSlot": fmt.Sprintf("%d", ancestorSlot),
+				"depth":        depth,
+			}).Debug("Chain reorg occurred")
+			s.stateNotifier.StateFeed().Send(&feed.Event{
+				Type: statefeed.Reorg,
+				Data: &statefeed.ReorgData{
+					NewSlot:            newHeadBlock.Block.Slot,
+					OldSlot:            oldHeadSlot,
+					NewHeadRoot:        headRoot,
+					OldHeadRoot:        oldHeadRoot,
+					CommonAncestorRoot: ancestorRoot,
+					CommonAncestorSlot: ancestorSlot,
+					Depth:              depth,
+				},
+			})
+
+			reorgCount.Inc()
+			reorgDepth.Observe(float64(depth))
+		}
 	}
+	epochTransition := helpers.SlotToEpoch(newHeadBlock.Block.Slot) > helpers.SlotToEpoch(oldHeadSlot)
 
 	// Cache the new head info.
 	s.setHead(headRoot, newHeadBlock, newHeadState)
 
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{
+			Slot:            newHeadBlock.Block.Slot,
+			BlockRoot:       headRoot,
+			StateRoot:       bytesutil.ToBytes32(newHeadBlock.Block.StateRoot),
+			EpochTransition: epochTransition,
+		},
+	})
+
 	// Save the new head root to DB.
 	if err := s.beaconDB.SaveHeadBlockRoot(ctx, headRoot); err != nil {
 		return errors.Wrap(err, "could not save head root in DB")
@@ -127,6 +156,76 @@ func (s *Service) saveHead(ctx context.Context, headRoot [32]byte) error {
 	return nil
 }
 
+// This returns the root and slot of the most recent block which is an ancestor of both
+// input blocks, walking back the chain of the block with the higher slot one block at a time.
+// If one block is an ancestor of the other, that block is returned. Parents are looked up in
+// fork choice, falling back to the DB for blocks not in fork choice, and the walk never goes
+// below the finalized slot. On error, the returned slot is the lowest slot reached on the
+// chain of the first block, which bounds the slot of the common ancestor from above.
+func (s *Service) commonAncestor(ctx context.Context, root1 [32]byte, slot1 uint64, root2 [32]byte, slot2 uint64) ([32]byte, uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "blockchain.commonAncestor")
+	defer span.End()
+
+	var finalizedSlot uint64
+	if s.finalizedCheckpt != nil {
+		finalizedSlot = helpers.StartSlot(s.finalizedCheckpt.Epoch)
+	}
+	// A snapshot of the fork choice nodes, indexed by root.
+	var nodes []*protoarray.Node
+	indices := make(map[[32]byte]int)
+	if s.forkChoiceStore != nil {
+		nodes = s.forkChoiceStore.Nodes()
+		for i, n := range nodes {
+			indices[n.Root] = i
+		}
+	}
+	parent := func(root [32]byte, slot uint64) ([32]byte, uint64, error) {
+		if slot <= finalizedSlot {
+			return [32]byte{}, 0, errors.Errorf("reached finalized slot %d without a common ancestor", finalizedSlot)
+		}
+		if i, ok := indices[root]; ok && nodes[i].Parent < uint64(len(nodes)) {
+			p := nodes[nodes[i].Parent]
+			return p.Root, p.Slot, nil
+		}
+		b, err := s.beaconDB.Block(ctx, root)
+		if err != nil {
+			return [32]byte{}, 0, err
+		}
+		if b == nil || b.Block == nil {
+			return [32]byte{}, 0, errors.Errorf("block %#x not found", bytesutil.Trunc(root[:]))
+		}
+		parentRoot := bytesutil.ToBytes32(b.Block.ParentRoot)
+		pb, err := s.beaconDB.Block(ctx, parentRoot)
+		if err != nil {
+			return [32]byte{}, 0, err
+		}
+		if pb == nil || pb.Block == nil {
+			return [32]byte{}, 0, errors.Errorf("block %#x not found", bytesutil.Trunc(parentRoot[:]))
+		}
+		return parentRoot, pb.Block.Slot, nil
+	}
+
+	for root1 != root2 {
+		if ctx.Err() != nil {
+			return [32]byte{}, slot1, ctx.Err()
+		}
+		if slot1 >= slot2 {
+			r, slot, err := parent(root1, slot1)
+			if err != nil {
+				return [32]byte{}, slot1, err
+			}
+			root1, slot1 = r, slot
+		} else {
+			r, slot, err := parent(root2, slot2)
+			if err != nil {
+				return [32]byte{}, slot1, err
+			}
+			root2, slot2 = r, slot
+		}
+	}
+	return root1, slot1, nil
+}
+
 // This gets called to update canonical root mapping. It does not save head block
 // root in DB. With the inception of initial-sync-cache-state flag, it uses finalized
 // check point as anchors to resume sync therefore head is no longer needed to be saved on per slot basis.
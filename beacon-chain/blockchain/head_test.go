@@ -7,6 +7,8 @@ import (
 	"testing"
 
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
@@ -84,18 +86,39 @@ func TestSaveHead_Different(t *testing.T) {
 	}
 }
 
+// saveForkBlock saves a block with the given slot and parent to the DB and returns its root.
+func saveForkBlock(t *testing.T, service *Service, slot uint64, parentRoot [32]byte, graffiti byte) [32]byte {
+	b := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{
+		Slot:       slot,
+		ParentRoot: parentRoot[:],
+		Body:       &ethpb.BeaconBlockBody{Graffiti: []byte{graffiti}},
+	}}
+	if err := service.beaconDB.SaveBlock(context.Background(), b); err != nil {
+		t.Fatal(err)
+	}
+	r, err := stateutil.BlockRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
 func TestSaveHead_Different_Reorg(t *testing.T) {
 	hook := logTest.NewGlobal()
 	db, sc := testDB.SetupDB(t)
 	service := setupBeaconChain(t, db, sc)
 
-	oldRoot := [32]byte{'A'}
-	service.head = &head{slot: 0, root: oldRoot}
+	// The old head and the new head fork off a common ancestor at slot 1:
+	//   1 <- 2 <- 3 (old head)
+	//   1 <- 4 (new head)
+	ancestorRoot := saveForkBlock(t, service, 1, [32]byte{}, 'a')
+	oldParentRoot := saveForkBlock(t, service, 2, ancestorRoot, 'b')
+	oldRoot := saveForkBlock(t, service, 3, oldParentRoot, 'b')
+	service.head = &head{slot: 3, root: oldRoot}
 
-	reorgChainParent := [32]byte{'B'}
 	newHeadBlock := &ethpb.BeaconBlock{
-		Slot:       1,
-		ParentRoot: reorgChainParent[:],
+		Slot:       4,
+		ParentRoot: ancestorRoot[:],
 	}
 	newHeadSignedBlock := &ethpb.SignedBeaconBlock{Block: newHeadBlock}
 
@@ -107,20 +130,23 @@ func TestSaveHead_Different_Reorg(t *testing.T) {
 		t.Fatal(err)
 	}
 	headState := testutil.NewBeaconState()
-	if err := headState.SetSlot(1); err != nil {
+	if err := headState.SetSlot(4); err != nil {
 		t.Fatal(err)
 	}
-	if err := service.beaconDB.SaveStateSummary(context.Background(), &pb.StateSummary{Slot: 1, Root: newRoot[:]}); err != nil {
+	if err := service.beaconDB.SaveStateSummary(context.Background(), &pb.StateSummary{Slot: 4, Root: newRoot[:]}); err != nil {
 		t.Fatal(err)
 	}
 	if err := service.beaconDB.SaveState(context.Background(), headState, newRoot); err != nil {
 		t.Fatal(err)
 	}
+	events := make(chan *feed.Event, 2)
+	sub := service.stateNotifier.StateFeed().Subscribe(events)
+	defer sub.Unsubscribe()
 	if err := service.saveHead(context.Background(), newRoot); err != nil {
 		t.Fatal(err)
 	}
 
-	if service.HeadSlot() != 1 {
+	if service.HeadSlot() != 4 {
 		t.Error("Head did not change")
 	}
 
@@ -138,6 +164,107 @@ func TestSaveHead_Different_Reorg(t *testing.T) {
 		t.Error("Head did not change")
 	}
 	testutil.AssertLogsContain(t, hook, "Chain reorg occurred")
+
+	ev := <-events
+	if ev.Type != statefeed.Reorg {
+		t.Fatalf("Wanted reorg event, received event type %d", ev.Type)
+	}
+	want := &statefeed.ReorgData{
+		NewSlot:            4,
+		OldSlot:            3,
+		NewHeadRoot:        newRoot,
+		OldHeadRoot:        oldRoot,
+		CommonAncestorRoot: ancestorRoot,
+		CommonAncestorSlot: 1,
+		Depth:              2,
+	}
+	if !reflect.DeepEqual(ev.Data, want) {
+		t.Errorf("Wanted reorg data %+v, received %+v", want, ev.Data)
+	}
+}
+
+func TestSaveHead_Different_Descendant(t *testing.T) {
+	hook := logTest.NewGlobal()
+	db, sc := testDB.SetupDB(t)
+	service := setupBeaconChain(t, db, sc)
+
+	// The new head descends from the old head through an intermediate block.
+	oldRoot := saveForkBlock(t, service, 1, [32]byte{}, 'a')
+	service.head = &head{slot: 1, root: oldRoot}
+	parentRoot := saveForkBlock(t, service, 2, oldRoot, 'a')
+	newRoot := saveForkBlock(t, service, 3, parentRoot, 'a')
+
+	headState := testutil.NewBeaconState()
+	if err := headState.SetSlot(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveStateSummary(context.Background(), &pb.StateSummary{Slot: 3, Root: newRoot[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.beaconDB.SaveState(context.Background(), headState, newRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.saveHead(context.Background(), newRoot); err != nil {
+		t.Fatal(err)
+	}
+
+	if service.HeadSlot() != 3 {
+		t.Error("Head did not change")
+	}
+	testutil.AssertLogsDoNotContain(t, hook, "Chain reorg occurred")
+}
+
+func TestCommonAncestor(t *testing.T) {
+	db, sc := testDB.SetupDB(t)
+	service := setupBeaconChain(t, db, sc)
+	ctx := context.Background()
+
+	//   1 <- 2 <- 4
+	//    \
+	//     <- 3 <- 5
+	root1 := saveForkBlock(t, service, 1, [32]byte{}, 'a')
+	root2 := saveForkBlock(t, service, 2, root1, 'a')
+	root3 := saveForkBlock(t, service, 3, root1, 'b')
+	root4 := saveForkBlock(t, service, 4, root2, 'a')
+	root5 := saveForkBlock(t, service, 5, root3, 'b')
+
+	tests := []struct {
+		name       string
+		r1, r2     [32]byte
+		s1, s2     uint64
+		wantedRoot [32]byte
+		wantedSlot uint64
+	}{
+		{name: "same block", r1: root4, s1: 4, r2: root4, s2: 4, wantedRoot: root4, wantedSlot: 4},
+		{name: "descendant", r1: root2, s1: 2, r2: root4, s2: 4, wantedRoot: root2, wantedSlot: 2},
+		{name: "fork", r1: root4, s1: 4, r2: root5, s2: 5, wantedRoot: root1, wantedSlot: 1},
+		{name: "fork reversed", r1: root5, s1: 5, r2: root2, s2: 2, wantedRoot: root1, wantedSlot: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, s, err := service.commonAncestor(ctx, tt.r1, tt.s1, tt.r2, tt.s2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r != tt.wantedRoot || s != tt.wantedSlot {
+				t.Errorf("Wanted ancestor %#x at slot %d, received %#x at slot %d", tt.wantedRoot, tt.wantedSlot, r, s)
+			}
+		})
+	}
+
+	if _, _, err := service.commonAncestor(ctx, root4, 4, [32]byte{'x'}, 3); err == nil {
+		t.Error("Expected error for unknown block")
+	}
+
+	// The walk stops at the finalized slot, reporting the lowest slot reached.
+	service.finalizedCheckpt = &ethpb.Checkpoint{Epoch: 0}
+	if _, _, err := service.commonAncestor(ctx, root4, 4, root5, 5); err != nil {
+		t.Fatal(err)
+	}
+	service.finalizedCheckpt = &ethpb.Checkpoint{Epoch: 1}
+	if _, s, err := service.commonAncestor(ctx, root4, 4, root5, 5); err == nil || s != 4 {
+		t.Errorf("Expected error at slot 4 below the finalized slot, received slot %d: %v", s, err)
+	}
 }
 
 func TestUpdateRecentCanonicalBlocks_CanUpdateWithoutParent(t *testing.T) {
@@ -81,6 +81,13 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock,
 		return nil, errors.Wrap(err, "could not execute state transition")
 	}
 
+	// The weak subjectivity checkpoint is verified before anything of a block finalizing a
+	// conflicting chain is persisted.
+	if err := s.verifyWeakSubjectivity(ctx, postState.FinalizedCheckpoint()); err != nil {
+		haltOnWeakSubjectivityFailure(err)
+		return nil, err
+	}
+
 	if err := s.beaconDB.SaveBlock(ctx, signed); err != nil {
 		return nil, errors.Wrapf(err, "could not save block from slot %d", b.Slot)
 	}
@@ -133,6 +140,8 @@ func (s *Service) onBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock,
 		if err := s.stateGen.MigrateToCold(ctx, fBlock.Block.Slot, fRoot); err != nil {
 			return nil, errors.Wrap(err, "could not migrate to cold")
 		}
+		s.pruneHistory(fBlock.Block.Slot, fRoot)
+		s.notifyFinalizedCheckpoint(fBlock, fRoot)
 	}
 
 	// Epoch boundary bookkeeping such as logging epoch summaries.
@@ -204,6 +213,13 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 		return errors.Wrap(err, "could not execute state transition")
 	}
 
+	// The weak subjectivity checkpoint is verified before anything of a block finalizing a
+	// conflicting chain is persisted.
+	if err := s.verifyWeakSubjectivity(ctx, postState.FinalizedCheckpoint()); err != nil {
+		haltOnWeakSubjectivityFailure(err)
+		return err
+	}
+
 	s.saveInitSyncBlock(blockRoot, signed)
 
 	if err := s.insertBlockToForkChoiceStore(ctx, b, blockRoot, postState); err != nil {
@@ -251,6 +267,8 @@ func (s *Service) onBlockInitialSyncStateTransition(ctx context.Context, signed
 		if err := s.stateGen.MigrateToCold(ctx, fBlock.Block.Slot, fRoot); err != nil {
 			return errors.Wrap(err, "could not migrate to cold")
 		}
+		s.pruneHistory(fBlock.Block.Slot, fRoot)
+		s.notifyFinalizedCheckpoint(fBlock, fRoot)
 	}
 
 	// Epoch boundary bookkeeping such as logging epoch summaries.
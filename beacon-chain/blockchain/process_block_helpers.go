@@ -7,7 +7,10 @@ import (
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	"github.com/prysmaticlabs/prysm/shared/attestationutil"
@@ -352,6 +355,49 @@ func (s *Service) deletePoolAtts(atts []*ethpb.Attestation) error {
 	return nil
 }
 
+// This notifies the rest of the services of a newly finalized checkpoint.
+func (s *Service) notifyFinalizedCheckpoint(fBlock *ethpb.SignedBeaconBlock, fRoot [32]byte) {
+	s.stateNotifier.StateFeed().Send(&feed.Event{
+		Type: statefeed.FinalizedCheckpoint,
+		Data: &statefeed.FinalizedCheckpointData{
+			Epoch:     s.finalizedCheckpt.Epoch,
+			BlockRoot: fRoot,
+			StateRoot: bytesutil.ToBytes32(fBlock.Block.StateRoot),
+		},
+	})
+}
+
+// This deletes the history older than the configured retention period behind the finalized block,
+// and the fork blocks which are not its ancestors, in the background. Finalized checkpoints which
+// arrive while pruning are skipped, as the next run covers their slots.
+func (s *Service) pruneHistory(fSlot uint64, fRoot [32]byte) {
+	retentionEpochs := flags.Get().DBRetentionEpochs
+	if retentionEpochs == 0 {
+		return
+	}
+	s.pruningLock.Lock()
+	defer s.pruningLock.Unlock()
+	if s.pruning {
+		return
+	}
+	s.pruning = true
+
+	var retainSlot uint64
+	if retained := retentionEpochs * params.BeaconConfig().SlotsPerEpoch; fSlot > retained {
+		retainSlot = fSlot - retained
+	}
+	go func() {
+		defer func() {
+			s.pruningLock.Lock()
+			s.pruning = false
+			s.pruningLock.Unlock()
+		}()
+		if err := s.beaconDB.PruneHistory(s.ctx, fRoot, retainSlot); err != nil {
+			log.WithError(err).Error("Could not prune database history")
+		}
+	}()
+}
+
 // This ensures that the input root defaults to using genesis root instead of zero hashes. This is needed for handling
 // fork choice justification routine.
 func (s *Service) ensureRootNotZeros(root [32]byte) [32]byte {
@@ -82,23 +82,29 @@ type Service struct {
 	recentCanonicalBlocksLock sync.RWMutex
 	justifiedBalances         []uint64
 	justifiedBalancesLock     sync.RWMutex
+	wsCheckpt                 *ethpb.Checkpoint
+	wsVerified                bool
+	wsVerifiedLock            sync.RWMutex
+	pruning                   bool
+	pruningLock               sync.Mutex
 }
 
 // Config options for the service.
 type Config struct {
-	BeaconBlockBuf    int
-	ChainStartFetcher powchain.ChainStartFetcher
-	BeaconDB          db.HeadAccessDatabase
-	DepositCache      *depositcache.DepositCache
-	AttPool           attestations.Pool
-	ExitPool          *voluntaryexits.Pool
-	SlashingPool      *slashings.Pool
-	P2p               p2p.Broadcaster
-	MaxRoutines       int64
-	StateNotifier     statefeed.Notifier
-	ForkChoiceStore   f.ForkChoicer
-	OpsService        *attestations.Service
-	StateGen          *stategen.State
+	BeaconBlockBuf          int
+	ChainStartFetcher       powchain.ChainStartFetcher
+	BeaconDB                db.HeadAccessDatabase
+	DepositCache            *depositcache.DepositCache
+	AttPool                 attestations.Pool
+	ExitPool                *voluntaryexits.Pool
+	SlashingPool            *slashings.Pool
+	P2p                     p2p.Broadcaster
+	MaxRoutines             int64
+	StateNotifier           statefeed.Notifier
+	ForkChoiceStore         f.ForkChoicer
+	OpsService              *attestations.Service
+	StateGen                *stategen.State
+	WeakSubjectivityCheckpt *ethpb.Checkpoint
 }
 
 // NewService instantiates a new block service instance that will
@@ -127,6 +133,7 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 		initSyncBlocks:        make(map[[32]byte]*ethpb.SignedBeaconBlock),
 		recentCanonicalBlocks: make(map[[32]byte]bool),
 		justifiedBalances:     make([]uint64, 0),
+		wsCheckpt:             cfg.WeakSubjectivityCheckpt,
 	}, nil
 }
 
@@ -187,6 +194,12 @@ func (s *Service) Start() {
 		s.finalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.prevFinalizedCheckpt = stateTrie.CopyCheckpoint(finalizedCheckpoint)
 		s.resumeForkChoice(justifiedCheckpoint, finalizedCheckpoint)
+		if err := s.insertOriginBlock(ctx, finalizedCheckpoint); err != nil {
+			log.Fatalf("Could not insert origin block into fork choice: %v", err)
+		}
+		if err := s.verifyWeakSubjectivityOnStartup(ctx); err != nil {
+			log.Fatalf("Could not verify weak subjectivity checkpoint: %v", err)
+		}
 
 		s.stateNotifier.StateFeed().Send(&feed.Event{
 			Type: statefeed.Initialized,
@@ -382,14 +395,24 @@ func (s *Service) initializeChainInfo(ctx context.Context) error {
 	if err != nil {
 		return errors.Wrap(err, "could not get genesis block from db")
 	}
-	if genesisBlock == nil {
-		return errors.New("no genesis block in db")
-	}
-	genesisBlkRoot, err := stateutil.BlockRoot(genesisBlock.Block)
-	if err != nil {
-		return errors.Wrap(err, "could not get signing root of genesis block")
+	if genesisBlock != nil {
+		genesisBlkRoot, err := stateutil.BlockRoot(genesisBlock.Block)
+		if err != nil {
+			return errors.Wrap(err, "could not get signing root of genesis block")
+		}
+		s.genesisRoot = genesisBlkRoot
+	} else {
+		// A node started from a weak subjectivity checkpoint does not have the genesis
+		// block until it has been backfilled, so the origin block takes its place.
+		originRoot, err := s.beaconDB.OriginBlockRoot(ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get origin block root from db")
+		}
+		if originRoot == params.BeaconConfig().ZeroHash {
+			return errors.New("no genesis block in db")
+		}
+		s.genesisRoot = originRoot
 	}
-	s.genesisRoot = genesisBlkRoot
 
 	if flags.Get().UnsafeSync {
 		headBlock, err := s.beaconDB.HeadBlock(ctx)
@@ -489,6 +512,33 @@ func (s *Service) resumeForkChoice(justifiedCheckpoint *ethpb.Checkpoint, finali
 	s.forkChoiceStore = store
 }
 
+// This inserts the origin block of a node started from a weak subjectivity checkpoint into fork
+// choice, as long as it is still the finalized block. Unlike on a regular restart, no block
+// descending from the finalized block is in the database for fork choice to start from.
+func (s *Service) insertOriginBlock(ctx context.Context, finalizedCheckpoint *ethpb.Checkpoint) error {
+	originRoot, err := s.beaconDB.OriginBlockRoot(ctx)
+	if err != nil {
+		return err
+	}
+	if originRoot != bytesutil.ToBytes32(finalizedCheckpoint.Root) {
+		return nil
+	}
+	originBlock, err := s.beaconDB.Block(ctx, originRoot)
+	if err != nil {
+		return err
+	}
+	if originBlock == nil {
+		return errors.New("origin block is missing from the database")
+	}
+	return s.forkChoiceStore.ProcessBlock(ctx,
+		originBlock.Block.Slot,
+		originRoot,
+		bytesutil.ToBytes32(originBlock.Block.ParentRoot),
+		bytesutil.ToBytes32(originBlock.Block.Body.Graffiti),
+		finalizedCheckpoint.Epoch,
+		finalizedCheckpoint.Epoch)
+}
+
 // This returns true if block has been processed before. Two ways to verify the block has been processed:
 // 1.) Check fork choice store.
 // 2.) Check DB.
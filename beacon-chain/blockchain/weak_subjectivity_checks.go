@@ -0,0 +1,144 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+var errWSBlockNotInChain = errors.New("weak subjectivity checkpoint block is not part of the finalized chain")
+
+// ParseWeakSubjectivityInputString parses a weak subjectivity checkpoint given in the
+// `block_root:epoch` format, where the block root is a 0x prefixed hex string.
+func ParseWeakSubjectivityInputString(wsCheckpointString string) (*ethpb.Checkpoint, error) {
+	s := strings.Split(wsCheckpointString, ":")
+	if len(s) != 2 {
+		return nil, fmt.Errorf("%s did not contain a block root and an epoch separated by a colon", wsCheckpointString)
+	}
+	bRoot, err := hex.DecodeString(strings.TrimPrefix(s[0], "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode weak subjectivity block root")
+	}
+	if len(bRoot) != 32 {
+		return nil, errors.New("weak subjectivity block root is not 32 bytes long")
+	}
+	epoch, err := strconv.ParseUint(s[1], 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse weak subjectivity epoch")
+	}
+	return &ethpb.Checkpoint{
+		Epoch: epoch,
+		Root:  bRoot,
+	}, nil
+}
+
+// haltOnWeakSubjectivityFailure stops the node, which must not follow a chain conflicting
+// with the weak subjectivity checkpoint any further.
+var haltOnWeakSubjectivityFailure = func(err error) {
+	log.WithError(err).Fatal("Chain conflicts with the weak subjectivity checkpoint, stopping the node")
+}
+
+// This verifies the weak subjectivity checkpoint against the finalized chain stored in the DB,
+// when the node restarts with a finalized checkpoint at or beyond the weak subjectivity epoch.
+// The checkpoint block must be finalized, and it must be the last finalized block at or before
+// the start slot of the checkpoint epoch.
+func (s *Service) verifyWeakSubjectivityOnStartup(ctx context.Context) error {
+	if s.wsCheckpt == nil || s.finalizedCheckpt.Epoch < s.wsCheckpt.Epoch {
+		return nil
+	}
+	wsRoot := bytesutil.ToBytes32(s.wsCheckpt.Root)
+	if !s.beaconDB.IsFinalizedBlock(ctx, wsRoot) {
+		// A node started from a checkpoint ahead of the weak subjectivity epoch does not have
+		// the blocks required to verify it, until they have been backfilled.
+		originRoot, err := s.beaconDB.OriginBlockRoot(ctx)
+		if err != nil {
+			return err
+		}
+		if originRoot != params.BeaconConfig().ZeroHash {
+			originBlock, err := s.beaconDB.Block(ctx, originRoot)
+			if err != nil {
+				return err
+			}
+			if originBlock != nil && originBlock.Block.Slot > helpers.StartSlot(s.wsCheckpt.Epoch) {
+				return errors.Wrapf(
+					errWSBlockNotInChain,
+					"root %#x, epoch %d precedes the checkpoint the node was started from at slot %d",
+					s.wsCheckpt.Root,
+					s.wsCheckpt.Epoch,
+					originBlock.Block.Slot,
+				)
+			}
+		}
+		return errors.Wrapf(errWSBlockNotInChain, "root %#x, epoch %d", s.wsCheckpt.Root, s.wsCheckpt.Epoch)
+	}
+
+	wsBlock, err := s.beaconDB.Block(ctx, wsRoot)
+	if err != nil {
+		return err
+	}
+	if wsBlock == nil || wsBlock.Block == nil {
+		return errors.Wrapf(errWSBlockNotInChain, "root %#x, epoch %d, block is missing", s.wsCheckpt.Root, s.wsCheckpt.Epoch)
+	}
+	wsSlot := helpers.StartSlot(s.wsCheckpt.Epoch)
+	if wsBlock.Block.Slot > wsSlot {
+		return errors.Wrapf(errWSBlockNotInChain, "root %#x, epoch %d, block is at slot %d", s.wsCheckpt.Root, s.wsCheckpt.Epoch, wsBlock.Block.Slot)
+	}
+	if wsBlock.Block.Slot < wsSlot {
+		// A later finalized block up to the start slot of the epoch is the actual checkpoint block.
+		f := filters.NewFilter().SetStartSlot(wsBlock.Block.Slot + 1).SetEndSlot(wsSlot)
+		roots, err := s.beaconDB.BlockRoots(ctx, f)
+		if err != nil {
+			return err
+		}
+		for _, r := range roots {
+			if s.beaconDB.IsFinalizedBlock(ctx, r) {
+				return errors.Wrapf(errWSBlockNotInChain, "root %#x, epoch %d, found root %#x", s.wsCheckpt.Root, s.wsCheckpt.Epoch, r)
+			}
+		}
+	}
+	s.setWSVerified()
+	log.WithField("epoch", s.wsCheckpt.Epoch).Info("Verified weak subjectivity checkpoint")
+	return nil
+}
+
+// This verifies the weak subjectivity checkpoint is an ancestor of a new finalized checkpoint,
+// before the node processes a block finalizing a chain at or beyond the weak subjectivity epoch.
+// This prevents the node from following a long range fork which does not contain the weak
+// subjectivity checkpoint.
+func (s *Service) verifyWeakSubjectivity(ctx context.Context, finalized *ethpb.Checkpoint) error {
+	if s.wsCheckpt == nil || s.isWSVerified() || finalized.Epoch < s.wsCheckpt.Epoch {
+		return nil
+	}
+	r, err := s.ancestor(ctx, finalized.Root, helpers.StartSlot(s.wsCheckpt.Epoch))
+	if err != nil {
+		return errors.Wrap(err, "could not get weak subjectivity checkpoint ancestor")
+	}
+	if !bytes.Equal(r, s.wsCheckpt.Root) {
+		return errors.Wrapf(errWSBlockNotInChain, "root %#x, epoch %d, found root %#x", s.wsCheckpt.Root, s.wsCheckpt.Epoch, r)
+	}
+	s.setWSVerified()
+	log.WithField("epoch", s.wsCheckpt.Epoch).Info("Verified weak subjectivity checkpoint")
+	return nil
+}
+
+func (s *Service) isWSVerified() bool {
+	s.wsVerifiedLock.RLock()
+	defer s.wsVerifiedLock.RUnlock()
+	return s.wsVerified
+}
+
+func (s *Service) setWSVerified() {
+	s.wsVerifiedLock.Lock()
+	defer s.wsVerifiedLock.Unlock()
+	s.wsVerified = true
+}
@@ -0,0 +1,214 @@
+package blockchain
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestParseWeakSubjectivityInputString(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantedErr   string
+		wantedEpoch uint64
+	}{
+		{
+			name:      "No colon",
+			input:     "0x6f8d1e1dc58aefc24c32880f0001e9e3f65a36e49e4b7b8287d43d8ee8b32ed5",
+			wantedErr: "did not contain a block root and an epoch",
+		},
+		{
+			name:      "Invalid root",
+			input:     "0xzz:100",
+			wantedErr: "could not decode weak subjectivity block root",
+		},
+		{
+			name:      "Short root",
+			input:     "0x6f8d:100",
+			wantedErr: "not 32 bytes long",
+		},
+		{
+			name:      "Invalid epoch",
+			input:     "0x6f8d1e1dc58aefc24c32880f0001e9e3f65a36e49e4b7b8287d43d8ee8b32ed5:abc",
+			wantedErr: "could not parse weak subjectivity epoch",
+		},
+		{
+			name:        "Valid input",
+			input:       "0x6f8d1e1dc58aefc24c32880f0001e9e3f65a36e49e4b7b8287d43d8ee8b32ed5:100",
+			wantedEpoch: 100,
+		},
+		{
+			name:        "Valid input without 0x prefix",
+			input:       "6f8d1e1dc58aefc24c32880f0001e9e3f65a36e49e4b7b8287d43d8ee8b32ed5:5",
+			wantedEpoch: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cp, err := ParseWeakSubjectivityInputString(tt.input)
+			if tt.wantedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantedErr) {
+					t.Fatalf("Expected error %q, received %v", tt.wantedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cp.Epoch != tt.wantedEpoch {
+				t.Errorf("Wanted epoch %d, received %d", tt.wantedEpoch, cp.Epoch)
+			}
+			if len(cp.Root) != 32 {
+				t.Errorf("Wanted 32 byte root, received %d bytes", len(cp.Root))
+			}
+		})
+	}
+}
+
+func TestService_VerifyWeakSubjectivity(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testDB.SetupDB(t)
+
+	// Chain of blocks at slots 0, epoch 1 start slot and epoch 2 start slot.
+	slots := []uint64{0, helpers.StartSlot(1), helpers.StartSlot(2)}
+	roots := make([][32]byte, len(slots))
+	parentRoot := [32]byte{}
+	for i, slot := range slots {
+		b := testutil.NewBeaconBlock()
+		b.Block.Slot = slot
+		b.Block.ParentRoot = parentRoot[:]
+		if err := db.SaveBlock(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+		r, err := stateutil.BlockRoot(b.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		roots[i] = r
+		parentRoot = r
+	}
+	finalized := &ethpb.Checkpoint{Epoch: 2, Root: roots[2][:]}
+
+	tests := []struct {
+		name      string
+		wsCheckpt *ethpb.Checkpoint
+		wantedErr string
+	}{
+		{
+			name: "No weak subjectivity checkpoint",
+		},
+		{
+			name:      "Checkpoint is ahead of finalized epoch",
+			wsCheckpt: &ethpb.Checkpoint{Epoch: 3, Root: bytesutil.PadTo([]byte{'a'}, 32)},
+		},
+		{
+			name:      "Checkpoint is in chain",
+			wsCheckpt: &ethpb.Checkpoint{Epoch: 1, Root: roots[1][:]},
+		},
+		{
+			name:      "Checkpoint is not in chain",
+			wsCheckpt: &ethpb.Checkpoint{Epoch: 1, Root: bytesutil.PadTo([]byte{'a'}, 32)},
+			wantedErr: errWSBlockNotInChain.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				beaconDB:       db,
+				wsCheckpt:      tt.wsCheckpt,
+				initSyncBlocks: make(map[[32]byte]*ethpb.SignedBeaconBlock),
+			}
+			err := s.verifyWeakSubjectivity(ctx, finalized)
+			if tt.wantedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantedErr) {
+					t.Fatalf("Expected error %q, received %v", tt.wantedErr, err)
+				}
+				if s.isWSVerified() {
+					t.Error("Expected checkpoint to not be verified")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wsCheckpt != nil && tt.wsCheckpt.Epoch <= finalized.Epoch && !s.isWSVerified() {
+				t.Error("Expected checkpoint to be verified")
+			}
+		})
+	}
+}
+
+func TestService_VerifyWeakSubjectivityOnStartup(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testDB.SetupDB(t)
+
+	genesis := testutil.NewBeaconBlock()
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := testutil.NewBeaconBlock()
+	b.Block.Slot = helpers.StartSlot(1)
+	b.Block.ParentRoot = genesisRoot[:]
+	r, err := stateutil.BlockRoot(b.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{genesis, b}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), r); err != nil {
+		t.Fatal(err)
+	}
+	finalized := &ethpb.Checkpoint{Epoch: 1, Root: r[:]}
+	if err := db.SaveFinalizedCheckpoint(ctx, finalized); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{
+		beaconDB:         db,
+		finalizedCheckpt: finalized,
+		wsCheckpt:        &ethpb.Checkpoint{Epoch: 1, Root: r[:]},
+	}
+	if err := s.verifyWeakSubjectivityOnStartup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !s.isWSVerified() {
+		t.Error("Expected checkpoint to be verified")
+	}
+
+	s = &Service{
+		beaconDB:         db,
+		finalizedCheckpt: finalized,
+		wsCheckpt:        &ethpb.Checkpoint{Epoch: 1, Root: bytesutil.PadTo([]byte{'a'}, 32)},
+	}
+	err = s.verifyWeakSubjectivityOnStartup(ctx)
+	if err == nil || !strings.Contains(err.Error(), errWSBlockNotInChain.Error()) {
+		t.Fatalf("Expected error %q, received %v", errWSBlockNotInChain, err)
+	}
+	if s.isWSVerified() {
+		t.Error("Expected checkpoint to not be verified")
+	}
+
+	// The genesis block is finalized, but it is not the checkpoint block of epoch 1.
+	s = &Service{
+		beaconDB:         db,
+		finalizedCheckpt: finalized,
+		wsCheckpt:        &ethpb.Checkpoint{Epoch: 1, Root: genesisRoot[:]},
+	}
+	err = s.verifyWeakSubjectivityOnStartup(ctx)
+	if err == nil || !strings.Contains(err.Error(), errWSBlockNotInChain.Error()) {
+		t.Fatalf("Expected error %q, received %v", errWSBlockNotInChain, err)
+	}
+}
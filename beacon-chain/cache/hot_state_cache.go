@@ -1,17 +1,30 @@
 package cache
 
 import (
+	"container/list"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 
-	lru "github.com/hashicorp/golang-lru"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	log "github.com/sirupsen/logrus"
 )
 
 var (
-	// hotStateCacheSize defines the max number of hot state this can cache.
-	hotStateCacheSize = 32
+	// DefaultHotStateCacheSize defines the default estimated size in bytes of the hot states kept in memory.
+	DefaultHotStateCacheSize = uint64(512 << 20)
+	// hotStateFileSuffix is the file name suffix of the hot states spilled to disk.
+	hotStateFileSuffix = ".state"
 	// Metrics
 	hotStateCacheHit = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "hot_state_cache_hit",
@@ -21,48 +34,138 @@ var (
 		Name: "hot_state_cache_miss",
 		Help: "The total number of cache misses on the hot state cache.",
 	})
+	hotStateCacheEviction = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hot_state_cache_eviction",
+		Help: "The total number of states evicted from memory by the hot state cache.",
+	})
+	hotStateCacheSpillHit = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "hot_state_cache_spill_hit",
+		Help: "The total number of cache hits on the states the hot state cache spilled to disk.",
+	})
+	hotStateCacheSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "hot_state_cache_size_bytes",
+		Help: "The estimated size in bytes of the states kept in memory by the hot state cache.",
+	})
+	hotStateCacheSpillSize = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "hot_state_cache_spill_size_bytes",
+		Help: "The size in bytes of the states spilled to disk by the hot state cache.",
+	})
 )
 
-// HotStateCache is used to store the processed beacon state after finalized check point..
+// HotStateCacheConfig configures the bounds and the disk tier of the hot state cache.
+type HotStateCacheConfig struct {
+	// MaxSize is the estimated size in bytes of the states kept in memory.
+	MaxSize uint64
+	// SpillDir is the directory the states evicted from memory are written to. An empty
+	// directory disables the disk tier.
+	SpillDir string
+	// MaxSpillSize is the size in bytes of the states kept in the spill directory.
+	MaxSpillSize uint64
+}
+
+// hotStateEntry is a state of the hot state cache, either in memory or spilled to disk.
+type hotStateEntry struct {
+	root  [32]byte
+	state *stateTrie.BeaconState
+	size  uint64
+}
+
+// HotStateCache is used to store the processed beacon state after finalized check point.
+// The states are kept in memory up to an estimated size in bytes, the least recently used
+// states are then evicted to a bounded directory on disk if one is configured.
 type HotStateCache struct {
-	cache *lru.Cache
-	lock  sync.RWMutex
+	cfg        *HotStateCacheConfig
+	lock       sync.Mutex
+	entries    map[[32]byte]*list.Element
+	order      *list.List // Most recently used states first.
+	size       uint64
+	spilled    map[[32]byte]*list.Element
+	spillOrder *list.List // Most recently spilled states first.
+	spillSize  uint64
 }
 
-// NewHotStateCache initializes the map and underlying cache.
+// NewHotStateCache initializes the hot state cache with the default size and no disk tier.
 func NewHotStateCache() *HotStateCache {
-	cache, err := lru.New(hotStateCacheSize)
+	c, err := NewHotStateCacheWithConfig(&HotStateCacheConfig{MaxSize: DefaultHotStateCacheSize})
 	if err != nil {
 		panic(err)
 	}
-	return &HotStateCache{
-		cache: cache,
+	return c
+}
+
+// NewHotStateCacheWithConfig initializes the hot state cache with the config. The states
+// spilled to the spill directory by a previous run are deleted, as they are not indexed.
+func NewHotStateCacheWithConfig(cfg *HotStateCacheConfig) (*HotStateCache, error) {
+	if cfg.MaxSize == 0 {
+		return nil, errors.New("hot state cache size must be greater than 0")
+	}
+	if cfg.SpillDir != "" {
+		if cfg.MaxSpillSize == 0 {
+			return nil, errors.New("hot state spill size must be greater than 0")
+		}
+		if err := os.MkdirAll(cfg.SpillDir, 0700); err != nil {
+			return nil, errors.Wrap(err, "could not create hot state spill directory")
+		}
+		files, err := ioutil.ReadDir(cfg.SpillDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not read hot state spill directory")
+		}
+		for _, f := range files {
+			if strings.HasSuffix(f.Name(), hotStateFileSuffix) {
+				if err := os.Remove(filepath.Join(cfg.SpillDir, f.Name())); err != nil {
+					return nil, errors.Wrap(err, "could not delete spilled hot state")
+				}
+			}
+		}
 	}
+	return &HotStateCache{
+		cfg:        cfg,
+		entries:    make(map[[32]byte]*list.Element),
+		order:      list.New(),
+		spilled:    make(map[[32]byte]*list.Element),
+		spillOrder: list.New(),
+	}, nil
 }
 
 // Get returns a cached response via input block root, if any.
 // The response is copied by default.
 func (c *HotStateCache) Get(root [32]byte) *stateTrie.BeaconState {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	item, exists := c.cache.Get(root)
-
-	if exists && item != nil {
-		hotStateCacheHit.Inc()
-		return item.(*stateTrie.BeaconState).Copy()
+	st := c.get(root)
+	if st == nil {
+		return nil
 	}
-	hotStateCacheMiss.Inc()
-	return nil
+	return st.Copy()
 }
 
 // GetWithoutCopy returns a non-copied cached response via input block root.
 func (c *HotStateCache) GetWithoutCopy(root [32]byte) *stateTrie.BeaconState {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	item, exists := c.cache.Get(root)
-	if exists && item != nil {
+	return c.get(root)
+}
+
+// get returns the state from memory, or loads a spilled state back into memory.
+func (c *HotStateCache) get(root [32]byte) *stateTrie.BeaconState {
+	c.lock.Lock()
+	if elem, ok := c.entries[root]; ok {
+		c.order.MoveToFront(elem)
+		c.lock.Unlock()
 		hotStateCacheHit.Inc()
-		return item.(*stateTrie.BeaconState)
+		return elem.Value.(*hotStateEntry).state
+	}
+	_, spilled := c.spilled[root]
+	if spilled {
+		// The file is deleted once it is loaded.
+		c.dropSpilled(root)
+	}
+	c.lock.Unlock()
+
+	if spilled {
+		st, err := c.loadSpilled(root)
+		if err == nil {
+			hotStateCacheSpillHit.Inc()
+			c.Put(root, st)
+			return st
+		}
+		log.WithError(err).Warn("Could not load spilled hot state")
 	}
 	hotStateCacheMiss.Inc()
 	return nil
@@ -70,21 +173,181 @@ func (c *HotStateCache) GetWithoutCopy(root [32]byte) *stateTrie.BeaconState {
 
 // Put the response in the cache.
 func (c *HotStateCache) Put(root [32]byte, state *stateTrie.BeaconState) {
+	size := estimatedStateSize(state)
+
 	c.lock.Lock()
-	defer c.lock.Unlock()
-	c.cache.Add(root, state)
+	if elem, ok := c.entries[root]; ok {
+		entry := elem.Value.(*hotStateEntry)
+		c.size = c.size - entry.size + size
+		entry.state = state
+		entry.size = size
+		c.order.MoveToFront(elem)
+	} else {
+		c.entries[root] = c.order.PushFront(&hotStateEntry{root: root, state: state, size: size})
+		c.size += size
+	}
+	if _, ok := c.spilled[root]; ok {
+		c.removeSpilled(root)
+	}
+	// The most recently used state is kept, even if it is larger than the cache.
+	var evicted []*hotStateEntry
+	for c.size > c.cfg.MaxSize && c.order.Len() > 1 {
+		entry := c.order.Remove(c.order.Back()).(*hotStateEntry)
+		delete(c.entries, entry.root)
+		c.size -= entry.size
+		evicted = append(evicted, entry)
+	}
+	hotStateCacheSize.Set(float64(c.size))
+	c.lock.Unlock()
+
+	hotStateCacheEviction.Add(float64(len(evicted)))
+	if c.cfg.SpillDir != "" {
+		for _, entry := range evicted {
+			c.spill(entry)
+		}
+	}
 }
 
 // Has returns true if the key exists in the cache.
 func (c *HotStateCache) Has(root [32]byte) bool {
-	c.lock.RLock()
-	defer c.lock.RUnlock()
-	return c.cache.Contains(root)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	_, inMemory := c.entries[root]
+	_, spilled := c.spilled[root]
+	return inMemory || spilled
 }
 
 // Delete deletes the key exists in the cache.
 func (c *HotStateCache) Delete(root [32]byte) bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
-	return c.cache.Remove(root)
+	elem, inMemory := c.entries[root]
+	if inMemory {
+		entry := c.order.Remove(elem).(*hotStateEntry)
+		delete(c.entries, root)
+		c.size -= entry.size
+		hotStateCacheSize.Set(float64(c.size))
+	}
+	_, spilled := c.spilled[root]
+	if spilled {
+		c.removeSpilled(root)
+	}
+	return inMemory || spilled
+}
+
+// spill writes the evicted state to the spill directory, and deletes the least recently
+// spilled states beyond the size of the disk tier. The state is written without holding the
+// lock, it is not spilled if it was put back in the cache in the meantime.
+func (c *HotStateCache) spill(entry *hotStateEntry) {
+	enc, err := proto.Marshal(entry.state.CloneInnerState())
+	if err != nil {
+		log.WithError(err).Warn("Could not encode hot state to spill")
+		return
+	}
+	enc = snappy.Encode(nil, enc)
+	if err := writeFileAtomic(c.spillPath(entry.root), enc); err != nil {
+		log.WithError(err).Warn("Could not spill hot state to disk")
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.entries[entry.root]; ok {
+		c.removeSpillFile(entry.root)
+		return
+	}
+	if _, ok := c.spilled[entry.root]; ok {
+		c.removeSpilled(entry.root)
+	}
+	c.spilled[entry.root] = c.spillOrder.PushFront(&hotStateEntry{root: entry.root, size: uint64(len(enc))})
+	c.spillSize += uint64(len(enc))
+	for c.spillSize > c.cfg.MaxSpillSize && c.spillOrder.Len() > 0 {
+		c.removeSpilled(c.spillOrder.Back().Value.(*hotStateEntry).root)
+	}
+	hotStateCacheSpillSize.Set(float64(c.spillSize))
+}
+
+// loadSpilled reads the state spilled under the root and deletes its file.
+func (c *HotStateCache) loadSpilled(root [32]byte) (*stateTrie.BeaconState, error) {
+	path := c.spillPath(root)
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Remove(path); err != nil {
+		log.WithError(err).Warn("Could not delete spilled hot state")
+	}
+	enc, err = snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, err
+	}
+	st := &pb.BeaconState{}
+	if err := proto.Unmarshal(enc, st); err != nil {
+		return nil, err
+	}
+	return stateTrie.InitializeFromProtoUnsafe(st)
+}
+
+// removeSpilled removes the spilled state from the disk tier. The lock must be held.
+func (c *HotStateCache) removeSpilled(root [32]byte) {
+	c.dropSpilled(root)
+	c.removeSpillFile(root)
+}
+
+// dropSpilled removes the spilled state from the index of the disk tier, without deleting its
+// file. The lock must be held.
+func (c *HotStateCache) dropSpilled(root [32]byte) {
+	elem, ok := c.spilled[root]
+	if !ok {
+		return
+	}
+	entry := c.spillOrder.Remove(elem).(*hotStateEntry)
+	delete(c.spilled, root)
+	c.spillSize -= entry.size
+	hotStateCacheSpillSize.Set(float64(c.spillSize))
+}
+
+func (c *HotStateCache) removeSpillFile(root [32]byte) {
+	if err := os.Remove(c.spillPath(root)); err != nil && !os.IsNotExist(err) {
+		log.WithError(err).Warn("Could not delete spilled hot state")
+	}
+}
+
+func (c *HotStateCache) spillPath(root [32]byte) string {
+	return filepath.Join(c.cfg.SpillDir, hex.EncodeToString(root[:])+hotStateFileSuffix)
+}
+
+// writeFileAtomic writes the file through a temporary file, so that a partially written
+// file is never read.
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// estimatedStateSize returns an estimate of the size in bytes of the state in memory, from its
+// validator count and the lengths of its vectors. States share unmodified fields with the states
+// they were copied from, so the estimate is an upper bound of the memory a state adds.
+func estimatedStateSize(st *stateTrie.BeaconState) uint64 {
+	if st == nil || !st.HasInnerState() {
+		return 0
+	}
+	const (
+		validatorSize          = 48 + 32 + 8 + 1 + 4*8
+		rootSize               = 32
+		pendingAttestationSize = 256
+		baseSize               = 1024
+	)
+	cfg := params.BeaconConfig()
+	size := uint64(baseSize)
+	size += uint64(st.NumValidators()) * validatorSize
+	size += uint64(st.BalancesLength()) * 8
+	size += uint64(st.RandaoMixesLength()) * rootSize
+	size += 2 * cfg.SlotsPerHistoricalRoot * rootSize
+	size += uint64(len(st.HistoricalRoots())) * rootSize
+	size += cfg.EpochsPerSlashingsVector * 8
+	size += 2 * cfg.MaxAttestations * cfg.SlotsPerEpoch * pendingAttestationSize
+	return size
 }
@@ -1,6 +1,8 @@
 package cache_test
 
 import (
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 
@@ -44,3 +46,81 @@ func TestHotStateCache_RoundTrip(t *testing.T) {
 		t.Error("Cache not suppose to have the object")
 	}
 }
+
+func TestHotStateCache_EvictsAndSpillsToDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hotstates")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	// Only the most recently used state fits in memory.
+	c, err := cache.NewHotStateCacheWithConfig(&cache.HotStateCacheConfig{
+		MaxSize:      1,
+		SpillDir:     dir,
+		MaxSpillSize: 1 << 20,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := [][32]byte{{'A'}, {'B'}}
+	for i, root := range roots {
+		state, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: uint64(i + 10)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Put(root, state)
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("Expected the evicted state to be spilled, received %d files", len(files))
+	}
+	if !c.Has(roots[0]) {
+		t.Fatal("Expected the spilled state in the cache")
+	}
+
+	// Loading the spilled state moves it back to memory and spills the other state.
+	res := c.Get(roots[0])
+	if res == nil || res.Slot() != 10 {
+		t.Fatalf("Expected the spilled state of slot 10, received %v", res)
+	}
+	if !c.Has(roots[1]) {
+		t.Error("Expected the state evicted by the load in the cache")
+	}
+	if !c.Delete(roots[1]) {
+		t.Error("Expected the spilled state to be deleted")
+	}
+	files, err = ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("Expected no spilled states, received %d files", len(files))
+	}
+}
+
+func TestHotStateCache_EvictsWithoutDisk(t *testing.T) {
+	c, err := cache.NewHotStateCacheWithConfig(&cache.HotStateCacheConfig{MaxSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, root := range [][32]byte{{'A'}, {'B'}} {
+		state, err := stateTrie.InitializeFromProto(&pb.BeaconState{Slot: uint64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Put(root, state)
+	}
+	if c.Has([32]byte{'A'}) {
+		t.Error("Expected the least recently used state to be evicted")
+	}
+	if !c.Has([32]byte{'B'}) {
+		t.Error("Expected the most recently used state to be kept")
+	}
+}
@@ -14,9 +14,13 @@ const (
 	Initialized
 	// Synced is sent when the beacon node has completed syncing and is ready to participate in the network.
 	Synced
-	// Reorg is an event sent when the new head state's slot after a block
-	// transition is lower than its previous head state slot value.
+	// Reorg is an event sent when the new head of the chain is not a descendant
+	// of the previous head.
 	Reorg
+	// NewHead is sent after the head of the chain has been updated.
+	NewHead
+	// FinalizedCheckpoint is sent after a new checkpoint has been finalized.
+	FinalizedCheckpoint
 )
 
 // BlockProcessedData is the data sent with BlockProcessed events.
@@ -55,4 +59,36 @@ type ReorgData struct {
 	NewSlot uint64
 	// OldSlot is the slot of the head state before the reorg.
 	OldSlot uint64
+	// NewHeadRoot is the block root of the head after the reorg.
+	NewHeadRoot [32]byte
+	// OldHeadRoot is the block root of the head before the reorg.
+	OldHeadRoot [32]byte
+	// CommonAncestorRoot is the block root of the most recent common ancestor of the old and new head.
+	CommonAncestorRoot [32]byte
+	// CommonAncestorSlot is the slot of the most recent common ancestor of the old and new head.
+	CommonAncestorSlot uint64
+	// Depth is the number of slots between the old head and the common ancestor.
+	Depth uint64
+}
+
+// NewHeadData is the data sent with NewHead events.
+type NewHeadData struct {
+	// Slot of the new head block.
+	Slot uint64
+	// BlockRoot of the new head block.
+	BlockRoot [32]byte
+	// StateRoot of the new head state.
+	StateRoot [32]byte
+	// EpochTransition is true if the new head is in a later epoch than the previous head.
+	EpochTransition bool
+}
+
+// FinalizedCheckpointData is the data sent with FinalizedCheckpoint events.
+type FinalizedCheckpointData struct {
+	// Epoch of the finalized checkpoint.
+	Epoch uint64
+	// BlockRoot of the finalized checkpoint.
+	BlockRoot [32]byte
+	// StateRoot of the finalized checkpoint block.
+	StateRoot [32]byte
 }
@@ -1,6 +1,9 @@
 package db
 
-import "github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+import (
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+)
 
 // ReadOnlyDatabase exposes Prysm's eth2 data backend for read access only, no information about
 // head info. For head info, use github.com/prysmaticlabs/prysm/blockchain.HeadFetcher.
@@ -22,3 +25,6 @@ type HeadAccessDatabase = iface.HeadAccessDatabase
 // key-value or relational database in practice. This is the full database interface which should
 // not be used often. Prefer a more restrictive interface in this package.
 type Database = iface.Database
+
+// Config for the database, such as the key-value engine to open it with.
+type Config = kv.Config
@@ -4,10 +4,16 @@ package db
 
 import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/export"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
 )
 
-// NewDB initializes a new DB.
-func NewDB(dirPath string, stateSummaryCache *cache.StateSummaryCache) (Database, error) {
-	return kv.NewKVStore(dirPath, stateSummaryCache)
+// NewDB initializes a new DB with the exporter.
+func NewDB(dirPath string, stateSummaryCache *cache.StateSummaryCache, cfg *Config) (Database, error) {
+	db, err := kv.NewKVStore(dirPath, stateSummaryCache, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return export.Wrap(db, export.DefaultSinks())
 }
@@ -4,16 +4,19 @@ package db
 
 import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/export"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/kafka"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
 )
 
-// NewDB initializes a new DB with kafka wrapper.
-func NewDB(dirPath string, stateSummaryCache *cache.StateSummaryCache) (Database, error) {
-	db, err := kv.NewKVStore(dirPath, stateSummaryCache)
+// NewDB initializes a new DB with the exporter, which supports the kafka sink.
+func NewDB(dirPath string, stateSummaryCache *cache.StateSummaryCache, cfg *Config) (Database, error) {
+	db, err := kv.NewKVStore(dirPath, stateSummaryCache, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	return kafka.Wrap(db)
+	sinks := export.DefaultSinks()
+	sinks["kafka"] = kafka.NewSink
+	return export.Wrap(db, sinks)
 }
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+)
+
+// ObjectType is a type of object saved in the database which can be exported.
+type ObjectType string
+
+const (
+	// Blocks are the signed beacon blocks.
+	Blocks ObjectType = kv.OutboxBlocks
+	// Attestations are the attestations.
+	Attestations ObjectType = kv.OutboxAttestations
+	// Slashings are the proposer and attester slashings.
+	Slashings ObjectType = kv.OutboxSlashings
+	// Exits are the voluntary exits.
+	Exits ObjectType = kv.OutboxExits
+	// States are the beacon states.
+	States ObjectType = kv.OutboxStates
+)
+
+var objectTypes = []ObjectType{Blocks, Attestations, Slashings, Exits, States}
+
+// SinkConfig routes the objects of the types to a sink.
+type SinkConfig struct {
+	Types []ObjectType
+	// Sink is the name of the sink, such as kafka, ndjson or webhook.
+	Sink string
+	// Target is the sink specific destination of the objects, such as a directory or an URL.
+	Target string
+}
+
+// ParseSinkConfig parses a sink config of the form <types>=<sink>:<target>, where the types
+// are a comma separated list of object types. For example
+// blocks,attestations=ndjson:/var/lib/export routes blocks and attestations to the
+// newline-delimited JSON files of the directory.
+func ParseSinkConfig(spec string) (*SinkConfig, error) {
+	parts := strings.SplitN(spec, "=", 2)
+	if len(parts) != 2 {
+		return nil, errors.Errorf("export sink %q is not of the form <types>=<sink>:<target>", spec)
+	}
+	sink := strings.SplitN(parts[1], ":", 2)
+	if len(sink) != 2 || sink[0] == "" || sink[1] == "" {
+		return nil, errors.Errorf("export sink %q is not of the form <types>=<sink>:<target>", spec)
+	}
+	cfg := &SinkConfig{Sink: sink[0], Target: sink[1]}
+	for _, name := range strings.Split(parts[0], ",") {
+		typ, err := parseObjectType(strings.TrimSpace(name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid export sink %q", spec)
+		}
+		cfg.Types = append(cfg.Types, typ)
+	}
+	return cfg, nil
+}
+
+func parseObjectType(name string) (ObjectType, error) {
+	for _, typ := range objectTypes {
+		if string(typ) == name {
+			return typ, nil
+		}
+	}
+	return "", errors.Errorf("unknown object type %q, expected one of %v", name, objectTypes)
+}
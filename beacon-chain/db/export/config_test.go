@@ -0,0 +1,41 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSinkConfig(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    *SinkConfig
+		wantErr bool
+	}{
+		{
+			spec: "blocks,attestations=ndjson:/var/lib/export?max-files=2",
+			want: &SinkConfig{Types: []ObjectType{Blocks, Attestations}, Sink: "ndjson", Target: "/var/lib/export?max-files=2"},
+		},
+		{
+			spec: "slashings, exits=webhook:http://localhost:8080/events",
+			want: &SinkConfig{Types: []ObjectType{Slashings, Exits}, Sink: "webhook", Target: "http://localhost:8080/events"},
+		},
+		{
+			spec: "states=kafka:localhost:9092",
+			want: &SinkConfig{Types: []ObjectType{States}, Sink: "kafka", Target: "localhost:9092"},
+		},
+		{spec: "ndjson:/var/lib/export", wantErr: true},
+		{spec: "blocks=ndjson", wantErr: true},
+		{spec: "blocks=ndjson:", wantErr: true},
+		{spec: "headers=ndjson:/var/lib/export", wantErr: true},
+	}
+	for _, tt := range tests {
+		cfg, err := ParseSinkConfig(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, received %v", tt.spec, tt.wantErr, err)
+			continue
+		}
+		if !reflect.DeepEqual(cfg, tt.want) {
+			t.Errorf("%s: expected %+v, received %+v", tt.spec, tt.want, cfg)
+		}
+	}
+}
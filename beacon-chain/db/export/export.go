@@ -0,0 +1,292 @@
+// Package export defines an implementation of Database interface which exports the objects
+// saved in the database to pluggable sinks, such as Kafka, newline-delimited JSON files or
+// an HTTP webhook, for data analysis.
+package export
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = iface.Database(&Exporter{})
+var log = logrus.WithField("prefix", "exporter")
+
+var (
+	// batchSize is the number of outbox entries read at once. The offset of a sink is saved
+	// after every batch.
+	batchSize = 256
+	// pollInterval is the interval at which the outbox is checked for new entries once all
+	// entries have been delivered.
+	pollInterval = 500 * time.Millisecond
+	// minRetryDelay and maxRetryDelay bound the exponential backoff of the delivery retries.
+	minRetryDelay = time.Second
+	maxRetryDelay = time.Minute
+	// Metrics
+	exportedRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_export_records_total",
+		Help: "The number of records exported to the sinks.",
+	}, []string{"sink", "type"})
+	failedRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_export_failures_total",
+		Help: "The number of failed attempts to export a record to the sinks.",
+	}, []string{"sink", "type"})
+	exportOffset = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "db_export_offset",
+		Help: "The offset of the next outbox entry to export to the sinks.",
+	}, []string{"sink"})
+)
+
+// Record is an object saved in the database, to be exported.
+type Record struct {
+	Type ObjectType
+	// Key identifies the object, it is the root the object is saved under, or the hash tree
+	// root of attestations.
+	Key     []byte
+	Message proto.Message
+}
+
+// Sink exports records to an external system. The records of a sink are exported in order
+// from a single goroutine.
+type Sink interface {
+	Export(ctx context.Context, record *Record) error
+	Close() error
+}
+
+// SinkFactory creates a sink from the target of its config, such as a directory or an URL.
+type SinkFactory func(target string) (Sink, error)
+
+// DefaultSinks returns the factories of the sinks which do not require external libraries.
+func DefaultSinks() map[string]SinkFactory {
+	return map[string]SinkFactory{
+		"ndjson":  NewNDJSONSink,
+		"webhook": NewWebhookSink,
+	}
+}
+
+// Outbox is the durable log of saved objects the exporter delivers from. The objects are
+// recorded in the same transaction as they are saved, so that none is lost.
+type Outbox interface {
+	EnableOutbox(topics []string)
+	OutboxEntries(ctx context.Context, offset uint64, limit int) ([]*kv.OutboxEntry, error)
+	OutboxOffset(ctx context.Context, consumer string) (uint64, error)
+	SaveOutboxOffset(ctx context.Context, consumer string, offset uint64) error
+	PruneOutbox(ctx context.Context, offset uint64) error
+}
+
+// Exporter wraps a database interface and exports the saved objects to the sinks configured for
+// their type. The database records the objects in its outbox, which every sink delivers from in
+// the background. A record which cannot be exported is retried until it is, and delivery
+// resumes from the last acknowledged offset of the sink after a restart, so that records are
+// exported at least once and in order.
+type Exporter struct {
+	db      iface.Database
+	outbox  Outbox
+	workers []*sinkWorker
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      *sync.WaitGroup
+	lock    sync.Mutex
+}
+
+// sinkWorker delivers the outbox entries of its object types to a sink.
+type sinkWorker struct {
+	name string
+	// consumer identifies the sink in the outbox, to save its offset.
+	consumer string
+	sink     Sink
+	types    map[ObjectType]bool
+	// offset is the offset of the next outbox entry to deliver. It is guarded by the lock of
+	// the exporter.
+	offset uint64
+}
+
+// Wrap the db with the exporter configured by the export sink feature flags. The Kafka
+// bootstrap servers feature flag exports blocks and attestations to Kafka. If no sinks are
+// configured, this does not wrap the database, but returns the underlying database itself.
+func Wrap(db iface.Database, factories map[string]SinkFactory) (iface.Database, error) {
+	specs := featureconfig.Get().ExportSinks
+	if servers := featureconfig.Get().KafkaBootstrapServers; servers != "" {
+		if _, ok := factories["kafka"]; ok {
+			specs = append(specs, "blocks,attestations=kafka:"+servers)
+		} else {
+			log.Warn("Kafka bootstrap servers are ignored, the beacon node was built without Kafka support")
+		}
+	}
+	if len(specs) == 0 {
+		log.Debug("No export sinks configured, database was not wrapped with exporter")
+		return db, nil
+	}
+	cfgs := make([]*SinkConfig, len(specs))
+	for i, spec := range specs {
+		cfg, err := ParseSinkConfig(spec)
+		if err != nil {
+			return nil, err
+		}
+		cfgs[i] = cfg
+	}
+	return NewExporter(db, cfgs, factories)
+}
+
+// NewExporter wraps the db with an exporter to the sinks of the configs, created by the
+// factory of their sink name. The db must implement the outbox.
+func NewExporter(db iface.Database, cfgs []*SinkConfig, factories map[string]SinkFactory) (*Exporter, error) {
+	outbox, ok := db.(Outbox)
+	if !ok {
+		return nil, errors.New("database does not support exporting")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	e := &Exporter{
+		db:     db,
+		outbox: outbox,
+		ctx:    ctx,
+		cancel: cancel,
+		wg:     &sync.WaitGroup{},
+	}
+	var topics []string
+	for _, cfg := range cfgs {
+		factory, ok := factories[cfg.Sink]
+		if !ok {
+			e.closeSinks()
+			return nil, errors.Errorf("unknown export sink %q", cfg.Sink)
+		}
+		consumer := cfg.Sink + ":" + cfg.Target
+		offset, err := outbox.OutboxOffset(ctx, consumer)
+		if err != nil {
+			e.closeSinks()
+			return nil, errors.Wrapf(err, "could not read offset of %s export sink", cfg.Sink)
+		}
+		sink, err := factory(cfg.Target)
+		if err != nil {
+			e.closeSinks()
+			return nil, errors.Wrapf(err, "could not create %s export sink", cfg.Sink)
+		}
+		w := &sinkWorker{
+			name:     cfg.Sink,
+			consumer: consumer,
+			sink:     sink,
+			types:    make(map[ObjectType]bool),
+			offset:   offset,
+		}
+		for _, typ := range cfg.Types {
+			w.types[typ] = true
+			topics = append(topics, string(typ))
+		}
+		e.workers = append(e.workers, w)
+	}
+	outbox.EnableOutbox(topics)
+	for _, w := range e.workers {
+		e.wg.Add(1)
+		go e.run(w)
+	}
+	return e, nil
+}
+
+// run delivers the outbox entries to the sink until the exporter is closed.
+func (e *Exporter) run(w *sinkWorker) {
+	defer e.wg.Done()
+	e.lock.Lock()
+	offset := w.offset
+	e.lock.Unlock()
+	for {
+		entries, err := e.outbox.OutboxEntries(e.ctx, offset, batchSize)
+		if err != nil {
+			log.WithError(err).WithField("sink", w.name).Error("Failed to read outbox")
+		}
+		if len(entries) == 0 {
+			select {
+			case <-e.ctx.Done():
+				return
+			case <-time.After(pollInterval):
+				continue
+			}
+		}
+		for _, entry := range entries {
+			typ := ObjectType(entry.Topic)
+			if w.types[typ] {
+				record := &Record{Type: typ, Key: entry.Key, Message: entry.Message}
+				if !e.deliver(w, record) {
+					return
+				}
+			}
+			offset = entry.Offset + 1
+		}
+		if err := e.acknowledge(w, offset); err != nil {
+			log.WithError(err).WithField("sink", w.name).Error("Failed to save export offset")
+		}
+	}
+}
+
+// deliver exports the record to the sink, retrying with an exponential backoff until it is
+// exported. It returns false if the exporter is closed first.
+func (e *Exporter) deliver(w *sinkWorker, record *Record) bool {
+	delay := minRetryDelay
+	for {
+		err := w.sink.Export(e.ctx, record)
+		if err == nil {
+			exportedRecords.WithLabelValues(w.name, string(record.Type)).Inc()
+			return true
+		}
+		failedRecords.WithLabelValues(w.name, string(record.Type)).Inc()
+		log.WithError(err).WithFields(logrus.Fields{
+			"sink":  w.name,
+			"type":  record.Type,
+			"retry": delay,
+		}).Error("Failed to export record")
+		select {
+		case <-e.ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
+
+// acknowledge saves the offset of the sink, and prunes the outbox entries delivered to all
+// sinks.
+func (e *Exporter) acknowledge(w *sinkWorker, offset uint64) error {
+	if err := e.outbox.SaveOutboxOffset(e.ctx, w.consumer, offset); err != nil {
+		return err
+	}
+	exportOffset.WithLabelValues(w.name).Set(float64(offset))
+
+	e.lock.Lock()
+	w.offset = offset
+	delivered := offset
+	for _, other := range e.workers {
+		if other.offset < delivered {
+			delivered = other.offset
+		}
+	}
+	e.lock.Unlock()
+	return e.outbox.PruneOutbox(e.ctx, delivered)
+}
+
+// Close stops the delivery, closes the sinks and the underlying db. The entries which have not
+// been delivered are kept in the outbox, and delivered after a restart.
+func (e *Exporter) Close() error {
+	e.cancel()
+	e.wg.Wait()
+	e.closeSinks()
+	return e.db.Close()
+}
+
+func (e *Exporter) closeSinks() {
+	for _, w := range e.workers {
+		if err := w.sink.Close(); err != nil {
+			log.WithError(err).WithField("sink", w.name).Error("Failed to close export sink")
+		}
+	}
+}
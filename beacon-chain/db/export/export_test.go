@@ -0,0 +1,212 @@
+package export
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+// mockSink records the exported records. It fails the first failures exports.
+type mockSink struct {
+	lock     sync.Mutex
+	records  []*Record
+	failures int
+	attempts int
+	closed   bool
+}
+
+func (s *mockSink) Export(_ context.Context, record *Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("unavailable")
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+// received returns the records, once at least n are exported.
+func (s *mockSink) received(t *testing.T, n int) []*Record {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.lock.Lock()
+		records := s.records
+		s.lock.Unlock()
+		if len(records) >= n {
+			return records
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d exported records, received %d", n, len(records))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (s *mockSink) Close() error {
+	s.closed = true
+	return nil
+}
+
+func TestExporter_ExportsSavedObjects(t *testing.T) {
+	db, dir := setupDB(t)
+	defer teardownDB(t, dir)
+	defer speedUp()()
+
+	blockSink, stateSink := &mockSink{}, &mockSink{}
+	factories := map[string]SinkFactory{
+		"blocks": func(string) (Sink, error) { return blockSink, nil },
+		"states": func(string) (Sink, error) { return stateSink, nil },
+	}
+	cfgs := []*SinkConfig{
+		{Types: []ObjectType{Blocks, Exits}, Sink: "blocks", Target: "a"},
+		{Types: []ObjectType{States}, Sink: "states", Target: "b"},
+	}
+	if _, err := NewExporter(db, []*SinkConfig{{Types: []ObjectType{Blocks}, Sink: "kafka"}}, factories); err == nil {
+		t.Error("Expected an unknown sink to fail")
+	}
+	e, err := NewExporter(db, cfgs, factories)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	blks := []*ethpb.SignedBeaconBlock{
+		{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: make([]byte, 32)}},
+		{Block: &ethpb.BeaconBlock{Slot: 2, ParentRoot: make([]byte, 32)}},
+	}
+	if err := e.SaveBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SaveVoluntaryExit(ctx, &ethpb.VoluntaryExit{Epoch: 1}); err != nil {
+		t.Fatal(err)
+	}
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: bitfield.Bitlist{0b00000001, 0b1},
+	}
+	if err := e.SaveAttestation(ctx, att); err != nil {
+		t.Fatal(err)
+	}
+	st := testutil.NewBeaconState()
+	if err := e.SaveState(ctx, st, [32]byte{'r'}); err != nil {
+		t.Fatal(err)
+	}
+	if !e.HasState(ctx, [32]byte{'r'}) {
+		t.Error("Expected the state to be saved")
+	}
+
+	records := blockSink.received(t, 3)
+	if len(records) != 3 {
+		t.Fatalf("Expected 2 blocks and 1 exit, received %d records", len(records))
+	}
+	for i, typ := range []ObjectType{Blocks, Blocks, Exits} {
+		if records[i].Type != typ || len(records[i].Key) != 32 {
+			t.Errorf("Expected record %d of type %s with a key, received %+v", i, typ, records[i])
+		}
+	}
+	records = stateSink.received(t, 1)
+	if len(records) != 1 || records[0].Type != States || records[0].Key[0] != 'r' {
+		t.Errorf("Expected the state keyed by its block root, received %+v", records)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !blockSink.closed || !stateSink.closed {
+		t.Error("Expected the sinks to be closed")
+	}
+}
+
+func TestExporter_RetriesAndResumesAfterRestart(t *testing.T) {
+	db, dir := setupDB(t)
+	defer teardownDB(t, dir)
+	defer speedUp()()
+
+	sink := &mockSink{failures: 2}
+	factories := map[string]SinkFactory{
+		"mock": func(string) (Sink, error) { return sink, nil },
+	}
+	cfgs := []*SinkConfig{{Types: []ObjectType{Exits}, Sink: "mock", Target: "a"}}
+	e, err := NewExporter(db, cfgs, factories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := e.SaveVoluntaryExit(ctx, &ethpb.VoluntaryExit{Epoch: 1}); err != nil {
+		t.Fatal(err)
+	}
+	records := sink.received(t, 1)
+	sink.lock.Lock()
+	attempts := sink.attempts
+	sink.lock.Unlock()
+	if len(records) != 1 || attempts != 3 {
+		t.Fatalf("Expected the exit to be exported after 2 retries, received %d records in %d attempts", len(records), attempts)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The exported exit is not exported again after a restart, but the exit saved while the
+	// exporter was down is.
+	db, err = kv.NewKVStore(dir, cache.NewStateSummaryCache(), &kv.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.EnableOutbox([]string{string(Exits)})
+	if err := db.SaveVoluntaryExit(ctx, &ethpb.VoluntaryExit{Epoch: 2}); err != nil {
+		t.Fatal(err)
+	}
+	sink = &mockSink{}
+	e, err = NewExporter(db, cfgs, factories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records = sink.received(t, 1)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Message.(*ethpb.VoluntaryExit).Epoch != 2 {
+		t.Errorf("Expected only the exit of epoch 2 to be exported, received %+v", records)
+	}
+}
+
+func setupDB(t *testing.T) (*kv.Store, string) {
+	dir, err := ioutil.TempDir("", "exportdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := kv.NewKVStore(dir, cache.NewStateSummaryCache(), &kv.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, dir
+}
+
+func teardownDB(t *testing.T, dir string) {
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// speedUp shortens the polling and retry intervals, and returns a function to restore them.
+func speedUp() func() {
+	interval, delay := pollInterval, minRetryDelay
+	pollInterval, minRetryDelay = 10*time.Millisecond, 10*time.Millisecond
+	return func() {
+		pollInterval, minRetryDelay = interval, delay
+	}
+}
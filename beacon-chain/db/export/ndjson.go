@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/pkg/errors"
+)
+
+var (
+	// defaultMaxFileSize is the size in megabytes at which the file of an object type is rotated.
+	defaultMaxFileSize = 100
+	// defaultMaxFiles is the number of rotated files kept for every object type.
+	defaultMaxFiles = 10
+	marshaler       = &jsonpb.Marshaler{}
+)
+
+// jsonRecord is the JSON encoding of a record.
+type jsonRecord struct {
+	Type        ObjectType      `json:"type"`
+	Key         string          `json:"key"`
+	MessageType string          `json:"message_type"`
+	Message     json.RawMessage `json:"message"`
+}
+
+// marshalRecord returns the JSON encoding of the record.
+func marshalRecord(record *Record) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, record.Message); err != nil {
+		return nil, err
+	}
+	return json.Marshal(&jsonRecord{
+		Type:        record.Type,
+		Key:         "0x" + hex.EncodeToString(record.Key),
+		MessageType: reflect.TypeOf(record.Message).Elem().Name(),
+		Message:     buf.Bytes(),
+	})
+}
+
+// NDJSONSink writes the records of every object type to a newline-delimited JSON file named
+// after the type, such as blocks.ndjson. A file is rotated once it reaches the max size, and
+// only the most recent rotated files are kept.
+type NDJSONSink struct {
+	dir      string
+	maxSize  int64
+	maxFiles int
+	files    map[ObjectType]*os.File
+	sizes    map[ObjectType]int64
+}
+
+// NewNDJSONSink creates a sink which writes to the files of the directory of the target. The
+// target may set the max file size in megabytes and the number of rotated files to keep, as in
+// /var/lib/export?max-size-mb=100&max-files=10.
+func NewNDJSONSink(target string) (Sink, error) {
+	dir, query := target, ""
+	if i := strings.LastIndex(target, "?"); i >= 0 {
+		dir, query = target[:i], target[i+1:]
+	}
+	opts, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid ndjson sink options")
+	}
+	maxSize, err := intOption(opts, "max-size-mb", defaultMaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	maxFiles, err := intOption(opts, "max-files", defaultMaxFiles)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create export directory")
+	}
+	return &NDJSONSink{
+		dir:      dir,
+		maxSize:  int64(maxSize) << 20,
+		maxFiles: maxFiles,
+		files:    make(map[ObjectType]*os.File),
+		sizes:    make(map[ObjectType]int64),
+	}, nil
+}
+
+func intOption(opts url.Values, name string, defaultValue int) (int, error) {
+	v := opts.Get(name)
+	if v == "" {
+		return defaultValue, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 {
+		return 0, errors.Errorf("ndjson sink option %s must be a positive integer, received %q", name, v)
+	}
+	return i, nil
+}
+
+// Export appends the record to the file of its type.
+func (s *NDJSONSink) Export(_ context.Context, record *Record) error {
+	line, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+	f, err := s.file(record.Type, int64(len(line)))
+	if err != nil {
+		return err
+	}
+	n, err := f.Write(line)
+	s.sizes[record.Type] += int64(n)
+	return err
+}
+
+// file returns the file of the object type, rotating it if the line does not fit.
+func (s *NDJSONSink) file(typ ObjectType, lineSize int64) (*os.File, error) {
+	f, ok := s.files[typ]
+	if ok && s.sizes[typ] > 0 && s.sizes[typ]+lineSize > s.maxSize {
+		if err := s.rotate(typ); err != nil {
+			return nil, err
+		}
+		ok = false
+	}
+	if ok {
+		return f, nil
+	}
+	f, err := os.OpenFile(s.path(typ), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Failed to close export file")
+		}
+		return nil, err
+	}
+	s.files[typ] = f
+	s.sizes[typ] = info.Size()
+	return f, nil
+}
+
+// rotate renames the file of the object type after the current time, and deletes the oldest
+// rotated files beyond the number of files to keep.
+func (s *NDJSONSink) rotate(typ ObjectType) error {
+	if err := s.files[typ].Close(); err != nil {
+		return err
+	}
+	delete(s.files, typ)
+	rotated := filepath.Join(s.dir, fmt.Sprintf("%s-%s.ndjson", typ, time.Now().UTC().Format("20060102T150405.000000000")))
+	if err := os.Rename(s.path(typ), rotated); err != nil {
+		return err
+	}
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, f := range files {
+		if strings.HasPrefix(f.Name(), string(typ)+"-") && strings.HasSuffix(f.Name(), ".ndjson") {
+			names = append(names, f.Name())
+		}
+	}
+	// The names sort in rotation order.
+	sort.Strings(names)
+	for len(names) > s.maxFiles {
+		if err := os.Remove(filepath.Join(s.dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
+
+func (s *NDJSONSink) path(typ ObjectType) string {
+	return filepath.Join(s.dir, string(typ)+".ndjson")
+}
+
+// Close closes the files.
+func (s *NDJSONSink) Close() error {
+	var firstErr error
+	for typ, f := range s.files {
+		if err := f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(s.files, typ)
+	}
+	return firstErr
+}
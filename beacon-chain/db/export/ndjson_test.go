@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func TestNDJSONSink_RotatesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	sink, err := NewNDJSONSink(dir + "?max-files=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := sink.(*NDJSONSink)
+	// Every file holds a single record.
+	s.maxSize = 1
+	ctx := context.Background()
+	for slot := uint64(0); slot < 5; slot++ {
+		record := &Record{
+			Type:    Blocks,
+			Key:     []byte{byte(slot)},
+			Message: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: slot}},
+		}
+		if err := sink.Export(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The current file and the two most recent rotated files are kept.
+	if len(files) != 3 {
+		t.Fatalf("Expected 3 files, received %d", len(files))
+	}
+	f, err := os.Open(filepath.Join(dir, "blocks.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		t.Fatal("Expected a record in the current file")
+	}
+	record := &jsonRecord{}
+	if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+		t.Fatal(err)
+	}
+	if record.Type != Blocks || record.Key != "0x04" || record.MessageType != "SignedBeaconBlock" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if scanner.Scan() {
+		t.Error("Expected a single record in the current file")
+	}
+}
+
+func TestNewNDJSONSink_InvalidOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if _, err := NewNDJSONSink(dir + "?max-size-mb=0"); err == nil {
+		t.Error("Expected a max size of 0 to fail")
+	}
+	if _, err := NewNDJSONSink(dir + "?max-files=many"); err == nil {
+		t.Error("Expected a non numeric file count to fail")
+	}
+}
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// webhookTimeout is the timeout of a webhook request.
+var webhookTimeout = 10 * time.Second
+
+// WebhookSink posts every record as JSON to an HTTP endpoint.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink which posts the records to the URL of the target.
+func NewWebhookSink(target string) (Sink, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid webhook URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("webhook URL %q is not an http or https URL", target)
+	}
+	return &WebhookSink{
+		url:    target,
+		client: &http.Client{Timeout: webhookTimeout},
+	}, nil
+}
+
+// Export posts the record to the webhook. Responses other than 2xx are errors.
+func (s *WebhookSink) Export(ctx context.Context, record *Record) error {
+	body, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Error("Failed to close webhook response body")
+		}
+	}()
+	// Read the body so that the connection can be reused.
+	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close closes the idle connections to the webhook.
+func (s *WebhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func TestWebhookSink_PostsRecords(t *testing.T) {
+	var received []*jsonRecord
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Unexpected %s request with content type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		record := &jsonRecord{}
+		if err := json.NewDecoder(r.Body).Decode(record); err != nil {
+			t.Error(err)
+		}
+		received = append(received, record)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	sink, err := NewWebhookSink(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := &Record{Type: Exits, Key: []byte{'a'}, Message: &ethpb.VoluntaryExit{Epoch: 2, ValidatorIndex: 3}}
+	if err := sink.Export(context.Background(), record); err != nil {
+		t.Fatal(err)
+	}
+	if len(received) != 1 || received[0].Type != Exits || received[0].Key != "0x61" {
+		t.Fatalf("Unexpected records %+v", received)
+	}
+
+	status = http.StatusInternalServerError
+	if err := sink.Export(context.Background(), record); err == nil {
+		t.Error("Expected an error response to fail the export")
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewWebhookSink_InvalidURL(t *testing.T) {
+	if _, err := NewWebhookSink("localhost:8080"); err == nil {
+		t.Error("Expected an URL without scheme to fail")
+	}
+}
@@ -0,0 +1,72 @@
+// Package kafka defines an export sink which streams the objects saved in the database to
+// Kafka topics for data analysis.
+package kafka
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/export"
+	"github.com/prysmaticlabs/prysm/shared/traceutil"
+	"go.opencensus.io/trace"
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	_ "gopkg.in/confluentinc/confluent-kafka-go.v1/kafka/librdkafka" // Required for c++ kafka library.
+)
+
+var _ = export.Sink(&Sink{})
+var marshaler = &jsonpb.Marshaler{}
+
+// topics are the Kafka topics of the exported object types.
+var topics = map[export.ObjectType]string{
+	export.Blocks:       "beacon_block",
+	export.Attestations: "beacon_attestation",
+	export.Slashings:    "beacon_slashing",
+	export.Exits:        "beacon_voluntary_exit",
+	export.States:       "beacon_state",
+}
+
+// Sink publishes the exported records to the Kafka topic of their type.
+type Sink struct {
+	p *kafka.Producer
+}
+
+// NewSink creates a sink which publishes to the Kafka bootstrap servers of the target.
+func NewSink(target string) (export.Sink, error) {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{"bootstrap.servers": target})
+	if err != nil {
+		return nil, err
+	}
+	return &Sink{p: p}, nil
+}
+
+// Export publishes the record as JSON, keyed by the record key.
+func (s *Sink) Export(ctx context.Context, record *export.Record) error {
+	ctx, span := trace.StartSpan(ctx, "kafka.publish")
+	defer span.End()
+
+	buf := bytes.NewBuffer(nil)
+	if err := marshaler.Marshal(buf, record.Message); err != nil {
+		traceutil.AnnotateError(span, err)
+		return err
+	}
+
+	topic := topics[record.Type]
+	if err := s.p.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{
+			Topic: &topic,
+		},
+		Value: buf.Bytes(),
+		Key:   record.Key,
+	}, nil); err != nil {
+		traceutil.AnnotateError(span, err)
+		return err
+	}
+	return nil
+}
+
+// Close closes the kafka producer.
+func (s *Sink) Close() error {
+	s.p.Close()
+	return nil
+}
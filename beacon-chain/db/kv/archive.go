@@ -7,7 +7,6 @@ import (
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -18,7 +17,7 @@ func (kv *Store) ArchivedActiveValidatorChanges(ctx context.Context, epoch uint6
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *pb.ArchivedActiveSetChanges
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(archivedValidatorSetChangesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -39,7 +38,7 @@ func (kv *Store) SaveArchivedActiveValidatorChanges(ctx context.Context, epoch u
 	if err != nil {
 		return err
 	}
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedValidatorSetChangesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -52,7 +51,7 @@ func (kv *Store) ArchivedCommitteeInfo(ctx context.Context, epoch uint64) (*pb.A
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *pb.ArchivedCommitteeInfo
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(archivedCommitteeInfoBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -73,7 +72,7 @@ func (kv *Store) SaveArchivedCommitteeInfo(ctx context.Context, epoch uint64, in
 	if err != nil {
 		return err
 	}
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedCommitteeInfoBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -86,7 +85,7 @@ func (kv *Store) ArchivedBalances(ctx context.Context, epoch uint64) ([]uint64,
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target []uint64
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(archivedBalancesBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -104,7 +103,7 @@ func (kv *Store) SaveArchivedBalances(ctx context.Context, epoch uint64, balance
 	defer span.End()
 	buf := bytesutil.Uint64ToBytes(epoch)
 	enc := marshalBalances(ctx, balances)
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedBalancesBucket)
 		return bucket.Put(buf, enc)
 	})
@@ -117,7 +116,7 @@ func (kv *Store) ArchivedValidatorParticipation(ctx context.Context, epoch uint6
 
 	buf := bytesutil.Uint64ToBytes(epoch)
 	var target *ethpb.ValidatorParticipation
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(archivedValidatorParticipationBucket)
 		enc := bkt.Get(buf)
 		if enc == nil {
@@ -138,7 +137,7 @@ func (kv *Store) SaveArchivedValidatorParticipation(ctx context.Context, epoch u
 	if err != nil {
 		return err
 	}
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedValidatorParticipationBucket)
 		return bucket.Put(buf, enc)
 	})
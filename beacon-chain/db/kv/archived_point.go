@@ -5,7 +5,6 @@ import (
 	"encoding/binary"
 
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -14,7 +13,7 @@ func (kv *Store) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte,
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveArchivedPointRoot")
 	defer span.End()
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		return bucket.Put(bytesutil.Uint64ToBytes(index), blockRoot[:])
 	})
@@ -24,7 +23,7 @@ func (kv *Store) SaveArchivedPointRoot(ctx context.Context, blockRoot [32]byte,
 func (kv *Store) SaveLastArchivedIndex(ctx context.Context, index uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveLastArchivedIndex")
 	defer span.End()
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		return bucket.Put(lastArchivedIndexKey, bytesutil.Uint64ToBytes(index))
 	})
@@ -35,7 +34,7 @@ func (kv *Store) LastArchivedIndex(ctx context.Context) (uint64, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.LastArchivedIndex")
 	defer span.End()
 	var index uint64
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		b := bucket.Get(lastArchivedIndexKey)
 		if b == nil {
@@ -54,7 +53,7 @@ func (kv *Store) LastArchivedIndexRoot(ctx context.Context) [32]byte {
 	defer span.End()
 
 	var blockRoot []byte
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		lastArchivedIndex := bucket.Get(lastArchivedIndexKey)
 		if lastArchivedIndex == nil {
@@ -76,7 +75,7 @@ func (kv *Store) ArchivedPointRoot(ctx context.Context, index uint64) [32]byte {
 	defer span.End()
 
 	var blockRoot []byte
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bucket := tx.Bucket(archivedIndexRootBucket)
 		blockRoot = bucket.Get(bytesutil.Uint64ToBytes(index))
 		return nil
@@ -92,7 +91,7 @@ func (kv *Store) HasArchivedPoint(ctx context.Context, index uint64) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasArchivedPoint")
 	defer span.End()
 	var exists bool
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		iBucket := tx.Bucket(archivedIndexRootBucket)
 		exists = iBucket.Get(bytesutil.Uint64ToBytes(index)) != nil
 		return nil
@@ -12,7 +12,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
 	"github.com/prysmaticlabs/prysm/shared/traceutil"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -21,7 +20,7 @@ func (kv *Store) AttestationsByDataRoot(ctx context.Context, attDataRoot [32]byt
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Attestation")
 	defer span.End()
 	var atts []*ethpb.Attestation
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 		enc := bkt.Get(attDataRoot[:])
 		if enc == nil {
@@ -45,7 +44,7 @@ func (kv *Store) Attestations(ctx context.Context, f *filters.QueryFilter) ([]*e
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Attestations")
 	defer span.End()
 	atts := make([]*ethpb.Attestation, 0)
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 
 		// If no filter criteria are specified, return an error.
@@ -83,7 +82,7 @@ func (kv *Store) HasAttestation(ctx context.Context, attDataRoot [32]byte) bool
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasAttestation")
 	defer span.End()
 	exists := false
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 		exists = bkt.Get(attDataRoot[:]) != nil
 		return nil
@@ -97,7 +96,7 @@ func (kv *Store) HasAttestation(ctx context.Context, attDataRoot [32]byte) bool
 func (kv *Store) DeleteAttestation(ctx context.Context, attDataRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteAttestation")
 	defer span.End()
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 		enc := bkt.Get(attDataRoot[:])
 		if enc == nil {
@@ -120,7 +119,7 @@ func (kv *Store) DeleteAttestations(ctx context.Context, attDataRoots [][32]byte
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteAttestations")
 	defer span.End()
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 		for _, attDataRoot := range attDataRoots {
 			enc := bkt.Get(attDataRoot[:])
@@ -158,7 +157,7 @@ func (kv *Store) SaveAttestation(ctx context.Context, att *ethpb.Attestation) er
 		return err
 	}
 
-	err = kv.db.Update(func(tx *bolt.Tx) error {
+	err = kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(attestationsBucket)
 		ac := &dbpb.AttestationContainer{
 			Data: att.Data,
@@ -181,6 +180,9 @@ func (kv *Store) SaveAttestation(ctx context.Context, att *ethpb.Attestation) er
 		if err := updateValueForIndices(ctx, indicesByBucket, attDataRoot[:], tx); err != nil {
 			return errors.Wrap(err, "could not update DB indices")
 		}
+		if err := kv.addAttestationToOutbox(tx, att); err != nil {
+			return err
+		}
 		return bkt.Put(attDataRoot[:], enc)
 	})
 	if err != nil {
@@ -194,7 +196,7 @@ func (kv *Store) SaveAttestations(ctx context.Context, atts []*ethpb.Attestation
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveAttestations")
 	defer span.End()
 
-	err := kv.db.Update(func(tx *bolt.Tx) error {
+	err := kv.db.Update(func(tx Tx) error {
 		for _, att := range atts {
 			attDataRoot, err := stateutil.AttestationDataRoot(att.Data)
 			if err != nil {
@@ -223,6 +225,9 @@ func (kv *Store) SaveAttestations(ctx context.Context, atts []*ethpb.Attestation
 			if err := updateValueForIndices(ctx, indicesByBucket, attDataRoot[:], tx); err != nil {
 				return errors.Wrap(err, "could not update DB indices")
 			}
+			if err := kv.addAttestationToOutbox(tx, att); err != nil {
+				return err
+			}
 
 			if err := bkt.Put(attDataRoot[:], enc); err != nil {
 				return err
@@ -1,63 +1,495 @@
 package kv
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
+	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
-	"github.com/prysmaticlabs/prysm/shared/params"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/sirupsen/logrus"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
-const backupsDirectoryName = "backups"
+const (
+	backupsDirectoryName = "backups"
+	backupFileSuffix     = ".backup"
+)
+
+// A backup is a bolt database file which, besides the backed up buckets, holds a
+// metadata bucket describing the backup. A full backup contains every bucket. An
+// incremental backup contains the keys changed since its parent, as recorded in the
+// change log, and the keys deleted since its parent in the deletions bucket.
+var (
+	backupMetadataBucket  = []byte("backup-metadata")
+	backupDeletionsBucket = []byte("backup-deletions")
+
+	backupSequenceKey  = []byte("sequence")
+	backupParentKey    = []byte("parent")
+	backupHeadSlotKey  = []byte("head-slot")
+	backupHeadRootKey  = []byte("head-root")
+	backupFinalizedKey = []byte("finalized-checkpoint")
+)
 
-// Backup the database to the datadir backup directory.
-// Example for backup at slot 345: $DATADIR/backups/prysm_beacondb_at_slot_0000345.backup
+// backupInfo describes a backup file.
+type backupInfo struct {
+	path     string
+	sequence uint64
+	// parent is the file name of the backup an incremental backup builds on, and empty
+	// for a full backup.
+	parent    string
+	headSlot  uint64
+	headRoot  []byte
+	finalized *ethpb.Checkpoint
+	// buckets contained in the backup file.
+	buckets map[string]bool
+}
+
+// Backup the database to the datadir backup directory. With incremental backups enabled,
+// only the keys changed since the previous backup are written, until the configured
+// number of incremental backups has been written and a full backup is written again.
+// After the backup, backups beyond the configured retention are removed.
+// Example for the third backup, at slot 345: $DATADIR/backups/prysm_beacondb_00003_at_slot_0000345.backup
 func (kv *Store) Backup(ctx context.Context) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Backup")
 	defer span.End()
 
+	kv.backupLock.Lock()
+	defer kv.backupLock.Unlock()
+
 	backupsDir := path.Join(kv.databasePath, backupsDirectoryName)
-	head, err := kv.HeadBlock(ctx)
+	// Ensure the backups directory exists.
+	if err := os.MkdirAll(backupsDir, os.ModePerm); err != nil {
+		return err
+	}
+	backups, err := listBackups(backupsDir)
 	if err != nil {
 		return err
 	}
-	if head == nil {
-		return errors.New("no head block")
+	var parent *backupInfo
+	sequence := uint64(1)
+	if len(backups) > 0 {
+		latest := backups[len(backups)-1]
+		sequence = latest.sequence + 1
+		if kv.cfg.IncrementalBackups && incrementalBackups(backups, latest) < kv.cfg.BackupFullInterval {
+			parent = latest
+		}
 	}
-	// Ensure the backups directory exists.
-	if err := os.MkdirAll(backupsDir, os.ModePerm); err != nil {
+
+	var backupName string
+	var counter uint64
+	err = kv.db.View(func(tx Tx) error {
+		changeLog := tx.Bucket(backupChangeLogBucket)
+		counter = changeLogCounter(changeLog)
+		// The change log can only be used if it is relative to the latest backup.
+		if parent != nil && string(changeLog.Get(changeLogLastBackupKey)) != path.Base(parent.path) {
+			parent = nil
+		}
+		if parent != nil && counter == 0 {
+			logrus.WithField("prefix", "db").Info("Database did not change since the previous backup, skipping backup")
+			return nil
+		}
+		headSlot, headRoot, finalized, err := backupHead(tx)
+		if err != nil {
+			return err
+		}
+
+		backupPath := path.Join(backupsDir, fmt.Sprintf("prysm_beacondb_%05d_at_slot_%07d%s", sequence, headSlot, backupFileSuffix))
+		logrus.WithField("prefix", "db").WithFields(logrus.Fields{
+			"backup":      backupPath,
+			"incremental": parent != nil,
+		}).Info("Writing backup database.")
+		// Remove the remains of an interrupted backup, which has no metadata and is
+		// therefore not listed.
+		if err := os.RemoveAll(backupPath); err != nil {
+			return err
+		}
+		copyDB, err := openBoltEngine(backupPath, false /* readOnly */)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := copyDB.Close(); err != nil {
+				logrus.WithError(err).Error("Failed to close destination database")
+			}
+		}()
+		if parent != nil {
+			err = copyChanges(tx, copyDB)
+		} else {
+			err = tx.ForEach(func(name []byte, b Bucket) error {
+				if bytes.Equal(name, backupChangeLogBucket) {
+					return nil
+				}
+				return copyBucket(name, b, copyDB)
+			})
+		}
+		if err != nil {
+			return err
+		}
+		// The metadata is written last, so that an interrupted backup is not used as parent.
+		if err := copyDB.Update(func(tx Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists(backupMetadataBucket)
+			if err != nil {
+				return err
+			}
+			if parent != nil {
+				if err := bkt.Put(backupParentKey, []byte(path.Base(parent.path))); err != nil {
+					return err
+				}
+			}
+			enc, err := encode(finalized)
+			if err != nil {
+				return err
+			}
+			if err := bkt.Put(backupFinalizedKey, enc); err != nil {
+				return err
+			}
+			if err := bkt.Put(backupHeadRootKey, headRoot); err != nil {
+				return err
+			}
+			if err := bkt.Put(backupHeadSlotKey, bytesutil.Bytes8(headSlot)); err != nil {
+				return err
+			}
+			return bkt.Put(backupSequenceKey, bytesutil.Bytes8(sequence))
+		}); err != nil {
+			return err
+		}
+		backupName = path.Base(backupPath)
+		return nil
+	})
+	if err != nil || backupName == "" {
 		return err
 	}
-	backupPath := path.Join(backupsDir, fmt.Sprintf("prysm_beacondb_at_slot_%07d.backup", head.Block.Slot))
-	logrus.WithField("prefix", "db").WithField("backup", backupPath).Info("Writing backup database.")
+	if kv.cfg.IncrementalBackups {
+		if err := kv.resetChangeLog(backupName, counter); err != nil {
+			return errors.Wrap(err, "could not reset change log")
+		}
+	}
+	return pruneBackups(backupsDir, kv.cfg.BackupRetention)
+}
+
+// copyChanges copies the keys recorded in the change log into the backup. Keys which
+// no longer exist are recorded in the deletions bucket of the backup.
+func copyChanges(tx Tx, dst Engine) error {
+	type change struct {
+		name, key, value []byte
+	}
+	var changes []change
+	flush := func() error {
+		if err := dst.Update(func(dstTx Tx) error {
+			for _, c := range changes {
+				if c.value == nil {
+					deletions, err := dstTx.CreateBucketIfNotExists(backupDeletionsBucket)
+					if err != nil {
+						return err
+					}
+					if err := deletions.Put(changeLogKey(c.name, c.key), []byte{}); err != nil {
+						return err
+					}
+					continue
+				}
+				bkt, err := dstTx.CreateBucketIfNotExists(c.name)
+				if err != nil {
+					return err
+				}
+				if err := bkt.Put(c.key, c.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		changes = changes[:0]
+		return nil
+	}
+	if err := tx.Bucket(backupChangeLogBucket).ForEach(func(k []byte, _ []byte) error {
+		name, key, ok := parseChangeLogKey(k)
+		if !ok {
+			return nil
+		}
+		bkt := tx.Bucket(name)
+		if bkt == nil {
+			return nil
+		}
+		changes = append(changes, change{name: name, key: key, value: bkt.Get(key)})
+		if len(changes) >= copyBatchSize {
+			return flush()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// resetChangeLog makes the change log relative to the backup, which contains all changes
+// up to the given transaction counter. Changes of later transactions are kept.
+func (kv *Store) resetChangeLog(backupName string, counter uint64) error {
+	return kv.db.Update(func(tx Tx) error {
+		changeLog := tx.Bucket(backupChangeLogBucket)
+		var backedUp [][]byte
+		if err := changeLog.ForEach(func(k []byte, v []byte) error {
+			if _, _, ok := parseChangeLogKey(k); ok && bytesutil.FromBytes8(v) <= counter {
+				backedUp = append(backedUp, bytesutil.SafeCopyBytes(k))
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range backedUp {
+			if err := changeLog.Delete(k); err != nil {
+				return err
+			}
+		}
+		if changeLogCounter(changeLog) == counter {
+			if err := changeLog.Delete(changeLogCounterKey); err != nil {
+				return err
+			}
+		}
+		return changeLog.Put(changeLogLastBackupKey, []byte(backupName))
+	})
+}
 
-	copyDB, err := bolt.Open(backupPath, params.BeaconIoConfig().ReadWritePermissions, nil)
+// backupHead returns the slot and root of the head block and the finalized checkpoint.
+func backupHead(tx Tx) (uint64, []byte, *ethpb.Checkpoint, error) {
+	blocks := tx.Bucket(blocksBucket)
+	headRoot := blocks.Get(headBlockRootKey)
+	if headRoot == nil {
+		return 0, nil, nil, errors.New("no head block")
+	}
+	enc := blocks.Get(headRoot)
+	if enc == nil {
+		return 0, nil, nil, errors.New("no head block")
+	}
+	head := &ethpb.SignedBeaconBlock{}
+	if err := decode(enc, head); err != nil {
+		return 0, nil, nil, err
+	}
+	finalized := &ethpb.Checkpoint{Root: blocks.Get(genesisBlockRootKey)}
+	if enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey); enc != nil {
+		if err := decode(enc, finalized); err != nil {
+			return 0, nil, nil, err
+		}
+	}
+	return head.Block.Slot, bytesutil.SafeCopyBytes(headRoot), finalized, nil
+}
+
+// readBackupInfo reads the metadata of the backup file.
+func readBackupInfo(backupPath string) (*backupInfo, error) {
+	if _, err := os.Stat(backupPath); err != nil {
+		return nil, errors.Wrap(err, "could not find backup")
+	}
+	engine, err := openBoltEngine(backupPath, true /* readOnly */)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
 	defer func() {
-		if err := copyDB.Close(); err != nil {
-			logrus.WithError(err).Error("Failed to close destination database")
+		if err := engine.Close(); err != nil {
+			logrus.WithError(err).Error("Failed to close backup database")
 		}
 	}()
+	info := &backupInfo{
+		path:    backupPath,
+		buckets: make(map[string]bool),
+	}
+	err = engine.View(func(tx Tx) error {
+		bkt := tx.Bucket(backupMetadataBucket)
+		if bkt == nil || bkt.Get(backupSequenceKey) == nil {
+			return errors.New("backup has no metadata, it was either interrupted or written by an older release")
+		}
+		info.sequence = bytesutil.FromBytes8(bkt.Get(backupSequenceKey))
+		info.parent = string(bkt.Get(backupParentKey))
+		info.headSlot = bytesutil.FromBytes8(bkt.Get(backupHeadSlotKey))
+		info.headRoot = bytesutil.SafeCopyBytes(bkt.Get(backupHeadRootKey))
+		info.finalized = &ethpb.Checkpoint{}
+		if err := decode(bkt.Get(backupFinalizedKey), info.finalized); err != nil {
+			return errors.Wrap(err, "could not decode finalized checkpoint")
+		}
+		return tx.ForEach(func(name []byte, _ Bucket) error {
+			if !bytes.Equal(name, backupMetadataBucket) && !bytes.Equal(name, backupDeletionsBucket) {
+				info.buckets[string(name)] = true
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read backup %s", backupPath)
+	}
+	return info, nil
+}
 
-	return kv.db.View(func(tx *bolt.Tx) error {
-		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
-			logrus.Debugf("Copying bucket %s\n", name)
-			return copyDB.Update(func(tx2 *bolt.Tx) error {
-				b2, err := tx2.CreateBucketIfNotExists(name)
-				if err != nil {
-					return err
+// listBackups returns the backups in the directory ordered by sequence. Files which
+// are not readable backups are skipped.
+func listBackups(backupsDir string) ([]*backupInfo, error) {
+	files, err := ioutil.ReadDir(backupsDir)
+	if err != nil {
+		return nil, err
+	}
+	var backups []*backupInfo
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), backupFileSuffix) {
+			continue
+		}
+		info, err := readBackupInfo(path.Join(backupsDir, f.Name()))
+		if err != nil {
+			logrus.WithError(err).WithField("backup", f.Name()).Debug("Skipping backup")
+			continue
+		}
+		backups = append(backups, info)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].sequence < backups[j].sequence
+	})
+	return backups, nil
+}
+
+// incrementalBackups returns the number of incremental backups in the chain of the
+// backup since its full backup.
+func incrementalBackups(backups []*backupInfo, backup *backupInfo) int {
+	byName := make(map[string]*backupInfo, len(backups))
+	for _, b := range backups {
+		byName[path.Base(b.path)] = b
+	}
+	count := 0
+	for b := backup; b != nil && b.parent != ""; b = byName[b.parent] {
+		count++
+	}
+	return count
+}
+
+// backupChain returns the chain of backups needed to restore the backup, starting
+// with its full backup. Parents are looked up in the directory of the backup.
+func backupChain(backup *backupInfo) ([]*backupInfo, error) {
+	chain := []*backupInfo{backup}
+	for b := backup; b.parent != ""; {
+		parent, err := readBackupInfo(path.Join(path.Dir(b.path), b.parent))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read parent of backup %s", path.Base(b.path))
+		}
+		if parent.sequence >= b.sequence {
+			return nil, errors.Errorf("backup %s is not older than its child %s", b.parent, path.Base(b.path))
+		}
+		chain = append([]*backupInfo{parent}, chain...)
+		b = parent
+	}
+	return chain, nil
+}
+
+// pruneBackups removes all backups except for the retained most recent backups and
+// the backups they build on. A retention of 0 keeps all backups.
+func pruneBackups(backupsDir string, retain int) error {
+	if retain <= 0 {
+		return nil
+	}
+	backups, err := listBackups(backupsDir)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= retain {
+		return nil
+	}
+	byName := make(map[string]*backupInfo, len(backups))
+	for _, b := range backups {
+		byName[path.Base(b.path)] = b
+	}
+	keep := make(map[string]bool)
+	for _, b := range backups[len(backups)-retain:] {
+		for ; b != nil && !keep[path.Base(b.path)]; b = byName[b.parent] {
+			keep[path.Base(b.path)] = true
+		}
+	}
+	for _, b := range backups {
+		if keep[path.Base(b.path)] {
+			continue
+		}
+		logrus.WithField("prefix", "db").WithField("backup", b.path).Info("Removing backup beyond retention")
+		if err := os.Remove(b.path); err != nil {
+			return errors.Wrap(err, "could not remove backup")
+		}
+	}
+	return nil
+}
+
+// restoreBackup writes the database described by the backup chain into the engine.
+// The backups are applied in order, starting with the full backup.
+func restoreBackup(chain []*backupInfo, dst Engine) error {
+	for _, b := range chain {
+		src, err := openBoltEngine(b.path, true /* readOnly */)
+		if err != nil {
+			return err
+		}
+		err = src.View(func(tx Tx) error {
+			if err := tx.ForEach(func(name []byte, bkt Bucket) error {
+				if bytes.Equal(name, backupMetadataBucket) || bytes.Equal(name, backupDeletionsBucket) {
+					return nil
 				}
-				return b.ForEach(func(k []byte, v []byte) error {
-					return b2.Put(k, v)
+				return copyBucket(name, bkt, dst)
+			}); err != nil {
+				return err
+			}
+			deletions := tx.Bucket(backupDeletionsBucket)
+			if deletions == nil {
+				return nil
+			}
+			return dst.Update(func(dstTx Tx) error {
+				return deletions.ForEach(func(k []byte, _ []byte) error {
+					name, key, ok := parseChangeLogKey(k)
+					if !ok {
+						return errors.Errorf("invalid deletion in backup %s", path.Base(b.path))
+					}
+					bkt, err := dstTx.CreateBucketIfNotExists(name)
+					if err != nil {
+						return err
+					}
+					return bkt.Delete(key)
 				})
 			})
 		})
+		if closeErr := src.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("Failed to close backup database")
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not apply backup %s", path.Base(b.path))
+		}
+	}
+	return nil
+}
+
+// validateRestored checks that the head block and finalized checkpoint of the restored
+// database are those recorded in the backup, and that they are consistent.
+func validateRestored(engine Engine, backup *backupInfo) error {
+	return engine.View(func(tx Tx) error {
+		headSlot, headRoot, finalized, err := backupHead(tx)
+		if err != nil {
+			return err
+		}
+		if headSlot != backup.headSlot || !bytes.Equal(headRoot, backup.headRoot) {
+			return errors.Errorf(
+				"restored head block at slot %d with root %#x, expected slot %d with root %#x",
+				headSlot,
+				headRoot,
+				backup.headSlot,
+				backup.headRoot,
+			)
+		}
+		if !proto.Equal(finalized, backup.finalized) {
+			return errors.Errorf("restored finalized checkpoint %v, expected %v", finalized, backup.finalized)
+		}
+		if helpers.StartSlot(finalized.Epoch) > headSlot {
+			return errors.Errorf("restored finalized epoch %d is after the head block at slot %d", finalized.Epoch, headSlot)
+		}
+		if finalized.Root != nil && tx.Bucket(blocksBucket).Get(finalized.Root) == nil {
+			return errors.Errorf("restored database has no block for the finalized root %#x", finalized.Root)
+		}
+		return nil
 	})
 }
@@ -3,11 +3,14 @@ package kv
 import (
 	"context"
 	"io/ioutil"
+	"os"
 	"path"
+	"strings"
 	"testing"
 
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
 )
 
@@ -44,3 +47,193 @@ func TestStore_Backup(t *testing.T) {
 		t.Fatal("No backups created.")
 	}
 }
+
+// saveHead saves a block at the slot and makes it the head block.
+func saveHead(t *testing.T, db *Store, slot uint64) [32]byte {
+	ctx := context.Background()
+	blk := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: slot}}
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateSummary(ctx, &pb.StateSummary{Slot: slot, Root: root[:]}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveHeadBlockRoot(ctx, root); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+func TestStore_Backup_Incremental(t *testing.T) {
+	db := setupDBWithConfig(t, &Config{IncrementalBackups: true, BackupFullInterval: 10})
+	ctx := context.Background()
+	backupsDir := path.Join(db.databasePath, backupsDirectoryName)
+
+	first := saveHead(t, db, 10)
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Nothing changed, so no backup is written.
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	second := saveHead(t, db, 20)
+	if err := db.db.Update(func(tx Tx) error {
+		return tx.Bucket(stateSummaryBucket).Delete(first[:])
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := listBackups(backupsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expected 2 backups, received %d", len(backups))
+	}
+	full, incremental := backups[0], backups[1]
+	if full.parent != "" || !full.buckets[string(powchainBucket)] {
+		t.Errorf("Expected a full backup, received parent %q with buckets %v", full.parent, full.buckets)
+	}
+	if incremental.parent != path.Base(full.path) {
+		t.Errorf("Expected incremental backup of %s, received parent %q", path.Base(full.path), incremental.parent)
+	}
+	if !incremental.buckets[string(blocksBucket)] || incremental.buckets[string(powchainBucket)] {
+		t.Errorf("Expected incremental backup to only contain the changed buckets, received %v", incremental.buckets)
+	}
+	engine, err := openBoltEngine(incremental.path, true /* readOnly */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.View(func(tx Tx) error {
+		if tx.Bucket(blocksBucket).Get(first[:]) != nil {
+			t.Error("Expected incremental backup to not contain the block of the full backup")
+		}
+		if tx.Bucket(blocksBucket).Get(second[:]) == nil {
+			t.Error("Expected incremental backup to contain the new block")
+		}
+		if tx.Bucket(backupDeletionsBucket).Get(changeLogKey(stateSummaryBucket, first[:])) == nil {
+			t.Error("Expected incremental backup to record the deleted state summary")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if incremental.headSlot != 20 {
+		t.Errorf("Expected head slot 20, received %d", incremental.headSlot)
+	}
+
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := Restore(incremental.path, dir); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := openBoltEngine(path.Join(dir, databaseFileName), true /* readOnly */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restored.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if err := restored.View(func(tx Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		if bkt.Get(first[:]) == nil || bkt.Get(second[:]) == nil {
+			t.Error("Expected restored database to contain the blocks of both backups")
+		}
+		if head := bkt.Get(headBlockRootKey); string(head) != string(second[:]) {
+			t.Errorf("Expected restored head %#x, received %#x", second, head)
+		}
+		if tx.Bucket(stateSummaryBucket).Get(first[:]) != nil {
+			t.Error("Expected the deleted state summary to not be restored")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStore_Backup_Retention(t *testing.T) {
+	db := setupDBWithConfig(t, &Config{IncrementalBackups: true, BackupFullInterval: 1, BackupRetention: 1})
+	ctx := context.Background()
+	backupsDir := path.Join(db.databasePath, backupsDirectoryName)
+
+	for i, expected := range []int{1, 2, 1} {
+		saveHead(t, db, uint64(i+1))
+		if err := db.Backup(ctx); err != nil {
+			t.Fatal(err)
+		}
+		backups, err := listBackups(backupsDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The incremental backup keeps its full backup, the third backup is full again.
+		if len(backups) != expected {
+			t.Errorf("Expected %d backups after backup %d, received %d", expected, i+1, len(backups))
+		}
+	}
+}
+
+func TestRestore_RejectsCorruptBackup(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	saveHead(t, db, 10)
+	if err := db.Backup(ctx); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := listBackups(path.Join(db.databasePath, backupsDirectoryName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("Expected 1 backup, received %d", len(backups))
+	}
+
+	engine, err := openBoltEngine(backups[0].path, false /* readOnly */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Update(func(tx Tx) error {
+		return tx.Bucket(blocksBucket).Put(headBlockRootKey, []byte("corrupt"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	err = Restore(backups[0].path, dir)
+	if err == nil || !strings.Contains(err.Error(), "no head block") {
+		t.Errorf("Expected missing head block, received %v", err)
+	}
+	if name, err := DetectEngine(dir); err != nil || name != "" {
+		t.Errorf("Expected no database to be swapped in, received %q (%v)", name, err)
+	}
+}
@@ -17,7 +17,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/sliceutil"
 	log "github.com/sirupsen/logrus"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -30,7 +29,7 @@ func (kv *Store) Block(ctx context.Context, blockRoot [32]byte) (*ethpb.SignedBe
 		return v.(*ethpb.SignedBeaconBlock), nil
 	}
 	var block *ethpb.SignedBeaconBlock
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -47,7 +46,7 @@ func (kv *Store) HeadBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, error
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadBlock")
 	defer span.End()
 	var headBlock *ethpb.SignedBeaconBlock
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		headRoot := bkt.Get(headBlockRootKey)
 		if headRoot == nil {
@@ -68,7 +67,7 @@ func (kv *Store) Blocks(ctx context.Context, f *filters.QueryFilter) ([]*ethpb.S
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.Blocks")
 	defer span.End()
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 
 		keys, err := getBlockRootsByFilter(ctx, tx, f)
@@ -94,7 +93,7 @@ func (kv *Store) BlockRoots(ctx context.Context, f *filters.QueryFilter) ([][32]
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.BlockRoots")
 	defer span.End()
 	blockRoots := make([][32]byte, 0)
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		keys, err := getBlockRootsByFilter(ctx, tx, f)
 		if err != nil {
 			return err
@@ -119,7 +118,7 @@ func (kv *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 		return true
 	}
 	exists := false
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		exists = bkt.Get(blockRoot[:]) != nil
 		return nil
@@ -133,7 +132,7 @@ func (kv *Store) HasBlock(ctx context.Context, blockRoot [32]byte) bool {
 func (kv *Store) deleteBlock(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.deleteBlock")
 	defer span.End()
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		enc := bkt.Get(blockRoot[:])
 		if enc == nil {
@@ -160,7 +159,7 @@ func (kv *Store) deleteBlocks(ctx context.Context, blockRoots [][32]byte) error
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.deleteBlocks")
 	defer span.End()
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		for _, blockRoot := range blockRoots {
 			enc := bkt.Get(blockRoot[:])
@@ -198,7 +197,7 @@ func (kv *Store) SaveBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 	if v, ok := kv.blockCache.Get(string(blockRoot[:])); v != nil && ok {
 		return nil
 	}
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		if err := kv.setBlockSlotBitField(ctx, tx, signed.Block.Slot); err != nil {
 			return err
 		}
@@ -215,6 +214,9 @@ func (kv *Store) SaveBlock(ctx context.Context, signed *ethpb.SignedBeaconBlock)
 		if err := updateValueForIndices(ctx, indicesByBucket, blockRoot[:], tx); err != nil {
 			return errors.Wrap(err, "could not update DB indices")
 		}
+		if err := kv.addToOutbox(tx, outboxBlock, blockRoot, enc); err != nil {
+			return err
+		}
 		kv.blockCache.Set(string(blockRoot[:]), signed, int64(len(enc)))
 		return bkt.Put(blockRoot[:], enc)
 	})
@@ -225,7 +227,7 @@ func (kv *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBlo
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveBlocks")
 	defer span.End()
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		for _, block := range blocks {
 			if err := kv.setBlockSlotBitField(ctx, tx, block.Block.Slot); err != nil {
@@ -247,6 +249,9 @@ func (kv *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBlo
 			if err := updateValueForIndices(ctx, indicesByBucket, blockRoot[:], tx); err != nil {
 				return errors.Wrap(err, "could not update DB indices")
 			}
+			if err := kv.addToOutbox(tx, outboxBlock, blockRoot, enc); err != nil {
+				return err
+			}
 			kv.blockCache.Set(string(blockRoot[:]), block, int64(len(enc)))
 
 			if err := bkt.Put(blockRoot[:], enc); err != nil {
@@ -261,7 +266,7 @@ func (kv *Store) SaveBlocks(ctx context.Context, blocks []*ethpb.SignedBeaconBlo
 func (kv *Store) SaveHeadBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveHeadBlockRoot")
 	defer span.End()
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		hasStateSummaryInCache := kv.stateSummaryCache.Has(blockRoot)
 		hasStateSummaryInDB := tx.Bucket(stateSummaryBucket).Get(blockRoot[:]) != nil
 		hasStateInDB := tx.Bucket(stateBucket).Get(blockRoot[:]) != nil
@@ -279,7 +284,7 @@ func (kv *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, er
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisBlock")
 	defer span.End()
 	var block *ethpb.SignedBeaconBlock
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		root := bkt.Get(genesisBlockRootKey)
 		enc := bkt.Get(root)
@@ -296,19 +301,44 @@ func (kv *Store) GenesisBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, er
 func (kv *Store) SaveGenesisBlockRoot(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveGenesisBlockRoot")
 	defer span.End()
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(blocksBucket)
 		return bucket.Put(genesisBlockRootKey, blockRoot[:])
 	})
 }
 
+// OriginBlockRoot returns the root of the block the node was bootstrapped from
+// when started from a weak subjectivity checkpoint instead of genesis. A zero
+// hash is returned if the node was synced from genesis.
+func (kv *Store) OriginBlockRoot(ctx context.Context) ([32]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.OriginBlockRoot")
+	defer span.End()
+	var root [32]byte
+	err := kv.db.View(func(tx Tx) error {
+		bkt := tx.Bucket(blocksBucket)
+		copy(root[:], bkt.Get(originBlockRootKey))
+		return nil
+	})
+	return root, err
+}
+
+// SaveOriginBlockRoot to the db.
+func (kv *Store) SaveOriginBlockRoot(ctx context.Context, blockRoot [32]byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveOriginBlockRoot")
+	defer span.End()
+	return kv.db.Update(func(tx Tx) error {
+		bucket := tx.Bucket(blocksBucket)
+		return bucket.Put(originBlockRootKey, blockRoot[:])
+	})
+}
+
 // HighestSlotBlocks returns the blocks with the highest slot from the db.
 func (kv *Store) HighestSlotBlocks(ctx context.Context) ([]*ethpb.SignedBeaconBlock, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HighestSlotBlocks")
 	defer span.End()
 
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		sBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := sBkt.Get(savedBlockSlotsKey)
 		highestIndex, err := bytesutil.HighestBitIndex(savedSlots)
@@ -332,7 +362,7 @@ func (kv *Store) HighestSlotBlocksBelow(ctx context.Context, slot uint64) ([]*et
 	defer span.End()
 
 	blocks := make([]*ethpb.SignedBeaconBlock, 0)
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		sBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := sBkt.Get(savedBlockSlotsKey)
 		if len(savedSlots) == 0 {
@@ -354,7 +384,7 @@ func (kv *Store) HighestSlotBlocksBelow(ctx context.Context, slot uint64) ([]*et
 
 // blocksAtSlotBitfieldIndex retrieves the blocks in DB given the input index. The index represents
 // the position of the slot bitfield the saved block maps to.
-func (kv *Store) blocksAtSlotBitfieldIndex(ctx context.Context, tx *bolt.Tx, index int) ([]*ethpb.SignedBeaconBlock, error) {
+func (kv *Store) blocksAtSlotBitfieldIndex(ctx context.Context, tx Tx, index int) ([]*ethpb.SignedBeaconBlock, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.blocksAtSlotBitfieldIndex")
 	defer span.End()
 
@@ -392,7 +422,7 @@ func (kv *Store) blocksAtSlotBitfieldIndex(ctx context.Context, tx *bolt.Tx, ind
 
 // setBlockSlotBitField sets the block slot bit in DB.
 // This helps to track which slot has a saved block in db.
-func (kv *Store) setBlockSlotBitField(ctx context.Context, tx *bolt.Tx, slot uint64) error {
+func (kv *Store) setBlockSlotBitField(ctx context.Context, tx Tx, slot uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.setBlockSlotBitField")
 	defer span.End()
 
@@ -413,7 +443,7 @@ func (kv *Store) setBlockSlotBitField(ctx context.Context, tx *bolt.Tx, slot uin
 
 // clearBlockSlotBitField clears the block slot bit in DB.
 // This helps to track which slot has a saved block in db.
-func (kv *Store) clearBlockSlotBitField(ctx context.Context, tx *bolt.Tx, slot uint64) error {
+func (kv *Store) clearBlockSlotBitField(ctx context.Context, tx Tx, slot uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.clearBlockSlotBitField")
 	defer span.End()
 
@@ -433,7 +463,7 @@ func (kv *Store) clearBlockSlotBitField(ctx context.Context, tx *bolt.Tx, slot u
 }
 
 // getBlockRootsByFilter retrieves the block roots given the filter criteria.
-func getBlockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFilter) ([][]byte, error) {
+func getBlockRootsByFilter(ctx context.Context, tx Tx, f *filters.QueryFilter) ([][]byte, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.getBlockRootsByFilter")
 	defer span.End()
 
@@ -491,7 +521,7 @@ func getBlockRootsByFilter(ctx context.Context, tx *bolt.Tx, f *filters.QueryFil
 // However, if step is one, the implemented logic won’t skip half of the slots in the range.
 func fetchBlockRootsBySlotRange(
 	ctx context.Context,
-	bkt *bolt.Bucket,
+	bkt Bucket,
 	startSlotEncoded interface{},
 	endSlotEncoded interface{},
 	startEpochEncoded interface{},
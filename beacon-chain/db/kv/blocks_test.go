@@ -170,6 +170,29 @@ func TestStore_GenesisBlock(t *testing.T) {
 	}
 }
 
+func TestStore_OriginBlockRoot(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	root, err := db.OriginBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != params.BeaconConfig().ZeroHash {
+		t.Errorf("Expected zero origin root, received %#x", root)
+	}
+	wanted := bytesutil.ToBytes32([]byte{'a'})
+	if err := db.SaveOriginBlockRoot(ctx, wanted); err != nil {
+		t.Fatal(err)
+	}
+	root, err = db.OriginBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != wanted {
+		t.Errorf("Wanted origin root %#x, received %#x", wanted, root)
+	}
+}
+
 func TestStore_BlocksCRUD_NoCache(t *testing.T) {
 	db := setupDB(t)
 	ctx := context.Background()
@@ -0,0 +1,128 @@
+package kv
+
+import (
+	"bytes"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+// The change log records the keys written since the last backup, so that an incremental
+// backup only copies those keys. Keys of the change log are the bucket name, prefixed
+// with its length, followed by the key. Values are the number of the write transaction
+// which last changed the key. Bookkeeping keys start with a zero byte, which no bucket
+// name length is: they hold the transaction counter and the name of the backup the log
+// is relative to.
+var (
+	changeLogCounterKey    = []byte{0x00, 'c'}
+	changeLogLastBackupKey = []byte{0x00, 'b'}
+)
+
+// changeLogKey returns the change log key of the key in the named bucket.
+func changeLogKey(name []byte, key []byte) []byte {
+	k := make([]byte, 0, len(name)+len(key)+1)
+	k = append(k, byte(len(name)))
+	k = append(k, name...)
+	return append(k, key...)
+}
+
+// parseChangeLogKey returns the bucket name and key of a change log key, or false for
+// bookkeeping keys.
+func parseChangeLogKey(k []byte) ([]byte, []byte, bool) {
+	if len(k) == 0 || k[0] == 0 || len(k) < int(k[0])+1 {
+		return nil, nil, false
+	}
+	return k[1 : int(k[0])+1], k[int(k[0])+1:], true
+}
+
+// changeLogCounter returns the number of the last write transaction recorded in the log.
+func changeLogCounter(changeLog Bucket) uint64 {
+	v := changeLog.Get(changeLogCounterKey)
+	if len(v) != 8 {
+		return 0
+	}
+	return bytesutil.FromBytes8(v)
+}
+
+// changeTrackingEngine records the keys changed by write transactions in the change log.
+type changeTrackingEngine struct {
+	Engine
+}
+
+func (e changeTrackingEngine) Update(fn func(tx Tx) error) error {
+	return e.Engine.Update(func(tx Tx) error {
+		return fn(&changeTrackingTx{Tx: tx})
+	})
+}
+
+func (e changeTrackingEngine) Batch(fn func(tx Tx) error) error {
+	return e.Engine.Batch(func(tx Tx) error {
+		return fn(&changeTrackingTx{Tx: tx})
+	})
+}
+
+type changeTrackingTx struct {
+	Tx
+	// counter is the number of the transaction, assigned on its first change.
+	counter []byte
+}
+
+// record adds the key of the named bucket to the change log.
+func (t *changeTrackingTx) record(name []byte, key []byte) error {
+	changeLog := t.Tx.Bucket(backupChangeLogBucket)
+	if changeLog == nil {
+		return nil
+	}
+	if t.counter == nil {
+		t.counter = bytesutil.Bytes8(changeLogCounter(changeLog) + 1)
+		if err := changeLog.Put(changeLogCounterKey, t.counter); err != nil {
+			return err
+		}
+	}
+	return changeLog.Put(changeLogKey(name, key), t.counter)
+}
+
+func (t *changeTrackingTx) wrap(name []byte, b Bucket) Bucket {
+	// Changes of the change log itself are not tracked.
+	if b == nil || bytes.Equal(name, backupChangeLogBucket) {
+		return b
+	}
+	return &changeTrackingBucket{Bucket: b, tx: t, name: name}
+}
+
+func (t *changeTrackingTx) Bucket(name []byte) Bucket {
+	return t.wrap(name, t.Tx.Bucket(name))
+}
+
+func (t *changeTrackingTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	b, err := t.Tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return t.wrap(name, b), nil
+}
+
+func (t *changeTrackingTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.Tx.ForEach(func(name []byte, b Bucket) error {
+		return fn(name, t.wrap(name, b))
+	})
+}
+
+type changeTrackingBucket struct {
+	Bucket
+	tx   *changeTrackingTx
+	name []byte
+}
+
+func (b *changeTrackingBucket) Put(key []byte, value []byte) error {
+	if err := b.tx.record(b.name, key); err != nil {
+		return err
+	}
+	return b.Bucket.Put(key, value)
+}
+
+func (b *changeTrackingBucket) Delete(key []byte) error {
+	if err := b.tx.record(b.name, key); err != nil {
+		return err
+	}
+	return b.Bucket.Delete(key)
+}
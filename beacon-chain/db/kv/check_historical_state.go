@@ -8,7 +8,6 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	log "github.com/sirupsen/logrus"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -25,7 +24,7 @@ func (kv *Store) HistoricalStatesDeleted(ctx context.Context) error {
 	}
 
 	var historicalStateDeleted bool
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bkt := tx.Bucket(newStateServiceCompatibleBucket)
 		v := bkt.Get(historicalStateDeletedKey)
 		historicalStateDeleted = len(v) == 1 && v[0] == 0x01
@@ -41,7 +40,7 @@ func (kv *Store) HistoricalStatesDeleted(ctx context.Context) error {
 		}
 	}
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(newStateServiceCompatibleBucket)
 		return bkt.Put(historicalStateDeletedKey, []byte{0x00})
 	})
@@ -50,7 +49,7 @@ func (kv *Store) HistoricalStatesDeleted(ctx context.Context) error {
 // This verifies the slots per archived point has not been altered since it's used.
 // The node does not allow slots per archived point to alter once it's in operation.
 func (kv *Store) verifySlotsPerArchivePoint() error {
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(newStateServiceCompatibleBucket)
 		v := bkt.Get(archivedSlotsPerPointKey)
 		if v == nil {
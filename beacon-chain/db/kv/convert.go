@@ -0,0 +1,121 @@
+package kv
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// copyBatchSize is the number of keys copied between engines per write transaction.
+const copyBatchSize = 10000
+
+// Convert copies the database in the given directory from one key-value engine to
+// another. The source database is left in place and the destination database must
+// not exist yet. The node must not be running while its database is converted.
+func Convert(dirPath string, from string, to string) error {
+	for _, name := range []string{from, to} {
+		if _, ok := engineFiles[name]; !ok {
+			return errors.Errorf("unknown database engine %q, expected one of %v", name, Engines)
+		}
+	}
+	if from == to {
+		return errors.Errorf("database already uses the %s engine", from)
+	}
+	if _, err := os.Stat(path.Join(dirPath, engineFiles[from])); err != nil {
+		return errors.Wrapf(err, "could not find %s database", from)
+	}
+	dstPath := path.Join(dirPath, engineFiles[to])
+	if _, err := os.Stat(dstPath); err == nil {
+		return errors.Errorf("%s already exists, remove it before converting", dstPath)
+	}
+
+	src, err := openEngine(from, dirPath, true /* readOnly */)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.WithError(err).Error("Failed to close source database")
+		}
+	}()
+	dst, err := openEngine(to, dirPath, false /* readOnly */)
+	if err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"from": from,
+		"to":   to,
+	}).Info("Converting database")
+	if err := copyBuckets(src, dst); err != nil {
+		if closeErr := dst.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Failed to close destination database")
+		}
+		// Do not leave a partial database behind, which would be picked up on start.
+		if rmErr := os.RemoveAll(dstPath); rmErr != nil {
+			log.WithError(rmErr).Error("Failed to remove partially converted database")
+		}
+		return errors.Wrap(err, "could not copy database")
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	log.WithField("path", dstPath).Info("Converted database, remove the source database before starting the node")
+	return nil
+}
+
+// copyBuckets copies all buckets of the source engine into the destination engine.
+func copyBuckets(src Engine, dst Engine) error {
+	return src.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, b Bucket) error {
+			return copyBucket(name, b, dst)
+		})
+	})
+}
+
+// copyBucket copies the bucket into the bucket of the same name of the destination
+// engine. The keys are written in batches, so that a large bucket does not have to
+// be buffered in a single write transaction.
+func copyBucket(name []byte, b Bucket, dst Engine) error {
+	var keys, values [][]byte
+	flush := func() error {
+		if err := dst.Update(func(dstTx Tx) error {
+			dstBkt, err := dstTx.CreateBucketIfNotExists(name)
+			if err != nil {
+				return err
+			}
+			for i := range keys {
+				if err := dstBkt.Put(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		keys, values = keys[:0], values[:0]
+		return nil
+	}
+	count := 0
+	if err := b.ForEach(func(k []byte, v []byte) error {
+		keys = append(keys, k)
+		values = append(values, v)
+		count++
+		if len(keys) >= copyBatchSize {
+			return flush()
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	// Flush the remainder, which also creates empty buckets.
+	if err := flush(); err != nil {
+		return err
+	}
+	log.WithFields(log.Fields{
+		"bucket": string(name),
+		"keys":   count,
+	}).Debug("Copied bucket")
+	return nil
+}
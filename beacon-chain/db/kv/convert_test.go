@@ -0,0 +1,78 @@
+package kv
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+)
+
+func TestConvert(t *testing.T) {
+	dir, err := ioutil.TempDir("", "convert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	db, err := NewKVStore(dir, cache.NewStateSummaryCache(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	block := &eth.SignedBeaconBlock{Block: &eth.BeaconBlock{Slot: 20}}
+	if err := db.SaveBlock(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(block.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Convert(dir, BoltEngine, BoltEngine); err == nil {
+		t.Error("Expected error when converting to the same engine")
+	}
+	if err := Convert(dir, "rocksdb", LevelDBEngine); err == nil {
+		t.Error("Expected error for an unknown source engine")
+	}
+	if err := Convert(dir, BoltEngine, LevelDBEngine); err != nil {
+		t.Fatal(err)
+	}
+	if err := Convert(dir, BoltEngine, LevelDBEngine); err == nil {
+		t.Error("Expected error when the destination database exists")
+	}
+	if err := os.Remove(path.Join(dir, databaseFileName)); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = NewKVStore(dir, cache.NewStateSummaryCache(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if db.engine != LevelDBEngine {
+		t.Errorf("Expected converted database to use %s, received %s", LevelDBEngine, db.engine)
+	}
+	received, err := db.Block(ctx, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(block, received) {
+		t.Errorf("Wanted %v, received %v", block, received)
+	}
+}
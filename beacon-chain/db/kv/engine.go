@@ -0,0 +1,139 @@
+package kv
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the supported key-value engines.
+const (
+	// BoltEngine stores the database in a single bbolt file. It is the default engine.
+	BoltEngine = "bolt"
+	// LevelDBEngine stores the database in a LevelDB log-structured merge tree, which
+	// sustains higher write throughput and does not grow the database file on updates.
+	LevelDBEngine = "leveldb"
+)
+
+// Engines lists the names of the supported key-value engines.
+var Engines = []string{BoltEngine, LevelDBEngine}
+
+// Engine is a transactional key-value engine which organises its keys in buckets,
+// on which the Store is built. Read transactions see a consistent snapshot of the
+// database and write transactions are serialized.
+type Engine interface {
+	// View executes the function within a read-only transaction.
+	View(fn func(tx Tx) error) error
+	// Update executes the function within a read-write transaction, which is
+	// committed if the function returns nil and rolled back otherwise.
+	Update(fn func(tx Tx) error) error
+	// Batch executes the function within a read-write transaction, which may be
+	// combined with the transactions of concurrent callers.
+	Batch(fn func(tx Tx) error) error
+	// Close releases all resources held by the engine.
+	Close() error
+}
+
+// Tx is a transaction of an engine. Keys and values returned by a transaction are
+// only valid for the life of the transaction.
+type Tx interface {
+	// Bucket returns the bucket with the given name, or nil if it does not exist.
+	Bucket(name []byte) Bucket
+	// CreateBucketIfNotExists creates the bucket with the given name if it does not
+	// exist yet and returns it.
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+	// ForEach calls the function for every bucket in the database.
+	ForEach(fn func(name []byte, b Bucket) error) error
+}
+
+// Bucket is a collection of key-value pairs, ordered by key.
+type Bucket interface {
+	// Get returns the value of the key, or nil if the key does not exist.
+	Get(key []byte) []byte
+	// Put sets the value of the key.
+	Put(key []byte, value []byte) error
+	// Delete removes the key. Deleting a key which does not exist is not an error.
+	Delete(key []byte) error
+	// Cursor returns a cursor to iterate over the bucket in key order.
+	Cursor() Cursor
+	// ForEach calls the function for every key-value pair in the bucket, in key order.
+	ForEach(fn func(k []byte, v []byte) error) error
+}
+
+// Cursor iterates over the key-value pairs of a bucket in key order. A nil key is
+// returned once the cursor moved past the last pair.
+type Cursor interface {
+	// First moves the cursor to the first pair of the bucket.
+	First() (key []byte, value []byte)
+	// Seek moves the cursor to the first pair with a key equal to or greater than seek.
+	Seek(seek []byte) (key []byte, value []byte)
+	// Next moves the cursor to the next pair.
+	Next() (key []byte, value []byte)
+}
+
+// engineFiles maps the supported engines to the file or directory of the database
+// within the database directory.
+var engineFiles = map[string]string{
+	BoltEngine:    databaseFileName,
+	LevelDBEngine: levelDBDirectoryName,
+}
+
+// openEngine opens the database of the named engine in the given directory,
+// creating it if it does not exist unless the engine is opened read-only.
+func openEngine(name string, dirPath string, readOnly bool) (Engine, error) {
+	switch name {
+	case BoltEngine:
+		return openBoltEngine(path.Join(dirPath, databaseFileName), readOnly)
+	case LevelDBEngine:
+		return openLevelDBEngine(path.Join(dirPath, levelDBDirectoryName), readOnly)
+	}
+	return nil, errors.Errorf("unknown database engine %q, expected one of %v", name, Engines)
+}
+
+// DetectEngine returns the name of the engine of the existing database in the given
+// directory, or an empty string if there is no database.
+func DetectEngine(dirPath string) (string, error) {
+	var found string
+	for _, name := range Engines {
+		if _, err := os.Stat(path.Join(dirPath, engineFiles[name])); err == nil {
+			if found != "" {
+				return "", errors.Errorf(
+					"found both a %s and a %s database in %s, please remove the one not in use",
+					found,
+					name,
+					dirPath,
+				)
+			}
+			found = name
+		}
+	}
+	return found, nil
+}
+
+// selectEngine returns the engine to open the database in the given directory with.
+// If no engine is requested, the engine of the existing database is used and bolt
+// for a new database. Requesting an engine other than that of the existing
+// database is an error, as its data needs to be converted first.
+func selectEngine(requested string, dirPath string) (string, error) {
+	existing, err := DetectEngine(dirPath)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case requested == "" && existing == "":
+		return BoltEngine, nil
+	case requested == "":
+		return existing, nil
+	case existing != "" && existing != requested:
+		return "", errors.Errorf(
+			"database in %s uses the %s engine, convert it with `beacon-chain db convert --from %s --to %s` to use the %s engine",
+			dirPath,
+			existing,
+			existing,
+			requested,
+			requested,
+		)
+	}
+	return requested, nil
+}
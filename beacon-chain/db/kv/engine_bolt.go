@@ -0,0 +1,114 @@
+package kv
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	prombolt "github.com/prysmaticlabs/prombbolt"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	databaseFileName = "beaconchain.db"
+	boltAllocSize    = 8 * 1024 * 1024
+)
+
+// boltEngine is an engine backed by a bbolt database file.
+type boltEngine struct {
+	db *bolt.DB
+}
+
+func openBoltEngine(datafile string, readOnly bool) (*boltEngine, error) {
+	boltDB, err := bolt.Open(
+		datafile,
+		params.BeaconIoConfig().ReadWritePermissions,
+		&bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6, ReadOnly: readOnly},
+	)
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return nil, err
+	}
+	boltDB.AllocSize = boltAllocSize
+	return &boltEngine{db: boltDB}, nil
+}
+
+func (e *boltEngine) View(fn func(tx Tx) error) error {
+	return e.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (e *boltEngine) Update(fn func(tx Tx) error) error {
+	return e.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (e *boltEngine) Batch(fn func(tx Tx) error) error {
+	return e.db.Batch(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (e *boltEngine) Close() error {
+	return e.db.Close()
+}
+
+// collector returns a prometheus collector specifically configured for boltdb.
+func (e *boltEngine) collector() prometheus.Collector {
+	return prombolt.New("boltDB", e.db)
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) Bucket(name []byte) Bucket {
+	bkt := t.tx.Bucket(name)
+	if bkt == nil {
+		return nil
+	}
+	return boltBucket{bkt}
+}
+
+func (t boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	bkt, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return boltBucket{bkt}, nil
+}
+
+func (t boltTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	return t.tx.ForEach(func(name []byte, bkt *bolt.Bucket) error {
+		return fn(name, boltBucket{bkt})
+	})
+}
+
+type boltBucket struct {
+	bkt *bolt.Bucket
+}
+
+func (b boltBucket) Get(key []byte) []byte {
+	return b.bkt.Get(key)
+}
+
+func (b boltBucket) Put(key []byte, value []byte) error {
+	return b.bkt.Put(key, value)
+}
+
+func (b boltBucket) Delete(key []byte) error {
+	return b.bkt.Delete(key)
+}
+
+func (b boltBucket) Cursor() Cursor {
+	return b.bkt.Cursor()
+}
+
+func (b boltBucket) ForEach(fn func(k []byte, v []byte) error) error {
+	return b.bkt.ForEach(fn)
+}
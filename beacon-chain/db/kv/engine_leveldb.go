@@ -0,0 +1,335 @@
+package kv
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const levelDBDirectoryName = "beaconchain-leveldb"
+
+// LevelDB has no notion of buckets, so the keys of a bucket are stored with the bucket
+// name as prefix. The names of the existing buckets are stored under a separate prefix.
+const (
+	levelDBBucketNamePrefix = byte(0x00)
+	levelDBBucketDataPrefix = byte(0x01)
+)
+
+var errTxNotWritable = errors.New("transaction not writable")
+
+// levelDBEngine is an engine backed by a LevelDB database. Read transactions operate on
+// a snapshot of the database. Write transactions buffer their writes, which are visible
+// to the transaction itself, and commit them atomically in a single batch.
+type levelDBEngine struct {
+	db       *leveldb.DB
+	readOnly bool
+	// writeLock serializes write transactions, so that their reads are not invalidated
+	// by concurrent commits.
+	writeLock sync.Mutex
+}
+
+func openLevelDBEngine(dirPath string, readOnly bool) (*levelDBEngine, error) {
+	db, err := leveldb.OpenFile(dirPath, &opt.Options{ReadOnly: readOnly, ErrorIfMissing: readOnly})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open leveldb database, it may be in use by another process")
+	}
+	return &levelDBEngine{db: db, readOnly: readOnly}, nil
+}
+
+func (e *levelDBEngine) View(fn func(tx Tx) error) error {
+	tx, err := e.begin(false)
+	if err != nil {
+		return err
+	}
+	defer tx.release()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.err
+}
+
+func (e *levelDBEngine) Update(fn func(tx Tx) error) error {
+	if e.readOnly {
+		return errTxNotWritable
+	}
+	e.writeLock.Lock()
+	defer e.writeLock.Unlock()
+
+	tx, err := e.begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.release()
+	if err := fn(tx); err != nil {
+		return err
+	}
+	// A failed read may have hidden data the transaction depended on, so it is not committed.
+	if tx.err != nil {
+		return tx.err
+	}
+	if len(tx.pending) == 0 {
+		return nil
+	}
+	batch := new(leveldb.Batch)
+	for k, w := range tx.pending {
+		if w.deleted {
+			batch.Delete([]byte(k))
+		} else {
+			batch.Put([]byte(k), w.value)
+		}
+	}
+	return e.db.Write(batch, &opt.WriteOptions{Sync: true})
+}
+
+// Batch runs the function in a regular write transaction, as LevelDB already commits
+// writes without the page rewrites that make batching worthwhile for bolt.
+func (e *levelDBEngine) Batch(fn func(tx Tx) error) error {
+	return e.Update(fn)
+}
+
+func (e *levelDBEngine) Close() error {
+	return e.db.Close()
+}
+
+func (e *levelDBEngine) begin(writable bool) (*levelDBTx, error) {
+	snap, err := e.db.GetSnapshot()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get database snapshot")
+	}
+	tx := &levelDBTx{snap: snap, writable: writable}
+	if writable {
+		tx.pending = make(map[string]*levelDBWrite)
+	}
+	return tx, nil
+}
+
+// levelDBWrite is a write buffered by a transaction.
+type levelDBWrite struct {
+	value   []byte
+	deleted bool
+}
+
+type levelDBTx struct {
+	snap     *leveldb.Snapshot
+	writable bool
+	pending  map[string]*levelDBWrite
+	iters    []iterator.Iterator
+	// err is the first read error of the transaction. Reads report missing data on
+	// error, as the bucket interface has no error return, and the transaction fails.
+	err error
+}
+
+func (t *levelDBTx) release() {
+	for _, it := range t.iters {
+		it.Release()
+	}
+	t.snap.Release()
+}
+
+func (t *levelDBTx) get(key []byte) []byte {
+	if w, ok := t.pending[string(key)]; ok {
+		if w.deleted {
+			return nil
+		}
+		return w.value
+	}
+	v, err := t.snap.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		t.fail(errors.Wrap(err, "could not read from leveldb"))
+		return nil
+	}
+	return v
+}
+
+// fail records the error, failing the transaction.
+func (t *levelDBTx) fail(err error) {
+	if t.err == nil {
+		t.err = err
+	}
+}
+
+func (t *levelDBTx) put(key []byte, value []byte) error {
+	if !t.writable {
+		return errTxNotWritable
+	}
+	v := make([]byte, len(value))
+	copy(v, value)
+	t.pending[string(key)] = &levelDBWrite{value: v}
+	return nil
+}
+
+func (t *levelDBTx) delete(key []byte) error {
+	if !t.writable {
+		return errTxNotWritable
+	}
+	t.pending[string(key)] = &levelDBWrite{deleted: true}
+	return nil
+}
+
+// cursor returns a cursor over the keys with the given prefix, which merges the
+// buffered writes of the transaction with the snapshot.
+func (t *levelDBTx) cursor(prefix []byte) *levelDBCursor {
+	it := t.snap.NewIterator(util.BytesPrefix(prefix), nil)
+	t.iters = append(t.iters, it)
+	c := &levelDBCursor{tx: t, prefix: prefix, iter: it}
+	for k, w := range t.pending {
+		if bytes.HasPrefix([]byte(k), prefix) {
+			c.pending = append(c.pending, levelDBCursorEntry{key: []byte(k)[len(prefix):], write: w})
+		}
+	}
+	sort.Slice(c.pending, func(i, j int) bool {
+		return bytes.Compare(c.pending[i].key, c.pending[j].key) < 0
+	})
+	return c
+}
+
+func (t *levelDBTx) Bucket(name []byte) Bucket {
+	if t.get(levelDBBucketNameKey(name)) == nil {
+		return nil
+	}
+	return &levelDBBucket{tx: t, prefix: levelDBBucketPrefix(name)}
+}
+
+func (t *levelDBTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	if len(name) == 0 || len(name) > 255 {
+		return nil, errors.Errorf("invalid bucket name %q", name)
+	}
+	if t.get(levelDBBucketNameKey(name)) == nil {
+		if err := t.put(levelDBBucketNameKey(name), []byte{0x01}); err != nil {
+			return nil, err
+		}
+	}
+	return &levelDBBucket{tx: t, prefix: levelDBBucketPrefix(name)}, nil
+}
+
+func (t *levelDBTx) ForEach(fn func(name []byte, b Bucket) error) error {
+	c := t.cursor([]byte{levelDBBucketNamePrefix})
+	for name, _ := c.First(); name != nil; name, _ = c.Next() {
+		if err := fn(name, &levelDBBucket{tx: t, prefix: levelDBBucketPrefix(name)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func levelDBBucketNameKey(name []byte) []byte {
+	return append([]byte{levelDBBucketNamePrefix}, name...)
+}
+
+func levelDBBucketPrefix(name []byte) []byte {
+	prefix := make([]byte, 0, len(name)+2)
+	prefix = append(prefix, levelDBBucketDataPrefix, byte(len(name)))
+	return append(prefix, name...)
+}
+
+type levelDBBucket struct {
+	tx     *levelDBTx
+	prefix []byte
+}
+
+func (b *levelDBBucket) key(key []byte) []byte {
+	k := make([]byte, 0, len(b.prefix)+len(key))
+	k = append(k, b.prefix...)
+	return append(k, key...)
+}
+
+func (b *levelDBBucket) Get(key []byte) []byte {
+	return b.tx.get(b.key(key))
+}
+
+func (b *levelDBBucket) Put(key []byte, value []byte) error {
+	if len(key) == 0 {
+		return errors.New("key required")
+	}
+	return b.tx.put(b.key(key), value)
+}
+
+func (b *levelDBBucket) Delete(key []byte) error {
+	return b.tx.delete(b.key(key))
+}
+
+func (b *levelDBBucket) Cursor() Cursor {
+	return b.tx.cursor(b.prefix)
+}
+
+func (b *levelDBBucket) ForEach(fn func(k []byte, v []byte) error) error {
+	c := b.tx.cursor(b.prefix)
+	for k, v := c.First(); k != nil; k, v = c.Next() {
+		if err := fn(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type levelDBCursorEntry struct {
+	key   []byte
+	write *levelDBWrite
+}
+
+// levelDBCursor merges the sorted buffered writes of a transaction with an iterator
+// over its snapshot, with buffered writes taking precedence.
+type levelDBCursor struct {
+	tx        *levelDBTx
+	prefix    []byte
+	iter      iterator.Iterator
+	iterValid bool
+	pending   []levelDBCursorEntry
+	next      int
+}
+
+func (c *levelDBCursor) First() ([]byte, []byte) {
+	c.iterValid = c.iter.First()
+	c.next = 0
+	return c.Next()
+}
+
+func (c *levelDBCursor) Seek(seek []byte) ([]byte, []byte) {
+	c.iterValid = c.iter.Seek(append(append([]byte{}, c.prefix...), seek...))
+	c.next = sort.Search(len(c.pending), func(i int) bool {
+		return bytes.Compare(c.pending[i].key, seek) >= 0
+	})
+	return c.Next()
+}
+
+func (c *levelDBCursor) Next() ([]byte, []byte) {
+	for {
+		var iterKey []byte
+		if c.iterValid {
+			iterKey = c.iter.Key()[len(c.prefix):]
+		}
+		if !c.iterValid {
+			if err := c.iter.Error(); err != nil {
+				c.tx.fail(errors.Wrap(err, "could not iterate over leveldb"))
+			}
+		}
+		hasPending := c.next < len(c.pending)
+		if !c.iterValid && !hasPending {
+			return nil, nil
+		}
+		if hasPending && (!c.iterValid || bytes.Compare(c.pending[c.next].key, iterKey) <= 0) {
+			entry := c.pending[c.next]
+			c.next++
+			if c.iterValid && bytes.Equal(entry.key, iterKey) {
+				c.iterValid = c.iter.Next()
+			}
+			if entry.write.deleted {
+				continue
+			}
+			return entry.key, entry.write.value
+		}
+		k := append([]byte{}, iterKey...)
+		v := append([]byte{}, c.iter.Value()...)
+		c.iterValid = c.iter.Next()
+		return k, v
+	}
+}
@@ -0,0 +1,230 @@
+package kv
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func setupEngine(t *testing.T, name string) Engine {
+	dir, err := ioutil.TempDir("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := openEngine(name, dir, false /* readOnly */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := engine.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return engine
+}
+
+func TestEngine_Buckets(t *testing.T) {
+	for _, name := range Engines {
+		t.Run(name, func(t *testing.T) {
+			engine := setupEngine(t, name)
+			if err := engine.Update(func(tx Tx) error {
+				if tx.Bucket([]byte("a")) != nil {
+					t.Error("Expected missing bucket to be nil")
+				}
+				bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+				if err != nil {
+					return err
+				}
+				if err := bkt.Put([]byte("key"), []byte("value")); err != nil {
+					return err
+				}
+				// Writes are visible within the transaction.
+				if v := tx.Bucket([]byte("a")).Get([]byte("key")); !bytes.Equal(v, []byte("value")) {
+					t.Errorf("Expected value within transaction, received %q", v)
+				}
+				_, err = tx.CreateBucketIfNotExists([]byte("b"))
+				return err
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := engine.View(func(tx Tx) error {
+				if v := tx.Bucket([]byte("a")).Get([]byte("key")); !bytes.Equal(v, []byte("value")) {
+					t.Errorf("Expected committed value, received %q", v)
+				}
+				if v := tx.Bucket([]byte("b")).Get([]byte("key")); v != nil {
+					t.Errorf("Expected keys to be scoped to their bucket, received %q", v)
+				}
+				var names []string
+				if err := tx.ForEach(func(name []byte, _ Bucket) error {
+					names = append(names, string(name))
+					return nil
+				}); err != nil {
+					return err
+				}
+				if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+					t.Errorf("Expected buckets [a b], received %v", names)
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := engine.Update(func(tx Tx) error {
+				return tx.Bucket([]byte("a")).Delete([]byte("key"))
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if err := engine.View(func(tx Tx) error {
+				if v := tx.Bucket([]byte("a")).Get([]byte("key")); v != nil {
+					t.Errorf("Expected deleted key, received %q", v)
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestEngine_Rollback(t *testing.T) {
+	for _, name := range Engines {
+		t.Run(name, func(t *testing.T) {
+			engine := setupEngine(t, name)
+			errRollback := errors.New("rollback")
+			err := engine.Update(func(tx Tx) error {
+				bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+				if err != nil {
+					return err
+				}
+				if err := bkt.Put([]byte("key"), []byte("value")); err != nil {
+					return err
+				}
+				return errRollback
+			})
+			if err != errRollback {
+				t.Fatalf("Expected rollback error, received %v", err)
+			}
+			if err := engine.View(func(tx Tx) error {
+				if tx.Bucket([]byte("a")) != nil {
+					t.Error("Expected bucket of rolled back transaction to be missing")
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestEngine_Cursor(t *testing.T) {
+	for _, name := range Engines {
+		t.Run(name, func(t *testing.T) {
+			engine := setupEngine(t, name)
+			if err := engine.Update(func(tx Tx) error {
+				bkt, err := tx.CreateBucketIfNotExists([]byte("a"))
+				if err != nil {
+					return err
+				}
+				for _, k := range []string{"1", "3", "5", "7"} {
+					if err := bkt.Put([]byte(k), []byte(k)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := engine.Update(func(tx Tx) error {
+				bkt := tx.Bucket([]byte("a"))
+				// Uncommitted writes and deletes are merged with the committed keys.
+				if err := bkt.Put([]byte("4"), []byte("4")); err != nil {
+					return err
+				}
+				if err := bkt.Put([]byte("5"), []byte("five")); err != nil {
+					return err
+				}
+				if err := bkt.Delete([]byte("3")); err != nil {
+					return err
+				}
+
+				var keys, values []string
+				c := bkt.Cursor()
+				for k, v := c.First(); k != nil; k, v = c.Next() {
+					keys = append(keys, string(k))
+					values = append(values, string(v))
+				}
+				if got := keys; len(got) != 4 || got[0] != "1" || got[1] != "4" || got[2] != "5" || got[3] != "7" {
+					t.Errorf("Expected keys [1 4 5 7], received %v", got)
+				}
+				if len(values) == 4 && values[2] != "five" {
+					t.Errorf("Expected uncommitted value, received %q", values[2])
+				}
+
+				keys = nil
+				for k, _ := c.Seek([]byte("2")); k != nil; k, _ = c.Next() {
+					keys = append(keys, string(k))
+				}
+				if len(keys) != 3 || keys[0] != "4" {
+					t.Errorf("Expected keys [4 5 7] after seek, received %v", keys)
+				}
+				if k, _ := c.Seek([]byte("8")); k != nil {
+					t.Errorf("Expected no key after the last key, received %q", k)
+				}
+
+				count := 0
+				if err := bkt.ForEach(func(_ []byte, _ []byte) error {
+					count++
+					return nil
+				}); err != nil {
+					return err
+				}
+				if count != 4 {
+					t.Errorf("Expected 4 keys, received %d", count)
+				}
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestSelectEngine(t *testing.T) {
+	dir, err := ioutil.TempDir("", "engine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	if name, err := selectEngine("", dir); err != nil || name != BoltEngine {
+		t.Errorf("Expected bolt for a new database, received %q (%v)", name, err)
+	}
+	if name, err := selectEngine(LevelDBEngine, dir); err != nil || name != LevelDBEngine {
+		t.Errorf("Expected requested engine for a new database, received %q (%v)", name, err)
+	}
+
+	engine, err := openEngine(LevelDBEngine, dir, false /* readOnly */)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if name, err := selectEngine("", dir); err != nil || name != LevelDBEngine {
+		t.Errorf("Expected engine of the existing database, received %q (%v)", name, err)
+	}
+	if _, err := selectEngine(BoltEngine, dir); err == nil {
+		t.Error("Expected error when requesting an engine other than that of the existing database")
+	}
+}
@@ -0,0 +1,326 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// OpenReadOnly opens the existing database in the given directory read-only, so that it
+// can be inspected while the node is stopped. Pending migrations are not applied and
+// writes to the returned store fail.
+func OpenReadOnly(dirPath string) (*Store, error) {
+	engineName, err := DetectEngine(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	if engineName == "" {
+		return nil, errors.Errorf("could not find database in %s", dirPath)
+	}
+	engine, err := openEngine(engineName, dirPath, true /* readOnly */)
+	if err != nil {
+		return nil, err
+	}
+	kv, err := newStore(engine, engineName, dirPath, cache.NewStateSummaryCache(), &Config{})
+	if err != nil {
+		if closeErr := engine.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Failed to close database")
+		}
+		return nil, err
+	}
+	return kv, nil
+}
+
+// BucketStats describes the contents of a bucket of the database.
+type BucketStats struct {
+	Name string
+	Keys int
+	// Size is the total size of the keys and values of the bucket in bytes.
+	Size int
+}
+
+// BucketStats returns the number of keys and the size of every bucket of the database,
+// ordered by bucket name.
+func (kv *Store) BucketStats() ([]*BucketStats, error) {
+	var stats []*BucketStats
+	err := kv.db.View(func(tx Tx) error {
+		return tx.ForEach(func(name []byte, b Bucket) error {
+			s := &BucketStats{Name: string(name)}
+			if err := b.ForEach(func(k []byte, v []byte) error {
+				s.Keys++
+				s.Size += len(k) + len(v)
+				return nil
+			}); err != nil {
+				return err
+			}
+			stats = append(stats, s)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return stats, nil
+}
+
+// IndexError describes an inconsistency between an index and the blocks of the database.
+type IndexError struct {
+	// Index is the name of the bucket of the index.
+	Index   string
+	Problem string
+}
+
+func (e *IndexError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Index, e.Problem)
+}
+
+// indexedBlock holds the fields of a block which the indices are checked against.
+type indexedBlock struct {
+	slot       uint64
+	parentRoot []byte
+}
+
+// VerifyIndices checks that the block slot index and the finalized block roots index are
+// consistent with the blocks of the database, and returns the inconsistencies found.
+func (kv *Store) VerifyIndices(ctx context.Context) ([]*IndexError, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.VerifyIndices")
+	defer span.End()
+
+	var problems []*IndexError
+	err := kv.db.View(func(tx Tx) error {
+		blocks, err := indexedBlocks(tx)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, verifySlotIndex(tx, blocks)...)
+		finalizedProblems, err := verifyFinalizedIndex(tx, blocks)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, finalizedProblems...)
+		return nil
+	})
+	return problems, err
+}
+
+// indexedBlocks returns the slot and parent root of every block of the database by block root.
+func indexedBlocks(tx Tx) (map[[32]byte]*indexedBlock, error) {
+	blocks := make(map[[32]byte]*indexedBlock)
+	err := tx.Bucket(blocksBucket).ForEach(func(k []byte, v []byte) error {
+		// Skip the head, genesis and origin root keys stored along with the blocks.
+		if len(k) != 32 {
+			return nil
+		}
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := decode(v, blk); err != nil {
+			return errors.Wrapf(err, "could not decode block %#x", k)
+		}
+		if blk.Block == nil {
+			return errors.Errorf("block %#x is empty", k)
+		}
+		blocks[bytesutil.ToBytes32(k)] = &indexedBlock{slot: blk.Block.Slot, parentRoot: blk.Block.ParentRoot}
+		return nil
+	})
+	return blocks, err
+}
+
+// verifySlotIndex checks that every block is indexed at its slot, and that the index only
+// references existing blocks.
+func verifySlotIndex(tx Tx, blocks map[[32]byte]*indexedBlock) []*IndexError {
+	index := string(blockSlotIndicesBucket)
+	var problems []*IndexError
+	indexed := make(map[[32]byte]bool, len(blocks))
+	if err := tx.Bucket(blockSlotIndicesBucket).ForEach(func(k []byte, v []byte) error {
+		slot, err := strconv.ParseUint(string(k), 10, 64)
+		if err != nil {
+			problems = append(problems, &IndexError{Index: index, Problem: fmt.Sprintf("invalid slot key %q", k)})
+			return nil
+		}
+		if len(v)%32 != 0 {
+			problems = append(problems, &IndexError{Index: index, Problem: fmt.Sprintf("malformed roots at slot %d", slot)})
+			return nil
+		}
+		for i := 0; i < len(v); i += 32 {
+			root := bytesutil.ToBytes32(v[i : i+32])
+			blk, ok := blocks[root]
+			switch {
+			case !ok:
+				problems = append(problems, &IndexError{
+					Index:   index,
+					Problem: fmt.Sprintf("missing block %#x indexed at slot %d", root, slot),
+				})
+			case blk.slot != slot:
+				problems = append(problems, &IndexError{
+					Index:   index,
+					Problem: fmt.Sprintf("block %#x of slot %d indexed at slot %d", root, blk.slot, slot),
+				})
+			default:
+				indexed[root] = true
+			}
+		}
+		return nil
+	}); err != nil {
+		problems = append(problems, &IndexError{Index: index, Problem: err.Error()})
+	}
+	for root, blk := range blocks {
+		if !indexed[root] {
+			problems = append(problems, &IndexError{
+				Index:   index,
+				Problem: fmt.Sprintf("block %#x of slot %d is not indexed", root, blk.slot),
+			})
+		}
+	}
+	return problems
+}
+
+// verifyFinalizedIndex checks that the finalized block roots index only references existing
+// blocks, that its containers link each block to its parent and child, and that the
+// finalized checkpoint is indexed.
+func verifyFinalizedIndex(tx Tx, blocks map[[32]byte]*indexedBlock) ([]*IndexError, error) {
+	index := string(finalizedBlockRootsIndexBucket)
+	var problems []*IndexError
+	bkt := tx.Bucket(finalizedBlockRootsIndexBucket)
+	prunedSlot := prunedSlotValue(tx, prunedSlotKey)
+	err := bkt.ForEach(func(k []byte, v []byte) error {
+		if bytes.Equal(k, previousFinalizedCheckpointKey) {
+			return nil
+		}
+		root := bytesutil.ToBytes32(k)
+		blk, ok := blocks[root]
+		if len(k) != 32 || !ok {
+			problems = append(problems, &IndexError{Index: index, Problem: fmt.Sprintf("missing block %#x is indexed", k)})
+			return nil
+		}
+		if bytes.Equal(v, containerFinalizedButNotCanonical) {
+			return nil
+		}
+		container := &dbpb.FinalizedBlockRootContainer{}
+		if err := decode(v, container); err != nil {
+			problems = append(problems, &IndexError{Index: index, Problem: fmt.Sprintf("malformed container of block %#x", root)})
+			return nil
+		}
+		if !bytes.Equal(container.ParentRoot, blk.parentRoot) {
+			problems = append(problems, &IndexError{
+				Index:   index,
+				Problem: fmt.Sprintf("block %#x indexed with parent %#x instead of %#x", root, container.ParentRoot, blk.parentRoot),
+			})
+		}
+		if len(container.ChildRoot) == 0 {
+			return nil
+		}
+		child, ok := blocks[bytesutil.ToBytes32(container.ChildRoot)]
+		// The children of blocks kept by pruning, such as archived points, may be pruned.
+		if !ok && blk.slot < prunedSlot {
+			return nil
+		}
+		if !ok || !bytes.Equal(child.parentRoot, k) {
+			problems = append(problems, &IndexError{
+				Index:   index,
+				Problem: fmt.Sprintf("block %#x indexed with child %#x which does not build on it", root, container.ChildRoot),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+	if enc == nil {
+		return problems, nil
+	}
+	checkpoint := &ethpb.Checkpoint{}
+	if err := decode(enc, checkpoint); err != nil {
+		return nil, errors.Wrap(err, "could not decode finalized checkpoint")
+	}
+	genesisRoot := tx.Bucket(blocksBucket).Get(genesisBlockRootKey)
+	if !bytes.Equal(checkpoint.Root, genesisRoot) && bkt.Get(checkpoint.Root) == nil {
+		problems = append(problems, &IndexError{
+			Index:   index,
+			Problem: fmt.Sprintf("finalized checkpoint block %#x is not indexed", checkpoint.Root),
+		})
+	}
+	return problems, nil
+}
+
+// RebuildIndices rebuilds the block slot and parent root indices from the blocks of the
+// database, and the finalized block roots index from the finalized checkpoint.
+func (kv *Store) RebuildIndices(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.RebuildIndices")
+	defer span.End()
+
+	if err := kv.db.Update(func(tx Tx) error {
+		for _, name := range [][]byte{blockSlotIndicesBucket, blockParentRootIndicesBucket} {
+			if err := clearBucket(tx.Bucket(name)); err != nil {
+				return err
+			}
+		}
+		return tx.Bucket(blocksBucket).ForEach(func(k []byte, v []byte) error {
+			if len(k) != 32 {
+				return nil
+			}
+			blk := &ethpb.SignedBeaconBlock{}
+			if err := decode(v, blk); err != nil {
+				return errors.Wrapf(err, "could not decode block %#x", k)
+			}
+			if blk.Block == nil {
+				return errors.Errorf("block %#x is empty", k)
+			}
+			return updateValueForIndices(ctx, createBlockIndicesFromBlock(ctx, blk.Block), k, tx)
+		})
+	}); err != nil {
+		return errors.Wrap(err, "could not rebuild block indices")
+	}
+	log.Info("Rebuilt block slot and parent root indices")
+
+	// The finalized index is rebuilt in a separate transaction, as it looks up blocks
+	// through the rebuilt slot index.
+	if err := kv.db.Update(func(tx Tx) error {
+		if err := clearBucket(tx.Bucket(finalizedBlockRootsIndexBucket)); err != nil {
+			return err
+		}
+		enc := tx.Bucket(checkpointBucket).Get(finalizedCheckpointKey)
+		if enc == nil {
+			return nil
+		}
+		checkpoint := &ethpb.Checkpoint{}
+		if err := decode(enc, checkpoint); err != nil {
+			return errors.Wrap(err, "could not decode finalized checkpoint")
+		}
+		return kv.updateFinalizedBlockRoots(ctx, tx, checkpoint)
+	}); err != nil {
+		return errors.Wrap(err, "could not rebuild finalized block roots index")
+	}
+	log.Info("Rebuilt finalized block roots index")
+	return nil
+}
+
+// clearBucket deletes all keys of the bucket.
+func clearBucket(b Bucket) error {
+	var keys [][]byte
+	if err := b.ForEach(func(k []byte, _ []byte) error {
+		keys = append(keys, k)
+		return nil
+	}); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
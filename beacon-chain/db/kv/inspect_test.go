@@ -0,0 +1,142 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestOpenReadOnly(t *testing.T) {
+	ctx := context.Background()
+	dir := path.Join(testutil.TempDir(), "inspect")
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if _, err := OpenReadOnly(dir); err == nil {
+		t.Error("Expected opening a missing database to fail")
+	}
+
+	db, err := NewKVStore(dir, cache.NewStateSummaryCache(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = OpenReadOnly(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	stats, err := db.BucketStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, s := range stats {
+		if s.Name == string(blocksBucket) {
+			found = true
+			if s.Keys != 1 || s.Size == 0 {
+				t.Errorf("Expected 1 block, received %d keys of %d bytes", s.Keys, s.Size)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected stats of the blocks bucket")
+	}
+	if err := db.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 2}}); err == nil {
+		t.Error("Expected writing to a read-only database to fail")
+	}
+}
+
+func TestStore_VerifyAndRebuildIndices(t *testing.T) {
+	slotsPerEpoch := int(params.BeaconConfig().SlotsPerEpoch)
+	db := setupDB(t)
+	ctx := context.Background()
+
+	if err := db.SaveGenesisBlockRoot(ctx, genesisBlockRoot); err != nil {
+		t.Fatal(err)
+	}
+	blks := makeBlocks(t, 0, slotsPerEpoch*3, genesisBlockRoot)
+	if err := db.SaveBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blks[slotsPerEpoch].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), root); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveFinalizedCheckpoint(ctx, &ethpb.Checkpoint{Epoch: 1, Root: root[:]}); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := db.VerifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("Expected consistent indices, received %v", problems)
+	}
+
+	firstRoot, err := stateutil.BlockRoot(blks[0].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := encode(&dbpb.FinalizedBlockRootContainer{ParentRoot: root[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Update(func(tx Tx) error {
+		if err := tx.Bucket(blockSlotIndicesBucket).Delete([]byte(fmt.Sprintf("%07d", 2))); err != nil {
+			return err
+		}
+		return tx.Bucket(finalizedBlockRootsIndexBucket).Put(firstRoot[:], enc)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = db.VerifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Errorf("Expected 2 inconsistencies, received %v", problems)
+	}
+
+	if err := db.RebuildIndices(ctx); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = db.VerifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Expected consistent indices after rebuilding, received %v", problems)
+	}
+	if !db.IsFinalizedBlock(ctx, firstRoot) {
+		t.Error("Expected the first block to be finalized after rebuilding")
+	}
+}
@@ -1,21 +1,17 @@
-// Package kv defines a bolt-db, key-value store implementation
-// of the Database interface defined by a Prysm beacon node.
+// Package kv defines a key-value store implementation of the Database
+// interface defined by a Prysm beacon node, backed by bolt-db or LevelDB.
 package kv
 
 import (
 	"os"
 	"path"
 	"sync"
-	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
-	prombolt "github.com/prysmaticlabs/prombbolt"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	"github.com/prysmaticlabs/prysm/beacon-chain/db/iface"
-	"github.com/prysmaticlabs/prysm/shared/params"
-	bolt "go.etcd.io/bbolt"
 )
 
 var _ = iface.Database(&Store{})
@@ -24,70 +20,71 @@ const (
 	// VotesCacheSize with 1M validators will be 8MB.
 	VotesCacheSize = 1 << 23
 	// NumOfVotes specifies the vote cache size.
-	NumOfVotes       = 1 << 20
-	databaseFileName = "beaconchain.db"
-	boltAllocSize    = 8 * 1024 * 1024
+	NumOfVotes = 1 << 20
 )
 
 // BlockCacheSize specifies 1000 slots worth of blocks cached, which
 // would be approximately 2MB
 var BlockCacheSize = int64(1 << 21)
 
+// Config for the beacon chain database.
+type Config struct {
+	// Engine is the key-value engine to open the database with. If empty, the engine
+	// of the existing database is used, or bolt for a new database.
+	Engine string
+	// IncrementalBackups records the keys changed since the last backup, so that
+	// backups only write those changes. Otherwise every backup is a full backup.
+	IncrementalBackups bool
+	// BackupFullInterval is the number of incremental backups to write before writing
+	// a full backup again.
+	BackupFullInterval int
+	// BackupRetention is the number of most recent backups to keep, along with the
+	// backups they build on. 0 keeps all backups.
+	BackupRetention int
+}
+
 // Store defines an implementation of the Prysm Database interface
-// using BoltDB as the underlying persistent kv-store for eth2.
+// using a key-value engine as the underlying persistent kv-store for eth2.
 type Store struct {
-	db                  *bolt.DB
+	db                  Engine
+	engine              string
 	databasePath        string
 	blockCache          *ristretto.Cache
 	validatorIndexCache *ristretto.Cache
 	stateSlotBitLock    sync.Mutex
 	blockSlotBitLock    sync.Mutex
+	backupLock          sync.Mutex
 	stateSummaryCache   *cache.StateSummaryCache
+	cfg                 *Config
+	outboxTopics        map[string]bool
+	outboxLock          sync.RWMutex
 }
 
-// NewKVStore initializes a new boltDB key-value store at the directory
-// path specified, creates the kv-buckets based on the schema, and stores
-// an open connection db object as a property of the Store struct.
-func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*Store, error) {
+// NewKVStore initializes a new key-value store at the directory path
+// specified, creates the kv-buckets based on the schema, and stores
+// an open connection db object as a property of the Store struct. A nil
+// config opens the database with the default options.
+func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache, cfg *Config) (*Store, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
 	if err := os.MkdirAll(dirPath, 0700); err != nil {
 		return nil, err
 	}
-	datafile := path.Join(dirPath, databaseFileName)
-	boltDB, err := bolt.Open(datafile, params.BeaconIoConfig().ReadWritePermissions, &bolt.Options{Timeout: 1 * time.Second, InitialMmapSize: 10e6})
+	engineName, err := selectEngine(cfg.Engine, dirPath)
 	if err != nil {
-		if err == bolt.ErrTimeout {
-			return nil, errors.New("cannot obtain database lock, database may be in use by another process")
-		}
 		return nil, err
 	}
-	boltDB.AllocSize = boltAllocSize
-	blockCache, err := ristretto.NewCache(&ristretto.Config{
-		NumCounters: 1000,           // number of keys to track frequency of (1000).
-		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
-		BufferItems: 64,             // number of keys per Get buffer.
-	})
+	engine, err := openEngine(engineName, dirPath, false /* readOnly */)
 	if err != nil {
 		return nil, err
 	}
-
-	validatorCache, err := ristretto.NewCache(&ristretto.Config{
-		NumCounters: NumOfVotes,     // number of keys to track frequency of (1M).
-		MaxCost:     VotesCacheSize, // maximum cost of cache (8MB).
-		BufferItems: 64,             // number of keys per Get buffer.
-	})
+	kv, err := newStore(engine, engineName, dirPath, stateSummaryCache, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	kv := &Store{
-		db:                  boltDB,
-		databasePath:        dirPath,
-		blockCache:          blockCache,
-		validatorIndexCache: validatorCache,
-		stateSummaryCache:   stateSummaryCache,
-	}
-
-	if err := kv.db.Update(func(tx *bolt.Tx) error {
+	if err := kv.db.Update(func(tx Tx) error {
 		return createBuckets(
 			tx,
 			attestationsBucket,
@@ -104,6 +101,7 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 			archivedValidatorParticipationBucket,
 			powchainBucket,
 			stateSummaryBucket,
+			stateDiffBucket,
 			archivedIndexRootBucket,
 			slotsHasObjectBucket,
 			// Indices buckets.
@@ -117,31 +115,84 @@ func NewKVStore(dirPath string, stateSummaryCache *cache.StateSummaryCache) (*St
 			finalizedBlockRootsIndexBucket,
 			// New State Management service bucket.
 			newStateServiceCompatibleBucket,
+			backupChangeLogBucket,
+			outboxBucket,
+			outboxOffsetsBucket,
 		)
 	}); err != nil {
 		return nil, err
 	}
+	if cfg.IncrementalBackups {
+		kv.db = changeTrackingEngine{engine}
+	} else if err := kv.db.Update(func(tx Tx) error {
+		// Changes are not tracked from now on, so the next backup must be a full backup.
+		return tx.Bucket(backupChangeLogBucket).Delete(changeLogLastBackupKey)
+	}); err != nil {
+		return nil, err
+	}
+	if err := kv.migrate(); err != nil {
+		return nil, errors.Wrap(err, "could not migrate database schema")
+	}
 
-	err = prometheus.Register(createBoltCollector(kv.db))
+	if e, ok := engine.(*boltEngine); ok {
+		err = prometheus.Register(e.collector())
+	}
 
 	return kv, err
 }
 
+// newStore returns a store on the opened engine with empty caches.
+func newStore(
+	engine Engine,
+	engineName string,
+	dirPath string,
+	stateSummaryCache *cache.StateSummaryCache,
+	cfg *Config,
+) (*Store, error) {
+	blockCache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 1000,           // number of keys to track frequency of (1000).
+		MaxCost:     BlockCacheSize, // maximum cost of cache (1000 Blocks).
+		BufferItems: 64,             // number of keys per Get buffer.
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	validatorCache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: NumOfVotes,     // number of keys to track frequency of (1M).
+		MaxCost:     VotesCacheSize, // maximum cost of cache (8MB).
+		BufferItems: 64,             // number of keys per Get buffer.
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		db:                  engine,
+		engine:              engineName,
+		databasePath:        dirPath,
+		blockCache:          blockCache,
+		validatorIndexCache: validatorCache,
+		stateSummaryCache:   stateSummaryCache,
+		cfg:                 cfg,
+	}, nil
+}
+
 // ClearDB removes the previously stored database in the data directory.
 func (kv *Store) ClearDB() error {
 	if _, err := os.Stat(kv.databasePath); os.IsNotExist(err) {
 		return nil
 	}
-	prometheus.Unregister(createBoltCollector(kv.db))
-	if err := os.Remove(path.Join(kv.databasePath, databaseFileName)); err != nil {
+	kv.unregisterCollector()
+	if err := os.RemoveAll(path.Join(kv.databasePath, engineFiles[kv.engine])); err != nil {
 		return errors.Wrap(err, "could not remove database file")
 	}
 	return nil
 }
 
-// Close closes the underlying BoltDB database.
+// Close closes the underlying database engine.
 func (kv *Store) Close() error {
-	prometheus.Unregister(createBoltCollector(kv.db))
+	kv.unregisterCollector()
 	return kv.db.Close()
 }
 
@@ -150,7 +201,7 @@ func (kv *Store) DatabasePath() string {
 	return kv.databasePath
 }
 
-func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
+func createBuckets(tx Tx, buckets ...[]byte) error {
 	for _, bucket := range buckets {
 		if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
 			return err
@@ -159,7 +210,13 @@ func createBuckets(tx *bolt.Tx, buckets ...[]byte) error {
 	return nil
 }
 
-// createBoltCollector returns a prometheus collector specifically configured for boltdb.
-func createBoltCollector(db *bolt.DB) prometheus.Collector {
-	return prombolt.New("boltDB", db)
+// unregisterCollector unregisters the prometheus collector of a bolt engine.
+func (kv *Store) unregisterCollector() {
+	e := kv.db
+	if t, ok := e.(changeTrackingEngine); ok {
+		e = t.Engine
+	}
+	if b, ok := e.(*boltEngine); ok {
+		prometheus.Unregister(b.collector())
+	}
 }
@@ -14,6 +14,11 @@ import (
 
 // setupDB instantiates and returns a Store instance.
 func setupDB(t testing.TB) *Store {
+	return setupDBWithConfig(t, nil)
+}
+
+// setupDBWithConfig instantiates and returns a Store instance with the config.
+func setupDBWithConfig(t testing.TB, cfg *Config) *Store {
 	randPath, err := rand.Int(rand.Reader, big.NewInt(1000000))
 	if err != nil {
 		t.Fatalf("Could not generate random file path: %v", err)
@@ -22,7 +27,7 @@ func setupDB(t testing.TB) *Store {
 	if err := os.RemoveAll(p); err != nil {
 		t.Fatalf("Failed to remove directory: %v", err)
 	}
-	db, err := NewKVStore(p, cache.NewStateSummaryCache())
+	db, err := NewKVStore(p, cache.NewStateSummaryCache(), cfg)
 	if err != nil {
 		t.Fatalf("Failed to instantiate DB: %v", err)
 	}
@@ -0,0 +1,85 @@
+package kv
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/migration"
+	log "github.com/sirupsen/logrus"
+)
+
+// migrations upgrade the schema of the beacon chain database, ordered by version. The
+// schema version is recorded in the chain metadata bucket. Released migrations must
+// never be modified, schema changes are appended as a new migration instead.
+var migrations = []*migration.Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// Databases created before schema versioning already have the initial schema.
+		Up: func(migration.Tx, []byte) ([]byte, error) {
+			return nil, nil
+		},
+	},
+}
+
+// PendingMigrations returns the schema version of the beacon chain database in the given
+// directory and the migrations which have not been applied to it, without modifying it.
+func PendingMigrations(dirPath string) (uint64, []*migration.Migration, error) {
+	name, err := DetectEngine(dirPath)
+	if err != nil {
+		return 0, nil, err
+	}
+	if name == "" {
+		return 0, nil, errors.Errorf("could not find database in %s", dirPath)
+	}
+	engine, err := openEngine(name, dirPath, true /* readOnly */)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() {
+		if err := engine.Close(); err != nil {
+			log.WithError(err).Error("Failed to close database")
+		}
+	}()
+	return migration.Pending(migrationDB{engine}, chainMetadataBucket, migrations)
+}
+
+// migrate applies the pending schema migrations to the database.
+func (kv *Store) migrate() error {
+	return migration.Run(migrationDB{kv.db}, chainMetadataBucket, migrations)
+}
+
+// migrationDB adapts an engine to the database interface of the migration package.
+type migrationDB struct {
+	engine Engine
+}
+
+func (d migrationDB) View(fn func(tx migration.Tx) error) error {
+	return d.engine.View(func(tx Tx) error {
+		return fn(migrationTx{tx})
+	})
+}
+
+func (d migrationDB) Update(fn func(tx migration.Tx) error) error {
+	return d.engine.Update(func(tx Tx) error {
+		return fn(migrationTx{tx})
+	})
+}
+
+type migrationTx struct {
+	tx Tx
+}
+
+func (t migrationTx) Bucket(name []byte) migration.Bucket {
+	bkt := t.tx.Bucket(name)
+	if bkt == nil {
+		return nil
+	}
+	return bkt
+}
+
+func (t migrationTx) CreateBucketIfNotExists(name []byte) (migration.Bucket, error) {
+	bkt, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return bkt, nil
+}
@@ -0,0 +1,31 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/migration"
+)
+
+func TestStore_MigratesSchemaOnOpen(t *testing.T) {
+	db := setupDB(t)
+	latest := migration.Latest(migrations)
+	if err := db.db.View(func(tx Tx) error {
+		if v := migration.Version(migrationTx{tx}, chainMetadataBucket); v != latest {
+			t.Errorf("Expected schema version %d, received %d", latest, v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	version, pending, err := PendingMigrations(db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest || len(pending) != 0 {
+		t.Errorf("Expected schema version %d without pending migrations, received %d with %d", latest, version, len(pending))
+	}
+}
@@ -0,0 +1,216 @@
+package kv
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"go.opencensus.io/trace"
+)
+
+// The outbox topics of the saved objects.
+const (
+	OutboxBlocks       = "blocks"
+	OutboxAttestations = "attestations"
+	OutboxSlashings    = "slashings"
+	OutboxExits        = "exits"
+	OutboxStates       = "states"
+)
+
+// The kinds of the outbox messages, stored as the first byte of an outbox entry.
+const (
+	outboxBlock byte = iota + 1
+	outboxAttestation
+	outboxProposerSlashing
+	outboxAttesterSlashing
+	outboxVoluntaryExit
+	outboxState
+)
+
+var outboxKindTopics = map[byte]string{
+	outboxBlock:            OutboxBlocks,
+	outboxAttestation:      OutboxAttestations,
+	outboxProposerSlashing: OutboxSlashings,
+	outboxAttesterSlashing: OutboxSlashings,
+	outboxVoluntaryExit:    OutboxExits,
+	outboxState:            OutboxStates,
+}
+
+// OutboxEntry is a saved object recorded in the outbox, to be exported.
+type OutboxEntry struct {
+	Offset uint64
+	Topic  string
+	// Key is the root the object is saved under, or the hash tree root of attestations.
+	Key     []byte
+	Message proto.Message
+}
+
+// EnableOutbox records the objects of the topics in the outbox, in the same transaction as
+// they are saved. Entries are kept until they are pruned with PruneOutbox.
+func (kv *Store) EnableOutbox(topics []string) {
+	kv.outboxLock.Lock()
+	defer kv.outboxLock.Unlock()
+	kv.outboxTopics = make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		kv.outboxTopics[topic] = true
+	}
+}
+
+// outboxEnabled returns true if the objects of the topic are recorded in the outbox.
+func (kv *Store) outboxEnabled(topic string) bool {
+	kv.outboxLock.RLock()
+	defer kv.outboxLock.RUnlock()
+	return kv.outboxTopics[topic]
+}
+
+// addToOutbox records the encoded object of the kind at the next offset of the outbox, if its
+// topic is enabled.
+func (kv *Store) addToOutbox(tx Tx, kind byte, key [32]byte, enc []byte) error {
+	if !kv.outboxEnabled(outboxKindTopics[kind]) {
+		return nil
+	}
+	offsets := tx.Bucket(outboxOffsetsBucket)
+	var offset uint64
+	if v := offsets.Get(nextOutboxOffsetKey); v != nil {
+		offset = binary.BigEndian.Uint64(v)
+	}
+	value := make([]byte, 0, 1+len(key)+len(enc))
+	value = append(value, kind)
+	value = append(value, key[:]...)
+	value = append(value, enc...)
+	if err := tx.Bucket(outboxBucket).Put(outboxOffsetKey(offset), value); err != nil {
+		return err
+	}
+	return offsets.Put(nextOutboxOffsetKey, outboxOffsetKey(offset+1))
+}
+
+// addAttestationToOutbox records the attestation in the outbox, keyed by its hash tree root.
+func (kv *Store) addAttestationToOutbox(tx Tx, att *ethpb.Attestation) error {
+	if !kv.outboxEnabled(OutboxAttestations) {
+		return nil
+	}
+	root, err := ssz.HashTreeRoot(att)
+	if err != nil {
+		return err
+	}
+	enc, err := encode(att)
+	if err != nil {
+		return err
+	}
+	return kv.addToOutbox(tx, outboxAttestation, root, enc)
+}
+
+// OutboxEntries returns up to limit outbox entries, from the offset on.
+func (kv *Store) OutboxEntries(ctx context.Context, offset uint64, limit int) ([]*OutboxEntry, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.OutboxEntries")
+	defer span.End()
+
+	var entries []*OutboxEntry
+	err := kv.db.View(func(tx Tx) error {
+		c := tx.Bucket(outboxBucket).Cursor()
+		for k, v := c.Seek(outboxOffsetKey(offset)); k != nil && len(entries) < limit; k, v = c.Next() {
+			entry, err := decodeOutboxEntry(k, v)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	})
+	return entries, err
+}
+
+func decodeOutboxEntry(k []byte, v []byte) (*OutboxEntry, error) {
+	offset := binary.BigEndian.Uint64(k)
+	if len(v) < 33 {
+		return nil, errors.Errorf("malformed outbox entry at offset %d", offset)
+	}
+	var msg proto.Message
+	switch v[0] {
+	case outboxBlock:
+		msg = &ethpb.SignedBeaconBlock{}
+	case outboxAttestation:
+		msg = &ethpb.Attestation{}
+	case outboxProposerSlashing:
+		msg = &ethpb.ProposerSlashing{}
+	case outboxAttesterSlashing:
+		msg = &ethpb.AttesterSlashing{}
+	case outboxVoluntaryExit:
+		msg = &ethpb.VoluntaryExit{}
+	case outboxState:
+		msg = &pb.BeaconState{}
+	default:
+		return nil, errors.Errorf("unknown kind %d of outbox entry at offset %d", v[0], offset)
+	}
+	if err := decode(v[33:], msg); err != nil {
+		return nil, errors.Wrapf(err, "could not decode outbox entry at offset %d", offset)
+	}
+	return &OutboxEntry{
+		Offset:  offset,
+		Topic:   outboxKindTopics[v[0]],
+		Key:     append([]byte{}, v[1:33]...),
+		Message: msg,
+	}, nil
+}
+
+// OutboxOffset returns the offset of the next outbox entry to deliver to the consumer.
+func (kv *Store) OutboxOffset(ctx context.Context, consumer string) (uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.OutboxOffset")
+	defer span.End()
+
+	var offset uint64
+	err := kv.db.View(func(tx Tx) error {
+		if v := tx.Bucket(outboxOffsetsBucket).Get(outboxConsumerKey(consumer)); v != nil {
+			offset = binary.BigEndian.Uint64(v)
+		}
+		return nil
+	})
+	return offset, err
+}
+
+// SaveOutboxOffset saves the offset of the next outbox entry to deliver to the consumer, once
+// the entries before it are acknowledged.
+func (kv *Store) SaveOutboxOffset(ctx context.Context, consumer string, offset uint64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveOutboxOffset")
+	defer span.End()
+
+	return kv.db.Update(func(tx Tx) error {
+		return tx.Bucket(outboxOffsetsBucket).Put(outboxConsumerKey(consumer), outboxOffsetKey(offset))
+	})
+}
+
+// PruneOutbox deletes the outbox entries before the offset.
+func (kv *Store) PruneOutbox(ctx context.Context, offset uint64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneOutbox")
+	defer span.End()
+
+	return kv.db.Update(func(tx Tx) error {
+		bkt := tx.Bucket(outboxBucket)
+		var keys [][]byte
+		c := bkt.Cursor()
+		for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) < offset; k, _ = c.Next() {
+			keys = append(keys, k)
+		}
+		for _, k := range keys {
+			if err := bkt.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// outboxOffsetKey encodes the offset big endian, so that the entries are ordered by offset.
+func outboxOffsetKey(offset uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, offset)
+	return k
+}
+
+func outboxConsumerKey(consumer string) []byte {
+	return append([]byte("consumer-"), consumer...)
+}
@@ -0,0 +1,133 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/go-ssz"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+)
+
+func TestStore_Outbox_RecordsEnabledTopics(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	exit := &ethpb.VoluntaryExit{Epoch: 1}
+	if err := db.SaveVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+	db.EnableOutbox([]string{OutboxBlocks, OutboxExits})
+	blks := []*ethpb.SignedBeaconBlock{
+		{Block: &ethpb.BeaconBlock{Slot: 1, ParentRoot: make([]byte, 32)}},
+		{Block: &ethpb.BeaconBlock{Slot: 2, ParentRoot: make([]byte, 32)}},
+	}
+	if err := db.SaveBlocks(ctx, blks); err != nil {
+		t.Fatal(err)
+	}
+	// Attestations are not recorded, as their topic is not enabled.
+	att := &ethpb.Attestation{
+		Data: &ethpb.AttestationData{
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		AggregationBits: bitfield.Bitlist{0b00000001, 0b1},
+	}
+	if err := db.SaveAttestation(ctx, att); err != nil {
+		t.Fatal(err)
+	}
+	// Exits which are already saved are not recorded again.
+	if err := db.SaveVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+	exit = &ethpb.VoluntaryExit{Epoch: 2}
+	if err := db.SaveVoluntaryExit(ctx, exit); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := db.OutboxEntries(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 2 blocks and 1 exit in the outbox, received %d entries", len(entries))
+	}
+	for i, topic := range []string{OutboxBlocks, OutboxBlocks, OutboxExits} {
+		if entries[i].Offset != uint64(i) || entries[i].Topic != topic {
+			t.Errorf("Expected entry %d of topic %s, received offset %d of topic %s", i, topic, entries[i].Offset, entries[i].Topic)
+		}
+	}
+	root, err := ssz.HashTreeRoot(blks[1].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != bytesutil.ToBytes32(entries[1].Key) || !proto.Equal(blks[1], entries[1].Message) {
+		t.Errorf("Expected the block keyed by its root, received %v", entries[1])
+	}
+	if !proto.Equal(exit, entries[2].Message) {
+		t.Errorf("Wanted %v, received %v", exit, entries[2].Message)
+	}
+
+	entries, err = db.OutboxEntries(ctx, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Offset != 1 {
+		t.Errorf("Expected the entry at offset 1, received %v", entries)
+	}
+}
+
+func TestStore_Outbox_OffsetsAndPrune(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+
+	db.EnableOutbox([]string{OutboxBlocks})
+	for i := uint64(1); i <= 3; i++ {
+		if err := db.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: i, ParentRoot: make([]byte, 32)}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	offset, err := db.OutboxOffset(ctx, "ndjson:/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 0 {
+		t.Errorf("Expected offset 0 of a new consumer, received %d", offset)
+	}
+	if err := db.SaveOutboxOffset(ctx, "ndjson:/tmp", 2); err != nil {
+		t.Fatal(err)
+	}
+	offset, err = db.OutboxOffset(ctx, "ndjson:/tmp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 2 {
+		t.Errorf("Expected offset 2, received %d", offset)
+	}
+
+	if err := db.PruneOutbox(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := db.OutboxEntries(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Offset != 2 {
+		t.Fatalf("Expected only the entry at offset 2 after pruning, received %v", entries)
+	}
+
+	// Offsets keep increasing after pruning.
+	if err := db.SaveBlock(ctx, &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 4, ParentRoot: make([]byte, 32)}}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = db.OutboxEntries(ctx, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Offset != 3 {
+		t.Errorf("Expected the new entry at offset 3, received %v", entries)
+	}
+}
@@ -0,0 +1,263 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	dbpb "github.com/prysmaticlabs/prysm/proto/beacon/db"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	log "github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// PruneHistory deletes the blocks, state summaries and attestations of the slots before
+// retainSlot, and the blocks between retainSlot and the finalized block which are not its
+// ancestors. The retained range is extended back to the archived point before retainSlot,
+// as states after it are regenerated by replaying blocks from that archived point. The
+// blocks and state summaries of archived points, the genesis block and the origin block
+// are kept, so that the archived states remain available.
+//
+// The pruned slots are recorded, so that every call only visits the slots which were not
+// pruned yet.
+func (kv *Store) PruneHistory(ctx context.Context, finalizedRoot [32]byte, retainSlot uint64) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.PruneHistory")
+	defer span.End()
+
+	finalized, err := kv.Block(ctx, finalizedRoot)
+	if err != nil {
+		return err
+	}
+	if finalized == nil || finalized.Block == nil {
+		return errors.Errorf("missing finalized block %#x", finalizedRoot)
+	}
+	finalizedSlot := finalized.Block.Slot
+	if retainSlot > finalizedSlot {
+		retainSlot = finalizedSlot
+	}
+	retainSlot -= retainSlot % params.BeaconConfig().SlotsPerArchivedPoint
+
+	var prunedSlot, orphansPrunedSlot uint64
+	keep := make(map[[32]byte]bool)
+	var pruned, orphans [][32]byte
+	if err := kv.db.View(func(tx Tx) error {
+		prunedSlot = prunedSlotValue(tx, prunedSlotKey)
+		orphansPrunedSlot = prunedSlotValue(tx, orphansPrunedSlotKey)
+		if err := tx.Bucket(archivedIndexRootBucket).ForEach(func(_ []byte, v []byte) error {
+			keep[bytesutil.ToBytes32(v)] = true
+			return nil
+		}); err != nil {
+			return err
+		}
+		for _, k := range [][]byte{genesisBlockRootKey, originBlockRootKey} {
+			if root := tx.Bucket(blocksBucket).Get(k); root != nil {
+				keep[bytesutil.ToBytes32(root)] = true
+			}
+		}
+
+		if retainSlot > prunedSlot {
+			for _, root := range blockRootsInSlotRange(tx, prunedSlot, retainSlot) {
+				if !keep[root] {
+					pruned = append(pruned, root)
+				}
+			}
+		}
+		start := orphansPrunedSlot
+		if start < retainSlot {
+			start = retainSlot
+		}
+		if finalizedSlot <= start {
+			return nil
+		}
+		ancestors, err := ancestorRoots(tx, finalizedRoot, start)
+		if err != nil {
+			return err
+		}
+		for _, root := range blockRootsInSlotRange(tx, start, finalizedSlot) {
+			if !ancestors[root] && !keep[root] {
+				orphans = append(orphans, root)
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := kv.pruneBlocks(ctx, append(pruned, orphans...)); err != nil {
+		return errors.Wrap(err, "could not prune blocks")
+	}
+	prunedAttestations, err := kv.pruneAttestations(ctx, helpers.SlotToEpoch(retainSlot))
+	if err != nil {
+		return errors.Wrap(err, "could not prune attestations")
+	}
+	if err := kv.db.Update(func(tx Tx) error {
+		bkt := tx.Bucket(chainMetadataBucket)
+		if retainSlot > prunedSlot {
+			if err := bkt.Put(prunedSlotKey, bytesutil.Bytes8(retainSlot)); err != nil {
+				return err
+			}
+		}
+		if finalizedSlot > orphansPrunedSlot {
+			return bkt.Put(orphansPrunedSlotKey, bytesutil.Bytes8(finalizedSlot))
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(pruned) > 0 || len(orphans) > 0 || prunedAttestations > 0 {
+		log.WithFields(log.Fields{
+			"retainedSlot": retainSlot,
+			"blocks":       len(pruned),
+			"forkBlocks":   len(orphans),
+			"attestations": prunedAttestations,
+		}).Info("Pruned database history")
+	}
+	return nil
+}
+
+// prunedSlotValue returns the slot stored under the key of the chain metadata bucket, or 0.
+func prunedSlotValue(tx Tx, key []byte) uint64 {
+	v := tx.Bucket(chainMetadataBucket).Get(key)
+	if len(v) != 8 {
+		return 0
+	}
+	return bytesutil.FromBytes8(v)
+}
+
+// blockRootsInSlotRange returns the roots of the blocks indexed at the slots from start up
+// to, but not including, end.
+func blockRootsInSlotRange(tx Tx, start uint64, end uint64) [][32]byte {
+	min := []byte(fmt.Sprintf("%07d", start))
+	max := []byte(fmt.Sprintf("%07d", end))
+	var roots [][32]byte
+	c := tx.Bucket(blockSlotIndicesBucket).Cursor()
+	for k, v := c.Seek(min); k != nil && bytes.Compare(k, max) < 0; k, v = c.Next() {
+		for i := 0; i+32 <= len(v); i += 32 {
+			roots = append(roots, bytesutil.ToBytes32(v[i:i+32]))
+		}
+	}
+	return roots
+}
+
+// ancestorRoots returns the roots of the block and its ancestors down to the slot.
+func ancestorRoots(tx Tx, root [32]byte, slot uint64) (map[[32]byte]bool, error) {
+	ancestors := make(map[[32]byte]bool)
+	bkt := tx.Bucket(blocksBucket)
+	for {
+		enc := bkt.Get(root[:])
+		if enc == nil {
+			return ancestors, nil
+		}
+		blk := &ethpb.SignedBeaconBlock{}
+		if err := decode(enc, blk); err != nil {
+			return nil, err
+		}
+		if blk.Block == nil || blk.Block.Slot < slot {
+			return ancestors, nil
+		}
+		ancestors[root] = true
+		root = bytesutil.ToBytes32(blk.Block.ParentRoot)
+	}
+}
+
+// pruneBlocks deletes the blocks, along with their indices and state summaries, in batches.
+func (kv *Store) pruneBlocks(ctx context.Context, roots [][32]byte) error {
+	for len(roots) > 0 {
+		batch := roots
+		if len(batch) > copyBatchSize {
+			batch = batch[:copyBatchSize]
+		}
+		roots = roots[len(batch):]
+		if err := kv.db.Update(func(tx Tx) error {
+			for _, root := range batch {
+				if err := kv.pruneBlock(ctx, tx, root); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneBlock deletes the block, its indices and its state summary.
+func (kv *Store) pruneBlock(ctx context.Context, tx Tx, root [32]byte) error {
+	bkt := tx.Bucket(blocksBucket)
+	enc := bkt.Get(root[:])
+	if enc == nil {
+		return nil
+	}
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := decode(enc, blk); err != nil {
+		return err
+	}
+	if err := deleteValueForIndices(ctx, createBlockIndicesFromBlock(ctx, blk.Block), root[:], tx); err != nil {
+		return errors.Wrap(err, "could not delete root for DB indices")
+	}
+	// The slot stays marked as long as other blocks of the slot remain.
+	if tx.Bucket(blockSlotIndicesBucket).Get([]byte(fmt.Sprintf("%07d", blk.Block.Slot))) == nil {
+		if err := kv.clearBlockSlotBitField(ctx, tx, blk.Block.Slot); err != nil {
+			return err
+		}
+	}
+	kv.blockCache.Del(string(root[:]))
+	if err := tx.Bucket(finalizedBlockRootsIndexBucket).Delete(root[:]); err != nil {
+		return err
+	}
+	if err := tx.Bucket(stateSummaryBucket).Delete(root[:]); err != nil {
+		return err
+	}
+	return bkt.Delete(root[:])
+}
+
+// pruneAttestations deletes the attestations which target an epoch before the given epoch,
+// and returns the number of attestations deleted.
+func (kv *Store) pruneAttestations(ctx context.Context, epoch uint64) (int, error) {
+	var roots [][32]byte
+	if err := kv.db.View(func(tx Tx) error {
+		return tx.Bucket(attestationTargetEpochIndicesBucket).ForEach(func(k []byte, v []byte) error {
+			if len(k) != 8 || bytesutil.FromBytes8(k) >= epoch {
+				return nil
+			}
+			for i := 0; i+32 <= len(v); i += 32 {
+				roots = append(roots, bytesutil.ToBytes32(v[i:i+32]))
+			}
+			return nil
+		})
+	}); err != nil {
+		return 0, err
+	}
+	if len(roots) == 0 {
+		return 0, nil
+	}
+	if err := kv.db.Update(func(tx Tx) error {
+		bkt := tx.Bucket(attestationsBucket)
+		for _, root := range roots {
+			enc := bkt.Get(root[:])
+			if enc == nil {
+				continue
+			}
+			ac := &dbpb.AttestationContainer{}
+			if err := decode(enc, ac); err != nil {
+				return err
+			}
+			if err := deleteValueForIndices(ctx, createAttestationIndicesFromData(ctx, ac.Data), root[:], tx); err != nil {
+				return errors.Wrap(err, "could not delete root for DB indices")
+			}
+			if err := bkt.Delete(root[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return len(roots), nil
+}
@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestStore_PruneHistory(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	cfg := params.BeaconConfig().Copy()
+	cfg.SlotsPerArchivedPoint = 8
+	cfg.SlotsPerEpoch = 8
+	params.OverrideBeaconConfig(cfg)
+
+	db := setupDB(t)
+	ctx := context.Background()
+	if err := db.SaveGenesisBlockRoot(ctx, genesisBlockRoot); err != nil {
+		t.Fatal(err)
+	}
+	blks := makeBlocks(t, 0, 40, genesisBlockRoot)
+	forkParent, err := stateutil.BlockRoot(blks[27].Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fork := &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{Slot: 30, ParentRoot: forkParent[:]}}
+	if err := db.SaveBlocks(ctx, append(blks, fork)); err != nil {
+		t.Fatal(err)
+	}
+	roots := make([][32]byte, len(blks))
+	for i, blk := range blks {
+		roots[i], err = stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveStateSummary(ctx, &pb.StateSummary{Slot: blk.Block.Slot, Root: roots[i][:]}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	forkRoot, err := stateutil.BlockRoot(fork.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The block at slot 5 is kept as an archived point.
+	if err := db.SaveArchivedPointRoot(ctx, roots[4], 0); err != nil {
+		t.Fatal(err)
+	}
+	atts := make([]*ethpb.Attestation, 2)
+	for i, epoch := range []uint64{1, 2} {
+		atts[i] = &ethpb.Attestation{
+			Data: &ethpb.AttestationData{
+				Slot:            epoch * cfg.SlotsPerEpoch,
+				BeaconBlockRoot: make([]byte, 32),
+				Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+				Target:          &ethpb.Checkpoint{Epoch: epoch, Root: make([]byte, 32)},
+			},
+			AggregationBits: bitfield.Bitlist{0b00000001, 0b1},
+		}
+	}
+	if err := db.SaveAttestations(ctx, atts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Slot 20 is retained from the archived point at slot 16.
+	if err := db.PruneHistory(ctx, roots[39], 20); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, root := range roots {
+		slot := blks[i].Block.Slot
+		kept := slot >= 16 || slot == 5
+		if db.HasBlock(ctx, root) != kept {
+			t.Errorf("Expected block at slot %d to be kept: %v", slot, kept)
+		}
+		if db.HasStateSummary(ctx, root) != kept {
+			t.Errorf("Expected state summary at slot %d to be kept: %v", slot, kept)
+		}
+	}
+	if db.HasBlock(ctx, forkRoot) {
+		t.Error("Expected the fork block to be pruned")
+	}
+	for i, att := range atts {
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if db.HasAttestation(ctx, root) != (i == 1) {
+			t.Errorf("Expected attestation of epoch %d to be kept: %v", att.Data.Target.Epoch, i == 1)
+		}
+	}
+	problems, err := db.VerifyIndices(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Expected consistent indices after pruning, received %v", problems)
+	}
+
+	// Pruning again up to the same slots deletes nothing.
+	if err := db.PruneHistory(ctx, roots[39], 20); err != nil {
+		t.Fatal(err)
+	}
+	if !db.HasBlock(ctx, roots[15]) {
+		t.Error("Expected block at slot 16 to be kept")
+	}
+}
@@ -0,0 +1,88 @@
+package kv
+
+import (
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// preRestoreSuffix is appended to the file name of the database replaced by a restore.
+const preRestoreSuffix = ".pre-restore"
+
+// Restore replaces the database in the given directory with the backup. Incremental
+// backups are restored together with the backups they build on, which are looked up
+// in the directory of the backup. The restored database is validated against the
+// head block and finalized checkpoint recorded in the backup before it is swapped in. The replaced database is kept next to the restored one with the
+// .pre-restore suffix. The node must not be running while its database is restored.
+func Restore(backupPath string, dirPath string) error {
+	backup, err := readBackupInfo(backupPath)
+	if err != nil {
+		return err
+	}
+	chain, err := backupChain(backup)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dirPath, 0700); err != nil {
+		return err
+	}
+
+	// Make sure the current database is not in use and can be moved aside.
+	existing, err := DetectEngine(dirPath)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		movedPath := path.Join(dirPath, engineFiles[existing]+preRestoreSuffix)
+		if _, err := os.Stat(movedPath); err == nil {
+			return errors.Errorf("%s already exists, remove it before restoring", movedPath)
+		}
+		engine, err := openEngine(existing, dirPath, true /* readOnly */)
+		if err != nil {
+			return err
+		}
+		if err := engine.Close(); err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"backup":   backupPath,
+		"backups":  len(chain),
+		"headSlot": backup.headSlot,
+	}).Info("Restoring database")
+	restoredPath := path.Join(dirPath, databaseFileName+".restore")
+	if err := os.RemoveAll(restoredPath); err != nil {
+		return err
+	}
+	restored, err := openBoltEngine(restoredPath, false /* readOnly */)
+	if err != nil {
+		return err
+	}
+	err = restoreBackup(chain, restored)
+	if err == nil {
+		err = validateRestored(restored, backup)
+	}
+	if closeErr := restored.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		if rmErr := os.RemoveAll(restoredPath); rmErr != nil {
+			log.WithError(rmErr).Error("Failed to remove partially restored database")
+		}
+		return errors.Wrap(err, "could not restore backup")
+	}
+
+	if existing != "" {
+		if err := os.Rename(path.Join(dirPath, engineFiles[existing]), path.Join(dirPath, engineFiles[existing]+preRestoreSuffix)); err != nil {
+			return errors.Wrap(err, "could not move current database aside")
+		}
+	}
+	if err := os.Rename(restoredPath, path.Join(dirPath, databaseFileName)); err != nil {
+		return errors.Wrap(err, "could not swap in restored database")
+	}
+	log.WithField("path", path.Join(dirPath, databaseFileName)).Info("Restored database")
+	return nil
+}
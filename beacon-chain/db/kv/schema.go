@@ -11,6 +11,7 @@ var (
 	blocksBucket                         = []byte("blocks")
 	stateBucket                          = []byte("state")
 	stateSummaryBucket                   = []byte("state-summary")
+	stateDiffBucket                      = []byte("state-diff")
 	proposerSlashingsBucket              = []byte("proposer-slashings")
 	attesterSlashingsBucket              = []byte("attester-slashings")
 	voluntaryExitsBucket                 = []byte("voluntary-exits")
@@ -34,9 +35,17 @@ var (
 	attestationTargetEpochIndicesBucket = []byte("attestation-target-epoch-indices")
 	finalizedBlockRootsIndexBucket      = []byte("finalized-block-roots-index")
 
+	// Keys changed since the last backup.
+	backupChangeLogBucket = []byte("backup-change-log")
+
+	// Saved objects to be exported, and the offsets of their consumers.
+	outboxBucket        = []byte("outbox")
+	outboxOffsetsBucket = []byte("outbox-offsets")
+
 	// Specific item keys.
 	headBlockRootKey          = []byte("head-root")
 	genesisBlockRootKey       = []byte("genesis-root")
+	originBlockRootKey        = []byte("origin-root")
 	depositContractAddressKey = []byte("deposit-contract")
 	justifiedCheckpointKey    = []byte("justified-checkpoint")
 	finalizedCheckpointKey    = []byte("finalized-checkpoint")
@@ -44,6 +53,9 @@ var (
 	lastArchivedIndexKey      = []byte("last-archived")
 	savedBlockSlotsKey        = []byte("saved-block-slots")
 	savedStateSlotsKey        = []byte("saved-state-slots")
+	prunedSlotKey             = []byte("pruned-slot")
+	orphansPrunedSlotKey      = []byte("orphans-pruned-slot")
+	nextOutboxOffsetKey       = []byte("next-offset")
 
 	// New state management service compatibility bucket.
 	newStateServiceCompatibleBucket = []byte("new-state-compatible")
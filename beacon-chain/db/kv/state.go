@@ -11,7 +11,6 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
-	bolt "go.etcd.io/bbolt"
 	"go.opencensus.io/trace"
 )
 
@@ -21,7 +20,7 @@ func (kv *Store) State(ctx context.Context, blockRoot [32]byte) (*state.BeaconSt
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.State")
 	defer span.End()
 	var s *pb.BeaconState
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		bucket := tx.Bucket(stateBucket)
 		enc := bucket.Get(blockRoot[:])
 		if enc == nil {
@@ -46,7 +45,7 @@ func (kv *Store) HeadState(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HeadState")
 	defer span.End()
 	var s *pb.BeaconState
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		// Retrieve head block's signing root from blocks bucket,
 		// to look up what the head state is.
 		bucket := tx.Bucket(blocksBucket)
@@ -80,7 +79,7 @@ func (kv *Store) GenesisState(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.GenesisState")
 	defer span.End()
 	var s *pb.BeaconState
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		// Retrieve genesis block's signing root from blocks bucket,
 		// to look up what the genesis state is.
 		bucket := tx.Bucket(blocksBucket)
@@ -117,11 +116,14 @@ func (kv *Store) SaveState(ctx context.Context, state *state.BeaconState, blockR
 		return err
 	}
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(stateBucket)
 		if err := bucket.Put(blockRoot[:], enc); err != nil {
 			return err
 		}
+		if err := kv.addToOutbox(tx, outboxState, blockRoot, enc); err != nil {
+			return err
+		}
 		return kv.setStateSlotBitField(ctx, tx, state.Slot())
 	})
 }
@@ -142,7 +144,7 @@ func (kv *Store) SaveStates(ctx context.Context, states []*state.BeaconState, bl
 		}
 	}
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bucket := tx.Bucket(stateBucket)
 		for i, rt := range blockRoots {
 			if err := kv.setStateSlotBitField(ctx, tx, states[i].Slot()); err != nil {
@@ -152,6 +154,9 @@ func (kv *Store) SaveStates(ctx context.Context, states []*state.BeaconState, bl
 			if err != nil {
 				return err
 			}
+			if err := kv.addToOutbox(tx, outboxState, rt, multipleEncs[i]); err != nil {
+				return err
+			}
 		}
 		return nil
 	})
@@ -162,7 +167,7 @@ func (kv *Store) HasState(ctx context.Context, blockRoot [32]byte) bool {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HasState")
 	defer span.End()
 	var exists bool
-	if err := kv.db.View(func(tx *bolt.Tx) error {
+	if err := kv.db.View(func(tx Tx) error {
 		bucket := tx.Bucket(stateBucket)
 		exists = bucket.Get(blockRoot[:]) != nil
 		return nil
@@ -177,7 +182,7 @@ func (kv *Store) DeleteState(ctx context.Context, blockRoot [32]byte) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.DeleteState")
 	defer span.End()
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bkt.Get(genesisBlockRootKey)
 
@@ -226,7 +231,7 @@ func (kv *Store) DeleteStates(ctx context.Context, blockRoots [][32]byte) error
 		rootMap[blockRoot] = true
 	}
 
-	return kv.db.Update(func(tx *bolt.Tx) error {
+	return kv.db.Update(func(tx Tx) error {
 		bkt := tx.Bucket(blocksBucket)
 		genesisBlockRoot := bkt.Get(genesisBlockRootKey)
 
@@ -279,7 +284,7 @@ func createState(enc []byte) (*pb.BeaconState, error) {
 }
 
 // slotByBlockRoot retrieves the corresponding slot of the input block root.
-func slotByBlockRoot(ctx context.Context, tx *bolt.Tx, blockRoot []byte) (uint64, error) {
+func slotByBlockRoot(ctx context.Context, tx Tx, blockRoot []byte) (uint64, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.slotByBlockRoot")
 	defer span.End()
 
@@ -332,7 +337,7 @@ func (kv *Store) HighestSlotStates(ctx context.Context) ([]*state.BeaconState, e
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HighestSlotState")
 	defer span.End()
 	var states []*state.BeaconState
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		slotBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := slotBkt.Get(savedStateSlotsKey)
 		highestIndex, err := bytesutil.HighestBitIndex(savedSlots)
@@ -362,7 +367,7 @@ func (kv *Store) HighestSlotStatesBelow(ctx context.Context, slot uint64) ([]*st
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.HighestSlotStatesBelow")
 	defer span.End()
 	var states []*state.BeaconState
-	err := kv.db.View(func(tx *bolt.Tx) error {
+	err := kv.db.View(func(tx Tx) error {
 		slotBkt := tx.Bucket(slotsHasObjectBucket)
 		savedSlots := slotBkt.Get(savedStateSlotsKey)
 		if len(savedSlots) == 0 {
@@ -390,7 +395,7 @@ func (kv *Store) HighestSlotStatesBelow(ctx context.Context, slot uint64) ([]*st
 
 // statesAtSlotBitfieldIndex retrieves the states in DB given the input index. The index represents
 // the position of the slot bitfield the saved state maps to.
-func (kv *Store) statesAtSlotBitfieldIndex(ctx context.Context, tx *bolt.Tx, index int) ([]*state.BeaconState, error) {
+func (kv *Store) statesAtSlotBitfieldIndex(ctx context.Context, tx Tx, index int) ([]*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.statesAtSlotBitfieldIndex")
 	defer span.End()
 
@@ -439,7 +444,7 @@ func (kv *Store) statesAtSlotBitfieldIndex(ctx context.Context, tx *bolt.Tx, ind
 
 // setStateSlotBitField sets the state slot bit in DB.
 // This helps to track which slot has a saved state in db.
-func (kv *Store) setStateSlotBitField(ctx context.Context, tx *bolt.Tx, slot uint64) error {
+func (kv *Store) setStateSlotBitField(ctx context.Context, tx Tx, slot uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.setStateSlotBitField")
 	defer span.End()
 
@@ -459,7 +464,7 @@ func (kv *Store) setStateSlotBitField(ctx context.Context, tx *bolt.Tx, slot uin
 
 // clearStateSlotBitField clears the state slot bit in DB.
 // This helps to track which slot has a saved state in db.
-func (kv *Store) clearStateSlotBitField(ctx context.Context, tx *bolt.Tx, slot uint64) error {
+func (kv *Store) clearStateSlotBitField(ctx context.Context, tx Tx, slot uint64) error {
 	ctx, span := trace.StartSpan(ctx, "BeaconDB.clearStateSlotBitField")
 	defer span.End()
 
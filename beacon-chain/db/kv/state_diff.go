@@ -0,0 +1,57 @@
+package kv
+
+import (
+	"context"
+
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+)
+
+// SaveStateDiff stores the encoded diff of a state under the block root which was used to
+// generate the state, and deletes the full state stored under the block root, if any. The
+// diff is stored as is, the state is rebuilt from it by the caller.
+func (kv *Store) SaveStateDiff(ctx context.Context, blockRoot [32]byte, diff []byte) error {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.SaveStateDiff")
+	defer span.End()
+	if len(diff) == 0 {
+		return errors.New("empty state diff")
+	}
+
+	return kv.db.Update(func(tx Tx) error {
+		if err := tx.Bucket(stateDiffBucket).Put(blockRoot[:], snappy.Encode(nil, diff)); err != nil {
+			return err
+		}
+		bkt := tx.Bucket(stateBucket)
+		if bkt.Get(blockRoot[:]) == nil {
+			return nil
+		}
+		slot, err := slotByBlockRoot(ctx, tx, blockRoot[:])
+		if err != nil {
+			return err
+		}
+		if err := kv.clearStateSlotBitField(ctx, tx, slot); err != nil {
+			return err
+		}
+		return bkt.Delete(blockRoot[:])
+	})
+}
+
+// StateDiff returns the encoded state diff stored under the block root, or nil if the state
+// of the block root is not stored as a diff.
+func (kv *Store) StateDiff(ctx context.Context, blockRoot [32]byte) ([]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "BeaconDB.StateDiff")
+	defer span.End()
+
+	var diff []byte
+	err := kv.db.View(func(tx Tx) error {
+		enc := tx.Bucket(stateDiffBucket).Get(blockRoot[:])
+		if enc == nil {
+			return nil
+		}
+		var err error
+		diff, err = snappy.Decode(nil, enc)
+		return err
+	})
+	return diff, err
+}
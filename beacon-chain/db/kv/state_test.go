@@ -512,3 +512,33 @@ func TestStore_GenesisState_CanGetHighestBelow(t *testing.T) {
 		t.Errorf("Did not retrieve saved state: %v != %v", highest, genesisState.InnerStateUnsafe())
 	}
 }
+
+func TestStore_SaveStateDiff(t *testing.T) {
+	db := setupDB(t)
+	ctx := context.Background()
+	r := [32]byte{'A'}
+
+	diff, err := db.StateDiff(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		t.Error("Expected no state diff")
+	}
+	if err := db.SaveState(ctx, testutil.NewBeaconState(), r); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveStateDiff(ctx, r, []byte("diff")); err != nil {
+		t.Fatal(err)
+	}
+	if db.HasState(ctx, r) {
+		t.Error("Expected the full state to be replaced by the diff")
+	}
+	diff, err = db.StateDiff(ctx, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(diff) != "diff" {
+		t.Errorf("Expected the saved diff, received %q", diff)
+	}
+}
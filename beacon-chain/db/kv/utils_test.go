@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
-	bolt "go.etcd.io/bbolt"
 )
 
 func Test_deleteValueForIndices(t *testing.T) {
@@ -122,7 +121,7 @@ func Test_deleteValueForIndices(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := db.db.Update(func(tx *bolt.Tx) error {
+			err := db.db.Update(func(tx Tx) error {
 				for k, idx := range tt.inputIndices {
 					bkt := tx.Bucket([]byte(k))
 					if err := bkt.Put(idx, tt.inputIndices[k]); err != nil {
@@ -27,7 +27,7 @@ func SetupDB(t testing.TB) (db.Database, *cache.StateSummaryCache) {
 		t.Fatalf("failed to remove directory: %v", err)
 	}
 	sc := cache.NewStateSummaryCache()
-	s, err := kv.NewKVStore(p, sc)
+	s, err := kv.NewKVStore(p, sc, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
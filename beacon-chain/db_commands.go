@@ -0,0 +1,302 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/kv"
+	"github.com/prysmaticlabs/prysm/beacon-chain/node"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/migration"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	convertFromFlag = &cli.StringFlag{
+		Name:  "from",
+		Usage: "The key-value engine of the existing database. Defaults to the detected engine",
+	}
+	convertToFlag = &cli.StringFlag{
+		Name:     "to",
+		Usage:    "The key-value engine to convert the database to, bolt or leveldb",
+		Required: true,
+	}
+	dumpRootFlag = &cli.StringFlag{
+		Name:  "root",
+		Usage: "The hex encoded block root of the object to dump",
+	}
+	dumpSlotFlag = &cli.Uint64Flag{
+		Name:  "slot",
+		Usage: "The slot of the objects to dump, used if no root is given",
+	}
+)
+
+// Kinds of objects which can be dumped from the database.
+const (
+	dumpBlock        = "block"
+	dumpState        = "state"
+	dumpStateSummary = "state-summary"
+)
+
+var dbCommands = &cli.Command{
+	Name:     "db",
+	Category: "db",
+	Usage:    "defines commands to maintain the beacon chain database",
+	Subcommands: []*cli.Command{
+		migration.Command(
+			[]cli.Flag{cmd.DataDirFlag},
+			func(cliCtx *cli.Context) (uint64, []*migration.Migration, error) {
+				return kv.PendingMigrations(dbPath(cliCtx))
+			},
+			func(cliCtx *cli.Context) error {
+				// Opening the database applies the pending migrations.
+				d, err := db.NewDB(dbPath(cliCtx), cache.NewStateSummaryCache(), nil)
+				if err != nil {
+					return err
+				}
+				return d.Close()
+			},
+		),
+		{
+			Name:  "convert",
+			Usage: "copies the database to another key-value engine",
+			Description: `copies all buckets of the beacon chain database to a new database of the engine
+given by --to. The source database is left in place and must be removed before the node is
+started on the converted database. The node must be stopped while its database is converted`,
+			Flags: []cli.Flag{cmd.DataDirFlag, convertFromFlag, convertToFlag},
+			Action: func(cliCtx *cli.Context) error {
+				from := cliCtx.String(convertFromFlag.Name)
+				if from == "" {
+					detected, err := kv.DetectEngine(dbPath(cliCtx))
+					if err != nil {
+						return err
+					}
+					if detected == "" {
+						return errors.Errorf("could not find database in %s", dbPath(cliCtx))
+					}
+					from = detected
+				}
+				return kv.Convert(dbPath(cliCtx), from, cliCtx.String(convertToFlag.Name))
+			},
+		},
+		{
+			Name:      "restore",
+			Usage:     "replaces the database with a backup",
+			ArgsUsage: "<backup file>",
+			Description: `restores the beacon chain database from a backup written by the database backup
+webhook. Incremental backups are restored together with the backups they build on, which must be
+in the same directory. The restored database is validated against the head block and finalized
+checkpoint recorded in the backup before it replaces the current database, which is kept with the
+.pre-restore suffix. The node must be stopped while its database is restored`,
+			Flags: []cli.Flag{cmd.DataDirFlag},
+			Action: func(cliCtx *cli.Context) error {
+				if cliCtx.NArg() != 1 {
+					return errors.New("expected the path of the backup file as argument")
+				}
+				return kv.Restore(cliCtx.Args().First(), dbPath(cliCtx))
+			},
+		},
+		{
+			Name:  "buckets",
+			Usage: "lists the buckets of the database with their sizes",
+			Description: `opens the database read-only and lists every bucket with its number of keys and
+the total size of its keys and values`,
+			Flags:  []cli.Flag{cmd.DataDirFlag},
+			Action: listBuckets,
+		},
+		{
+			Name:      "dump",
+			Usage:     "prints a block, state or state summary as JSON",
+			ArgsUsage: "<block|state|state-summary>",
+			Description: `opens the database read-only and prints the block, state or state summary of the
+block root given by --root as JSON. If no root is given, the objects of every block at --slot are
+printed`,
+			Flags:  []cli.Flag{cmd.DataDirFlag, dumpRootFlag, dumpSlotFlag},
+			Action: dumpObjects,
+		},
+		{
+			Name:  "verify",
+			Usage: "checks that the block indices are consistent with the blocks",
+			Description: `opens the database read-only and checks that every block is indexed at its slot,
+that the slot index only references existing blocks and that the finalized block roots index links
+the finalized blocks to their parents. Inconsistent indices can be rebuilt with the repair command`,
+			Flags: []cli.Flag{cmd.DataDirFlag},
+			Action: func(cliCtx *cli.Context) error {
+				d, err := kv.OpenReadOnly(dbPath(cliCtx))
+				if err != nil {
+					return err
+				}
+				defer closeDB(d)
+				problems, err := d.VerifyIndices(context.Background())
+				if err != nil {
+					return err
+				}
+				for _, p := range problems {
+					fmt.Println(p)
+				}
+				if len(problems) > 0 {
+					return errors.Errorf("found %d index inconsistencies, run db repair to rebuild the indices", len(problems))
+				}
+				fmt.Println("Indices are consistent")
+				return nil
+			},
+		},
+		{
+			Name:  "repair",
+			Usage: "rebuilds the block indices from the blocks",
+			Description: `rebuilds the block slot and parent root indices from the blocks of the database, and
+the finalized block roots index from the finalized checkpoint. The node must be stopped while its
+database is repaired`,
+			Flags: []cli.Flag{cmd.DataDirFlag},
+			Action: func(cliCtx *cli.Context) error {
+				d, err := kv.NewKVStore(dbPath(cliCtx), cache.NewStateSummaryCache(), nil)
+				if err != nil {
+					return err
+				}
+				defer closeDB(d)
+				return d.RebuildIndices(context.Background())
+			},
+		},
+	},
+}
+
+// listBuckets prints the number of keys and the size of every bucket of the database.
+func listBuckets(cliCtx *cli.Context) error {
+	d, err := kv.OpenReadOnly(dbPath(cliCtx))
+	if err != nil {
+		return err
+	}
+	defer closeDB(d)
+	stats, err := d.BucketStats()
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tBYTES")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", s.Name, s.Keys, s.Size)
+	}
+	return w.Flush()
+}
+
+// dumpObjects prints the objects of the kind given as argument for the block root or slot
+// given by flags as JSON.
+func dumpObjects(cliCtx *cli.Context) error {
+	kind := cliCtx.Args().First()
+	if cliCtx.NArg() != 1 || (kind != dumpBlock && kind != dumpState && kind != dumpStateSummary) {
+		return errors.Errorf("expected one of %s, %s or %s as argument", dumpBlock, dumpState, dumpStateSummary)
+	}
+	ctx := context.Background()
+	d, err := kv.OpenReadOnly(dbPath(cliCtx))
+	if err != nil {
+		return err
+	}
+	defer closeDB(d)
+
+	var roots [][32]byte
+	if cliCtx.IsSet(dumpRootFlag.Name) {
+		root, err := hex.DecodeString(strings.TrimPrefix(cliCtx.String(dumpRootFlag.Name), "0x"))
+		if err != nil || len(root) != 32 {
+			return errors.New("expected a hex encoded 32 byte root")
+		}
+		var r [32]byte
+		copy(r[:], root)
+		roots = append(roots, r)
+	} else if cliCtx.IsSet(dumpSlotFlag.Name) {
+		roots, err = blockRootsAtSlot(ctx, d, cliCtx.Uint64(dumpSlotFlag.Name))
+		if err != nil {
+			return err
+		}
+	} else {
+		return errors.New("expected a block root or slot")
+	}
+
+	marshaler := &jsonpb.Marshaler{Indent: "  "}
+	for _, root := range roots {
+		var msg proto.Message
+		switch kind {
+		case dumpBlock:
+			blk, err := d.Block(ctx, root)
+			if err != nil {
+				return err
+			}
+			if blk != nil {
+				msg = blk
+			}
+		case dumpState:
+			// Archived states may be stored as diffs of the previous archived state.
+			st, err := stategen.New(d, cache.NewStateSummaryCache()).StoredState(ctx, root)
+			if err != nil {
+				return err
+			}
+			if st != nil {
+				msg = st.InnerStateUnsafe()
+			}
+		case dumpStateSummary:
+			summary, err := d.StateSummary(ctx, root)
+			if err != nil {
+				return err
+			}
+			if summary != nil {
+				msg = summary
+			}
+		}
+		if msg == nil {
+			return errors.Errorf("no %s found for block root %#x", kind, root)
+		}
+		out, err := marshaler.MarshalToString(msg)
+		if err != nil {
+			return err
+		}
+		fmt.Println(out)
+	}
+	return nil
+}
+
+// blockRootsAtSlot returns the roots of the blocks at the slot.
+func blockRootsAtSlot(ctx context.Context, d *kv.Store, slot uint64) ([][32]byte, error) {
+	roots, err := d.BlockRoots(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+	if err != nil {
+		return nil, err
+	}
+	// An end slot of 0 does not bound the filter, so the slot of the blocks is checked.
+	var atSlot [][32]byte
+	for _, root := range roots {
+		blk, err := d.Block(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if blk != nil && blk.Block.Slot == slot {
+			atSlot = append(atSlot, root)
+		}
+	}
+	if len(atSlot) == 0 {
+		return nil, errors.Errorf("no blocks found at slot %d", slot)
+	}
+	return atSlot, nil
+}
+
+// closeDB closes the database, logging a failure.
+func closeDB(d *kv.Store) {
+	if err := d.Close(); err != nil {
+		logrus.WithError(err).Error("Failed to close database")
+	}
+}
+
+// dbPath returns the path of the beacon chain database in the data directory.
+func dbPath(cliCtx *cli.Context) string {
+	return filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), node.BeaconChainDBName)
+}
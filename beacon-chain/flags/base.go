@@ -136,6 +136,76 @@ var (
 		Usage: "The factor by which block batch limit may increase on burst.",
 		Value: 10,
 	}
+	// RPCRateLimits overrides the default per-peer rate limits of the p2p RPC protocols.
+	RPCRateLimits = &cli.StringSliceFlag{
+		Name: "rpc-rate-limit",
+		Usage: "Overrides the per-peer rate limit of a p2p RPC protocol, given as <protocol>=<requests per second>:<burst>, " +
+			"e.g. beacon_blocks_by_root=2:10. This flag may be used multiple times. The number of blocks served over " +
+			"beacon_blocks_by_range and beacon_blocks_by_root is limited separately by --block-batch-limit.",
+	}
+	// DBEngine selects the key-value engine of the beacon chain database.
+	DBEngine = &cli.StringFlag{
+		Name: "db-engine",
+		Usage: "The key-value engine of the beacon chain database, bolt or leveldb. Defaults to the engine of the " +
+			"existing database, or bolt for a new database. Use `beacon-chain db convert` to change the engine of an existing database.",
+	}
+	// DBBackupRetention specifies the number of database backups to keep.
+	DBBackupRetention = &cli.IntFlag{
+		Name: "db-backup-retention",
+		Usage: "The number of most recent database backups to keep, along with the backups they build on. " +
+			"Older backups are removed after every backup. 0 keeps all backups.",
+		Value: 5,
+	}
+	// DBBackupFullInterval specifies the number of incremental backups between full backups.
+	DBBackupFullInterval = &cli.IntFlag{
+		Name: "db-backup-full-interval",
+		Usage: "The number of incremental database backups, which only contain the buckets changed since the previous " +
+			"backup, to write before writing a full backup again. 0 writes a full backup every time.",
+		Value: 10,
+	}
+	// DBRetentionEpochs specifies the number of epochs of history to keep behind the finalized checkpoint.
+	DBRetentionEpochs = &cli.Uint64Flag{
+		Name: "db-retention-epochs",
+		Usage: "The number of epochs of blocks, attestations and state summaries to keep behind the finalized checkpoint. " +
+			"Older data and fork blocks which are not ancestors of the finalized block are deleted on finalization, " +
+			"except for the blocks of archived points. 0 keeps all history.",
+	}
+	// HistoricalStateInterval specifies the interval of the historical states reconstructed in between archived points.
+	HistoricalStateInterval = &cli.Uint64Flag{
+		Name: "historical-state-interval",
+		Usage: "The interval in slots at which historical states are reconstructed in between archived points, " +
+			"to speed up the retrieval of finalized states. Must be a multiple of the slots per epoch and divide " +
+			"--slots-per-archive-point, the slots per epoch reconstruct a state every epoch. The reconstruction of a " +
+			"slot range is started through the /eth/v1/debug/states/reconstruction endpoint, which requires " +
+			"--enable-debug-rpc-endpoints. 0 disables reconstruction.",
+	}
+	// ArchivedStateSnapshotInterval specifies the number of archived points between the archived states stored in full.
+	ArchivedStateSnapshotInterval = &cli.Uint64Flag{
+		Name: "archived-state-snapshot-interval",
+		Usage: "The number of archived points between the archived points whose state is stored in full. The states " +
+			"of the other archived points are stored as their difference from the state of the previous archived point, " +
+			"and rebuilt from the last full state when loaded. 1 stores every archived state in full.",
+		Value: 16,
+	}
+	// HotStateCacheSize specifies the size of the hot states kept in memory.
+	HotStateCacheSize = &cli.Uint64Flag{
+		Name: "hot-state-cache-size",
+		Usage: "The estimated size in megabytes of the unfinalized states kept in memory. The least recently used " +
+			"states beyond it are evicted to the hot state spill directory.",
+		Value: 512,
+	}
+	// HotStateSpillSize specifies the size of the hot states kept on disk.
+	HotStateSpillSize = &cli.Uint64Flag{
+		Name: "hot-state-spill-size",
+		Usage: "The size in megabytes of the unfinalized states evicted from memory which are kept on disk, so that " +
+			"they are loaded instead of being regenerated by replaying blocks. 0 disables the disk tier.",
+		Value: 2048,
+	}
+	// HotStateSpillDir specifies the directory of the hot states kept on disk.
+	HotStateSpillDir = &cli.StringFlag{
+		Name:  "hot-state-spill-dir",
+		Usage: "The directory of the unfinalized states evicted from memory. Defaults to hotstates in the data directory.",
+	}
 	// EnableDebugRPCEndpoints as /v1/beacon/state.
 	EnableDebugRPCEndpoints = &cli.BoolFlag{
 		Name:  "enable-debug-rpc-endpoints",
@@ -0,0 +1,34 @@
+package flags
+
+import (
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// CheckpointStateFlag defines a flag for the beacon node to start from a finalized state loaded from file
+	// instead of genesis.
+	CheckpointStateFlag = &cli.StringFlag{
+		Name: "checkpoint-state",
+		Usage: "Start the beacon node from a trusted, finalized beacon state file (.SSZ) instead of genesis. " +
+			"Must be used with --checkpoint-block",
+	}
+	// CheckpointBlockFlag defines a flag for the block corresponding to the state given by --checkpoint-state.
+	CheckpointBlockFlag = &cli.StringFlag{
+		Name: "checkpoint-block",
+		Usage: "The signed beacon block file (.SSZ) whose post state is given by --checkpoint-state. " +
+			"Must be used with --checkpoint-state",
+	}
+	// CheckpointSyncProviderFlag defines a flag for a trusted beacon node to fetch the finalized state and block from.
+	CheckpointSyncProviderFlag = &cli.StringFlag{
+		Name: "checkpoint-sync-provider",
+		Usage: "A trusted beacon node gRPC endpoint (host:port) to fetch the latest finalized state and block from " +
+			"when starting with an empty database. The provider must run with --enable-debug-rpc-endpoints, and " +
+			"--grpc-max-msg-size has to be large enough to receive a full beacon state",
+	}
+	// WeakSubjectivityCheckpointFlag defines a weak subjectivity checkpoint the chain followed by the node must contain.
+	WeakSubjectivityCheckpointFlag = &cli.StringFlag{
+		Name: "weak-subjectivity-checkpoint",
+		Usage: "Input in `block_root:epoch` format. The beacon node refuses to finalize or follow a chain " +
+			"which does not contain this block root at this epoch",
+	}
+)
@@ -19,6 +19,16 @@ type GlobalFlags struct {
 	MaxPageSize                       int
 	BlockBatchLimit                   int
 	BlockBatchLimitBurstFactor        int
+	RPCRateLimits                     []string
+	DBEngine                          string
+	DBBackupRetention                 int
+	DBBackupFullInterval              int
+	DBRetentionEpochs                 uint64
+	HistoricalStateInterval           uint64
+	ArchivedStateSnapshotInterval     uint64
+	HotStateCacheSize                 uint64
+	HotStateSpillSize                 uint64
+	HotStateSpillDir                  string
 }
 
 var globalConfig *GlobalFlags
@@ -60,7 +70,17 @@ func ConfigureGlobalFlags(ctx *cli.Context) {
 	}
 	cfg.BlockBatchLimit = ctx.Int(BlockBatchLimit.Name)
 	cfg.BlockBatchLimitBurstFactor = ctx.Int(BlockBatchLimitBurstFactor.Name)
+	cfg.RPCRateLimits = ctx.StringSlice(RPCRateLimits.Name)
 	cfg.MaxPageSize = ctx.Int(RPCMaxPageSize.Name)
+	cfg.DBEngine = ctx.String(DBEngine.Name)
+	cfg.DBBackupRetention = ctx.Int(DBBackupRetention.Name)
+	cfg.DBBackupFullInterval = ctx.Int(DBBackupFullInterval.Name)
+	cfg.DBRetentionEpochs = ctx.Uint64(DBRetentionEpochs.Name)
+	cfg.HistoricalStateInterval = ctx.Uint64(HistoricalStateInterval.Name)
+	cfg.ArchivedStateSnapshotInterval = ctx.Uint64(ArchivedStateSnapshotInterval.Name)
+	cfg.HotStateCacheSize = ctx.Uint64(HotStateCacheSize.Name)
+	cfg.HotStateSpillSize = ctx.Uint64(HotStateSpillSize.Name)
+	cfg.HotStateSpillDir = ctx.String(HotStateSpillDir.Name)
 	configureMinimumPeers(ctx, cfg)
 
 	Init(cfg)
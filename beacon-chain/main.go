@@ -44,6 +44,7 @@ var appFlags = []cli.Flag{
 	flags.DisableDiscv5,
 	flags.BlockBatchLimit,
 	flags.BlockBatchLimitBurstFactor,
+	flags.RPCRateLimits,
 	flags.InteropMockEth1DataVotesFlag,
 	flags.InteropGenesisStateFlag,
 	flags.InteropNumValidatorsFlag,
@@ -54,6 +55,19 @@ var appFlags = []cli.Flag{
 	flags.ArchiveAttestationsFlag,
 	flags.SlotsPerArchivedPoint,
 	flags.EnableDebugRPCEndpoints,
+	flags.DBEngine,
+	flags.DBBackupRetention,
+	flags.DBBackupFullInterval,
+	flags.DBRetentionEpochs,
+	flags.HistoricalStateInterval,
+	flags.ArchivedStateSnapshotInterval,
+	flags.HotStateCacheSize,
+	flags.HotStateSpillSize,
+	flags.HotStateSpillDir,
+	flags.CheckpointStateFlag,
+	flags.CheckpointBlockFlag,
+	flags.CheckpointSyncProviderFlag,
+	flags.WeakSubjectivityCheckpointFlag,
 	cmd.MinimalConfigFlag,
 	cmd.E2EConfigFlag,
 	cmd.CustomGenesisDelayFlag,
@@ -112,6 +126,7 @@ func main() {
 	app.Version = version.GetVersion()
 
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{dbCommands}
 
 	app.Before = func(ctx *cli.Context) error {
 		// Load any flags from file, if specified.
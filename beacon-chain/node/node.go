@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/archiver"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
@@ -35,6 +37,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
 	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync/checkpoint"
 	initialsync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync"
 	"github.com/prysmaticlabs/prysm/shared"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
@@ -53,7 +56,12 @@ import (
 
 var log = logrus.WithField("prefix", "node")
 
-const beaconChainDBName = "beaconchaindata"
+// BeaconChainDBName is the directory of the beacon chain database within the data directory.
+const BeaconChainDBName = "beaconchaindata"
+
+// hotStateSpillDirName is the default directory of the hot states spilled to disk within the data directory.
+const hotStateSpillDirName = "hotstates"
+
 const testSkipPowFlag = "test-skip-pow"
 
 // BeaconNode defines a struct that handles the services running a random beacon chain
@@ -106,6 +114,9 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 	cmd.ConfigureBeaconChain(cliCtx)
 	featureconfig.ConfigureBeaconChain(cliCtx)
 	flags.ConfigureGlobalFlags(cliCtx)
+	if err := prysmsync.ValidateRateLimits(flags.Get().RPCRateLimits); err != nil {
+		return nil, err
+	}
 
 	// Setting chain network specific flags.
 	if cliCtx.IsSet(flags.DepositContractFlag.Name) {
@@ -146,7 +157,9 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
-	beacon.startStateGen()
+	if err := beacon.startStateGen(); err != nil {
+		return nil, err
+	}
 
 	if err := beacon.registerP2P(cliCtx); err != nil {
 		return nil, err
@@ -164,6 +177,10 @@ func NewBeaconNode(cliCtx *cli.Context) (*BeaconNode, error) {
 		return nil, err
 	}
 
+	if err := beacon.registerCheckpointSyncService(); err != nil {
+		return nil, err
+	}
+
 	beacon.startForkChoice()
 
 	if err := beacon.registerBlockchainService(); err != nil {
@@ -269,11 +286,17 @@ func (b *BeaconNode) startForkChoice() {
 
 func (b *BeaconNode) startDB(cliCtx *cli.Context) error {
 	baseDir := cliCtx.String(cmd.DataDirFlag.Name)
-	dbPath := filepath.Join(baseDir, beaconChainDBName)
+	dbPath := filepath.Join(baseDir, BeaconChainDBName)
 	clearDB := cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearDB := cliCtx.Bool(cmd.ForceClearDB.Name)
+	dbConfig := &db.Config{
+		Engine:             flags.Get().DBEngine,
+		IncrementalBackups: featureconfig.Get().EnableBackupWebhook,
+		BackupFullInterval: flags.Get().DBBackupFullInterval,
+		BackupRetention:    flags.Get().DBBackupRetention,
+	}
 
-	d, err := db.NewDB(dbPath, b.stateSummaryCache)
+	d, err := db.NewDB(dbPath, b.stateSummaryCache, dbConfig)
 	if err != nil {
 		return err
 	}
@@ -292,7 +315,7 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context) error {
 		if err := d.ClearDB(); err != nil {
 			return errors.Wrap(err, "could not clear database")
 		}
-		d, err = db.NewDB(dbPath, b.stateSummaryCache)
+		d, err = db.NewDB(dbPath, b.stateSummaryCache, dbConfig)
 		if err != nil {
 			return errors.Wrap(err, "could not create new database")
 		}
@@ -312,8 +335,31 @@ func (b *BeaconNode) startDB(cliCtx *cli.Context) error {
 	return nil
 }
 
-func (b *BeaconNode) startStateGen() {
+func (b *BeaconNode) startStateGen() error {
 	b.stateGen = stategen.New(b.db, b.stateSummaryCache)
+	hotStateCfg := &cache.HotStateCacheConfig{MaxSize: flags.Get().HotStateCacheSize << 20}
+	if hotStateCfg.MaxSize == 0 {
+		hotStateCfg.MaxSize = cache.DefaultHotStateCacheSize
+	}
+	if spillSize := flags.Get().HotStateSpillSize; spillSize > 0 {
+		hotStateCfg.SpillDir = flags.Get().HotStateSpillDir
+		if hotStateCfg.SpillDir == "" {
+			hotStateCfg.SpillDir = filepath.Join(b.cliCtx.String(cmd.DataDirFlag.Name), hotStateSpillDirName)
+		}
+		hotStateCfg.MaxSpillSize = spillSize << 20
+	}
+	hotStateCache, err := cache.NewHotStateCacheWithConfig(hotStateCfg)
+	if err != nil {
+		return errors.Wrap(err, "could not create hot state cache")
+	}
+	b.stateGen.SetHotStateCache(hotStateCache)
+	b.stateGen.SetArchivedSnapshotInterval(flags.Get().ArchivedStateSnapshotInterval)
+	if interval := flags.Get().HistoricalStateInterval; interval > 0 {
+		if err := b.stateGen.SetReconstructionInterval(interval); err != nil {
+			return errors.Wrap(err, "invalid historical state interval")
+		}
+	}
+	return nil
 }
 
 func readbootNodes(fileName string) ([]string, error) {
@@ -413,20 +459,30 @@ func (b *BeaconNode) registerBlockchainService() error {
 		return err
 	}
 
+	var wsCheckpoint *ethpb.Checkpoint
+	if b.cliCtx.IsSet(flags.WeakSubjectivityCheckpointFlag.Name) {
+		var err error
+		wsCheckpoint, err = blockchain.ParseWeakSubjectivityInputString(b.cliCtx.String(flags.WeakSubjectivityCheckpointFlag.Name))
+		if err != nil {
+			return errors.Wrap(err, "could not parse weak subjectivity checkpoint")
+		}
+	}
+
 	maxRoutines := b.cliCtx.Int64(cmd.MaxGoroutines.Name)
 	blockchainService, err := blockchain.NewService(b.ctx, &blockchain.Config{
-		BeaconDB:          b.db,
-		DepositCache:      b.depositCache,
-		ChainStartFetcher: web3Service,
-		AttPool:           b.attestationPool,
-		ExitPool:          b.exitPool,
-		SlashingPool:      b.slashingsPool,
-		P2p:               b.fetchP2P(),
-		MaxRoutines:       maxRoutines,
-		StateNotifier:     b,
-		ForkChoiceStore:   b.forkChoiceStore,
-		OpsService:        opsService,
-		StateGen:          b.stateGen,
+		BeaconDB:                b.db,
+		DepositCache:            b.depositCache,
+		ChainStartFetcher:       web3Service,
+		AttPool:                 b.attestationPool,
+		ExitPool:                b.exitPool,
+		SlashingPool:            b.slashingsPool,
+		P2p:                     b.fetchP2P(),
+		MaxRoutines:             maxRoutines,
+		StateNotifier:           b,
+		ForkChoiceStore:         b.forkChoiceStore,
+		OpsService:              opsService,
+		StateGen:                b.stateGen,
+		WeakSubjectivityCheckpt: wsCheckpoint,
 	})
 	if err != nil {
 		return errors.Wrap(err, "could not register blockchain service")
@@ -580,6 +636,7 @@ func (b *BeaconNode) registerRPCService() error {
 		HeadFetcher:             chainService,
 		ForkFetcher:             chainService,
 		FinalizationFetcher:     chainService,
+		CanonicalFetcher:        chainService,
 		ParticipationFetcher:    chainService,
 		BlockReceiver:           chainService,
 		AttestationReceiver:     chainService,
@@ -646,12 +703,19 @@ func (b *BeaconNode) registerGRPCGateway() error {
 	gatewayAddress := fmt.Sprintf("%s:%d", gatewayHost, gatewayPort)
 	allowedOrigins := strings.Split(b.cliCtx.String(flags.GPRCGatewayCorsDomain.Name), ",")
 	enableDebugRPCEndpoints := b.cliCtx.Bool(flags.EnableDebugRPCEndpoints.Name)
+
+	var rpcService *rpc.Service
+	if err := b.services.FetchService(&rpcService); err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/eth/v1/", rpcService.APIHandler())
 	return b.services.RegisterService(
 		gateway.New(
 			b.ctx,
 			selfAddress,
 			gatewayAddress,
-			nil, /*optional mux*/
+			mux,
 			allowedOrigins,
 			enableDebugRPCEndpoints,
 			b.cliCtx.Uint64(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
@@ -678,6 +742,46 @@ func (b *BeaconNode) registerInteropServices() error {
 	return nil
 }
 
+func (b *BeaconNode) registerCheckpointSyncService() error {
+	statePath := b.cliCtx.String(flags.CheckpointStateFlag.Name)
+	blockPath := b.cliCtx.String(flags.CheckpointBlockFlag.Name)
+	provider := b.cliCtx.String(flags.CheckpointSyncProviderFlag.Name)
+
+	if statePath == "" && blockPath == "" && provider == "" {
+		// A node started from a checkpoint keeps backfilling blocks after a restart,
+		// until the genesis block is reached.
+		originRoot, err := b.db.OriginBlockRoot(b.ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get origin block root")
+		}
+		if originRoot == params.BeaconConfig().ZeroHash {
+			return nil
+		}
+		genesisBlock, err := b.db.GenesisBlock(b.ctx)
+		if err != nil {
+			return errors.Wrap(err, "could not get genesis block")
+		}
+		if genesisBlock != nil {
+			return nil
+		}
+	}
+	if (statePath == "") != (blockPath == "") {
+		return fmt.Errorf("--%s and --%s must be used together", flags.CheckpointStateFlag.Name, flags.CheckpointBlockFlag.Name)
+	}
+	svc, err := checkpoint.NewCheckpointSyncService(b.ctx, &checkpoint.Config{
+		BeaconDB:           b.db,
+		P2P:                b.fetchP2P(),
+		StatePath:          statePath,
+		BlockPath:          blockPath,
+		Provider:           provider,
+		MaxCallRecvMsgSize: b.cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not register checkpoint sync service")
+	}
+	return b.services.RegisterService(svc)
+}
+
 func (b *BeaconNode) registerArchiverService() error {
 	if !flags.Get().EnableArchive {
 		return nil
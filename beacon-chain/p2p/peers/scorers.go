@@ -0,0 +1,107 @@
+package peers
+
+import (
+	"math"
+	"time"
+)
+
+// The decay and ban constants complement the maxBadResponses limit of the p2p service: a
+// peer's recorded behaviour is halved every DecayInterval, and a peer whose penalties reach
+// the limit stays bad for banDuration even if decay brings it back under the limit.
+const (
+	// DecayInterval is the interval at which the recorded behaviour of peers should be decayed.
+	DecayInterval = 10 * time.Minute
+	// decayFactor is the factor the recorded behaviour of peers is multiplied by on each decay.
+	decayFactor = 0.5
+	// banDuration is the minimum time a peer stays bad after its penalties crossed the threshold.
+	banDuration = time.Hour
+	// blocksServedPerPoint is the number of useful blocks a peer needs to serve to gain a score of 1.
+	blocksServedPerPoint = 1024
+	// staleChainStatePenalty is the penalty applied to peers whose chain state is out of date.
+	staleChainStatePenalty = 0.5
+)
+
+// Weights of the penalties relative to a bad response. Timeouts and RPC errors are
+// penalised less than invalid data, as a slow honest peer produces them as well.
+const (
+	invalidGossipWeight = 1.0
+	rpcErrorWeight      = 0.5
+	timeoutWeight       = 0.25
+)
+
+// PeerStats is a snapshot of the behaviour recorded for a peer, from which its score is computed.
+type PeerStats struct {
+	// BlocksServed is the number of useful blocks the peer served to us.
+	BlocksServed float64
+	// BadResponses is the number of invalid or malformed responses received from the peer.
+	BadResponses float64
+	// InvalidGossip is the number of gossip messages from the peer which were rejected.
+	InvalidGossip float64
+	// Timeouts is the number of requests to the peer which timed out.
+	Timeouts float64
+	// RPCErrors is the number of requests the peer answered with an error.
+	RPCErrors float64
+	// ChainStateAge is the time since the chain state of the peer was last updated, or zero if it is unknown.
+	ChainStateAge time.Duration
+}
+
+// Scorer computes one component of a peer's score. Positive scores reward useful
+// peers, negative scores penalise misbehaving ones.
+type Scorer interface {
+	Score(stats *PeerStats) float64
+}
+
+// ScorerFunc is an adapter to allow the use of ordinary functions as scorers.
+type ScorerFunc func(stats *PeerStats) float64
+
+// Score calls f(stats).
+func (f ScorerFunc) Score(stats *PeerStats) float64 {
+	return f(stats)
+}
+
+// CompositeScorer sums the scores of its scorers.
+type CompositeScorer []Scorer
+
+// Score returns the sum of the scores of all scorers.
+func (c CompositeScorer) Score(stats *PeerStats) float64 {
+	var score float64
+	for _, s := range c {
+		score += s.Score(stats)
+	}
+	return score
+}
+
+// BadResponsesScorer penalises peers for invalid data and failed requests. A peer
+// reaches a score of -1 after maxBadResponses bad responses or the equivalent
+// weight of other failures, at which point it is considered bad.
+func BadResponsesScorer(maxBadResponses int) Scorer {
+	return ScorerFunc(func(stats *PeerStats) float64 {
+		if maxBadResponses <= 0 {
+			return 0
+		}
+		penalty := stats.BadResponses +
+			invalidGossipWeight*stats.InvalidGossip +
+			rpcErrorWeight*stats.RPCErrors +
+			timeoutWeight*stats.Timeouts
+		return -penalty / float64(maxBadResponses)
+	})
+}
+
+// BlockProviderScorer rewards peers for the blocks they served, gaining a score
+// of 1 per blocksPerPoint blocks up to maxScore.
+func BlockProviderScorer(blocksPerPoint float64, maxScore float64) Scorer {
+	return ScorerFunc(func(stats *PeerStats) float64 {
+		return math.Min(stats.BlocksServed/blocksPerPoint, maxScore)
+	})
+}
+
+// StaleChainStateScorer penalises peers whose chain state has not been updated
+// within the given period.
+func StaleChainStateScorer(period time.Duration, penalty float64) Scorer {
+	return ScorerFunc(func(stats *PeerStats) float64 {
+		if stats.ChainStateAge > period {
+			return -penalty
+		}
+		return 0
+	})
+}
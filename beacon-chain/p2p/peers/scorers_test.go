@@ -0,0 +1,53 @@
+package peers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p/peers"
+)
+
+func TestBadResponsesScorer(t *testing.T) {
+	scorer := peers.BadResponsesScorer(4)
+	tests := []struct {
+		stats *peers.PeerStats
+		score float64
+	}{
+		{stats: &peers.PeerStats{}, score: 0},
+		{stats: &peers.PeerStats{BlocksServed: 100}, score: 0},
+		{stats: &peers.PeerStats{BadResponses: 2}, score: -0.5},
+		{stats: &peers.PeerStats{InvalidGossip: 4}, score: -1},
+		{stats: &peers.PeerStats{RPCErrors: 2, Timeouts: 4}, score: -0.5},
+		{stats: &peers.PeerStats{BadResponses: 1, InvalidGossip: 1, RPCErrors: 2, Timeouts: 4}, score: -1},
+	}
+	for _, tt := range tests {
+		if score := scorer.Score(tt.stats); score != tt.score {
+			t.Errorf("Unexpected score for %+v: expected %v, received %v", tt.stats, tt.score, score)
+		}
+	}
+}
+
+func TestCompositeScorer(t *testing.T) {
+	scorer := peers.CompositeScorer{
+		peers.BadResponsesScorer(2),
+		peers.BlockProviderScorer(100, 1),
+		peers.StaleChainStateScorer(time.Minute, 0.5),
+	}
+	tests := []struct {
+		stats *peers.PeerStats
+		score float64
+	}{
+		{stats: &peers.PeerStats{}, score: 0},
+		{stats: &peers.PeerStats{BlocksServed: 50}, score: 0.5},
+		// The reward for serving blocks is capped.
+		{stats: &peers.PeerStats{BlocksServed: 1000}, score: 1},
+		{stats: &peers.PeerStats{BlocksServed: 100, BadResponses: 1}, score: 0.5},
+		{stats: &peers.PeerStats{BlocksServed: 100, ChainStateAge: time.Hour}, score: 0.5},
+		{stats: &peers.PeerStats{ChainStateAge: time.Second}, score: 0},
+	}
+	for _, tt := range tests {
+		if score := scorer.Score(tt.stats); score != tt.score {
+			t.Errorf("Unexpected score for %+v: expected %v, received %v", tt.stats, tt.score, score)
+		}
+	}
+}
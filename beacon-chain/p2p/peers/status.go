@@ -17,10 +17,15 @@
 //
 // Peer information is persistent for the run of the service.  This allows for collection of useful long-term statistics such as
 // number of bad responses obtained from the peer, giving the basis for decisions to not talk to known-bad peers.
+//
+// The recorded behaviour of each peer is combined into a score by a set of scorers.  Penalties for bad responses, invalid gossip,
+// timeouts and RPC errors decide whether a peer is bad, while the full score is used to prefer useful peers when selecting peers
+// to sync from.
 package peers
 
 import (
 	"errors"
+	"math"
 	"sort"
 	"sync"
 	"time"
@@ -35,6 +40,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 )
 
@@ -62,6 +68,8 @@ type Status struct {
 	lock            sync.RWMutex
 	maxBadResponses int
 	status          map[peer.ID]*peerStatus
+	penaltyScorer   Scorer
+	scorer          Scorer
 }
 
 // peerStatus is the status of an individual peer at the protocol level.
@@ -73,14 +81,27 @@ type peerStatus struct {
 	enr                   *enr.Record
 	metaData              *pb.MetaData
 	chainStateLastUpdated time.Time
-	badResponses          int
+	blocksServed          float64
+	badResponses          float64
+	invalidGossip         float64
+	timeouts              float64
+	rpcErrors             float64
+	bannedUntil           time.Time
 }
 
 // NewStatus creates a new status entity.
 func NewStatus(maxBadResponses int) *Status {
+	penaltyScorer := BadResponsesScorer(maxBadResponses)
+	staleChainStatePeriod := 2 * time.Duration(params.BeaconConfig().SlotsPerEpoch*params.BeaconConfig().SecondsPerSlot) * time.Second
 	return &Status{
 		maxBadResponses: maxBadResponses,
 		status:          make(map[peer.ID]*peerStatus),
+		penaltyScorer:   penaltyScorer,
+		scorer: CompositeScorer{
+			penaltyScorer,
+			BlockProviderScorer(blocksServedPerPoint, 1),
+			StaleChainStateScorer(staleChainStatePeriod, staleChainStatePenalty),
+		},
 	}
 }
 
@@ -276,11 +297,9 @@ func (p *Status) ChainStateLastUpdated(pid peer.ID) (time.Time, error) {
 
 // IncrementBadResponses increments the number of bad responses we have received from the given remote peer.
 func (p *Status) IncrementBadResponses(pid peer.ID) {
-	p.lock.Lock()
-	defer p.lock.Unlock()
-
-	status := p.fetch(pid)
-	status.badResponses++
+	p.record(pid, func(status *peerStatus) {
+		status.badResponses++
+	})
 }
 
 // BadResponses obtains the number of bad responses we have received from the given remote peer.
@@ -290,11 +309,63 @@ func (p *Status) BadResponses(pid peer.ID) (int, error) {
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses, nil
+		return int(math.Floor(status.badResponses)), nil
 	}
 	return -1, ErrPeerUnknown
 }
 
+// IncrementInvalidGossip increments the number of gossip messages from the given remote peer which were rejected.
+func (p *Status) IncrementInvalidGossip(pid peer.ID) {
+	p.record(pid, func(status *peerStatus) {
+		status.invalidGossip++
+	})
+}
+
+// IncrementTimeouts increments the number of requests to the given remote peer which timed out.
+func (p *Status) IncrementTimeouts(pid peer.ID) {
+	p.record(pid, func(status *peerStatus) {
+		status.timeouts++
+	})
+}
+
+// IncrementRPCErrors increments the number of requests the given remote peer answered with an error.
+func (p *Status) IncrementRPCErrors(pid peer.ID) {
+	p.record(pid, func(status *peerStatus) {
+		status.rpcErrors++
+	})
+}
+
+// IncrementBlocksServed adds to the number of useful blocks the given remote peer served to us.
+func (p *Status) IncrementBlocksServed(pid peer.ID, count uint64) {
+	p.record(pid, func(status *peerStatus) {
+		status.blocksServed += float64(count)
+	})
+}
+
+// Stats returns a snapshot of the behaviour recorded for the given remote peer.
+// This will error if the peer does not exist.
+func (p *Status) Stats(pid peer.ID) (*PeerStats, error) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return status.stats(roughtime.Now()), nil
+	}
+	return nil, ErrPeerUnknown
+}
+
+// Score returns the score of the given remote peer, which is positive for useful peers and negative for misbehaving ones.
+// If the peer is unknown this will return 0.
+func (p *Status) Score(pid peer.ID) float64 {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	if status, ok := p.status[pid]; ok {
+		return p.scorer.Score(status.stats(roughtime.Now()))
+	}
+	return 0
+}
+
 // IsBad states if the peer is to be considered bad.
 // If the peer is unknown this will return `false`, which makes using this function easier than returning an error.
 func (p *Status) IsBad(pid peer.ID) bool {
@@ -302,7 +373,7 @@ func (p *Status) IsBad(pid peer.ID) bool {
 	defer p.lock.RUnlock()
 
 	if status, ok := p.status[pid]; ok {
-		return status.badResponses >= p.maxBadResponses
+		return p.isBad(status, roughtime.Now())
 	}
 	return false
 }
@@ -389,9 +460,10 @@ func (p *Status) Inactive() []peer.ID {
 func (p *Status) Bad() []peer.ID {
 	p.lock.RLock()
 	defer p.lock.RUnlock()
+	now := roughtime.Now()
 	peers := make([]peer.ID, 0)
 	for pid, status := range p.status {
-		if status.badResponses >= p.maxBadResponses {
+		if p.isBad(status, now) {
 			peers = append(peers, pid)
 		}
 	}
@@ -409,16 +481,18 @@ func (p *Status) All() []peer.ID {
 	return pids
 }
 
-// Decay reduces the bad responses of all peers, giving reformed peers a chance to join the network.
-// This can be run periodically, although note that each time it runs it does give all bad peers another chance as well to clog up
-// the network with bad responses, so should not be run too frequently; once an hour would be reasonable.
+// Decay reduces the recorded behaviour of all peers, so that recent behaviour weighs more than old behaviour and reformed peers
+// get a chance to join the network.  Peers which were banned stay bad until their ban expires, regardless of decay.
+// This should be run every DecayInterval.
 func (p *Status) Decay() {
 	p.lock.Lock()
 	defer p.lock.Unlock()
 	for _, status := range p.status {
-		if status.badResponses > 0 {
-			status.badResponses--
-		}
+		status.blocksServed *= decayFactor
+		status.badResponses *= decayFactor
+		status.invalidGossip *= decayFactor
+		status.timeouts *= decayFactor
+		status.rpcErrors *= decayFactor
 	}
 }
 
@@ -426,20 +500,26 @@ func (p *Status) Decay() {
 // This method may not return the absolute highest finalized, but the finalized epoch in which most peers can serve blocks.
 // Ideally, all peers would be reporting the same finalized epoch but some may be behind due to their own latency, or because of
 // their finalized epoch at the time we queried them.
+// Bad peers are not considered, and peers on the same finalized epoch are ordered by their score.
 // Returns the best finalized root, epoch number, and list of peers that are at or beyond that epoch.
 func (p *Status) BestFinalized(maxPeers int, ourFinalizedEpoch uint64) ([]byte, uint64, []peer.ID) {
 	connected := p.Connected()
 	finalized := make(map[[32]byte]uint64)
 	rootToEpoch := make(map[[32]byte]uint64)
 	pidEpoch := make(map[peer.ID]uint64)
+	pidScore := make(map[peer.ID]float64)
 	potentialPIDs := make([]peer.ID, 0, len(connected))
 	for _, pid := range connected {
+		if p.IsBad(pid) {
+			continue
+		}
 		peerChainState, err := p.ChainState(pid)
 		if err == nil && peerChainState != nil && peerChainState.FinalizedEpoch >= ourFinalizedEpoch {
 			root := bytesutil.ToBytes32(peerChainState.FinalizedRoot)
 			finalized[root]++
 			rootToEpoch[root] = peerChainState.FinalizedEpoch
 			pidEpoch[pid] = peerChainState.FinalizedEpoch
+			pidScore[pid] = p.Score(pid)
 			potentialPIDs = append(potentialPIDs, pid)
 		}
 	}
@@ -455,9 +535,12 @@ func (p *Status) BestFinalized(maxPeers int, ourFinalizedEpoch uint64) ([]byte,
 	}
 	targetEpoch := rootToEpoch[targetRoot]
 
-	// Sort PIDs by finalized epoch, in decreasing order.
+	// Sort PIDs by finalized epoch and then by score, in decreasing order.
 	sort.Slice(potentialPIDs, func(i, j int) bool {
-		return pidEpoch[potentialPIDs[i]] > pidEpoch[potentialPIDs[j]]
+		if pidEpoch[potentialPIDs[i]] != pidEpoch[potentialPIDs[j]] {
+			return pidEpoch[potentialPIDs[i]] > pidEpoch[potentialPIDs[j]]
+		}
+		return pidScore[potentialPIDs[i]] > pidScore[potentialPIDs[j]]
 	})
 
 	// Trim potential peers to those on or after target epoch.
@@ -484,6 +567,40 @@ func (p *Status) fetch(pid peer.ID) *peerStatus {
 	return p.status[pid]
 }
 
+// record applies the given update to the recorded behaviour of a peer, possibly creating it, and bans the peer if its
+// penalties reached the threshold.
+func (p *Status) record(pid peer.ID, update func(status *peerStatus)) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := p.fetch(pid)
+	update(status)
+	now := roughtime.Now()
+	if p.penaltyScorer.Score(status.stats(now)) <= -1 && !status.bannedUntil.After(now) {
+		status.bannedUntil = now.Add(banDuration)
+	}
+}
+
+// isBad states if the peer is banned or its penalties reached the threshold.
+func (p *Status) isBad(status *peerStatus, now time.Time) bool {
+	return status.bannedUntil.After(now) || p.penaltyScorer.Score(status.stats(now)) <= -1
+}
+
+// stats returns a snapshot of the recorded behaviour of the peer.
+func (s *peerStatus) stats(now time.Time) *PeerStats {
+	stats := &PeerStats{
+		BlocksServed:  s.blocksServed,
+		BadResponses:  s.badResponses,
+		InvalidGossip: s.invalidGossip,
+		Timeouts:      s.timeouts,
+		RPCErrors:     s.rpcErrors,
+	}
+	if s.chainState != nil {
+		stats.ChainStateAge = now.Sub(s.chainStateLastUpdated)
+	}
+	return stats
+}
+
 // HighestEpoch returns the highest epoch reported epoch amongst peers.
 func (p *Status) HighestEpoch() uint64 {
 	p.lock.RLock()
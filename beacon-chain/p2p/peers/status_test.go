@@ -517,6 +517,87 @@ func TestDecay(t *testing.T) {
 	}
 }
 
+func TestDecay_BannedPeerStaysBad(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+
+	pid := addPeer(t, p, peers.PeerConnected)
+	p.IncrementBadResponses(pid)
+	p.IncrementBadResponses(pid)
+	if !p.IsBad(pid) {
+		t.Fatal("Peer should be bad")
+	}
+
+	// Decaying the penalties does not lift the ban.
+	p.Decay()
+	p.Decay()
+	badResponses, err := p.BadResponses(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badResponses != 0 {
+		t.Errorf("Unexpected bad responses: expected 0, received %v", badResponses)
+	}
+	if !p.IsBad(pid) {
+		t.Error("Banned peer should still be bad")
+	}
+	if len(p.Bad()) != 1 {
+		t.Errorf("Unexpected number of bad peers: expected 1, received %v", len(p.Bad()))
+	}
+}
+
+func TestPeerPenalties(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+
+	// Invalid gossip weighs as much as a bad response.
+	pid1 := addPeer(t, p, peers.PeerConnected)
+	p.IncrementInvalidGossip(pid1)
+	if p.IsBad(pid1) {
+		t.Error("Peer should not be bad after a single invalid gossip message")
+	}
+	p.IncrementInvalidGossip(pid1)
+	if !p.IsBad(pid1) {
+		t.Error("Peer should be bad after two invalid gossip messages")
+	}
+
+	// RPC errors and timeouts weigh less than a bad response.
+	pid2 := addPeer(t, p, peers.PeerConnected)
+	p.IncrementRPCErrors(pid2)
+	p.IncrementRPCErrors(pid2)
+	p.IncrementTimeouts(pid2)
+	p.IncrementTimeouts(pid2)
+	p.IncrementTimeouts(pid2)
+	if p.IsBad(pid2) {
+		t.Error("Peer should not be bad yet")
+	}
+	p.IncrementTimeouts(pid2)
+	if !p.IsBad(pid2) {
+		t.Error("Peer should be bad after two RPC errors and four timeouts")
+	}
+	stats, err := p.Stats(pid2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.RPCErrors != 2 || stats.Timeouts != 4 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	// Serving blocks increases the score.
+	pid3 := addPeer(t, p, peers.PeerConnected)
+	if p.Score(pid3) != 0 {
+		t.Errorf("Unexpected score for new peer: %v", p.Score(pid3))
+	}
+	p.IncrementBlocksServed(pid3, 512)
+	if p.Score(pid3) != 0.5 {
+		t.Errorf("Unexpected score: expected 0.5, received %v", p.Score(pid3))
+	}
+	p.Decay()
+	if p.Score(pid3) != 0.25 {
+		t.Errorf("Unexpected score after decay: expected 0.25, received %v", p.Score(pid3))
+	}
+}
+
 func TestTrimmedOrderedPeers(t *testing.T) {
 	p := peers.NewStatus(1)
 
@@ -652,6 +733,38 @@ func TestBestFinalized_returnsMaxValue(t *testing.T) {
 	}
 }
 
+func TestBestFinalized_PrefersScoredPeers(t *testing.T) {
+	maxBadResponses := 2
+	p := peers.NewStatus(maxBadResponses)
+	root := [32]byte{'r', 'o', 'o', 't'}
+
+	pids := make([]peer.ID, 3)
+	for i := range pids {
+		pids[i] = addPeer(t, p, peers.PeerConnected)
+		p.SetChainState(pids[i], &pb.Status{
+			FinalizedEpoch: 4,
+			FinalizedRoot:  root[:],
+		})
+	}
+	p.IncrementBlocksServed(pids[1], 64)
+	p.IncrementBlocksServed(pids[2], 128)
+	// A bad peer is never selected, even if it served blocks before.
+	bad := addPeer(t, p, peers.PeerConnected)
+	p.SetChainState(bad, &pb.Status{
+		FinalizedEpoch: 4,
+		FinalizedRoot:  root[:],
+	})
+	p.IncrementBlocksServed(bad, 1024)
+	p.IncrementBadResponses(bad)
+	p.IncrementBadResponses(bad)
+
+	_, _, best := p.BestFinalized(10, 0)
+	want := []peer.ID{pids[2], pids[1], pids[0]}
+	if !reflect.DeepEqual(best, want) {
+		t.Errorf("Unexpected peers; wanted %v but got %v", want, best)
+	}
+}
+
 func TestStatus_CurrentEpoch(t *testing.T) {
 	maxBadResponses := 2
 	p := peers.NewStatus(maxBadResponses)
@@ -57,6 +57,8 @@ const lookupLimit = 15
 const prysmProtocolPrefix = "/prysm/0.0.0"
 
 // maxBadResponses is the maximum number of bad responses from a peer before we stop talking to it.
+// Recorded responses are halved every peers.DecayInterval (10 minutes), and a peer which reaches
+// the limit stays banned for an hour, see the decay and ban constants of the peers package.
 const maxBadResponses = 5
 
 const (
@@ -234,7 +236,7 @@ func (s *Service) Start() {
 	runutil.RunEvery(s.ctx, 5*time.Second, func() {
 		ensurePeerConnections(s.ctx, s.host, peersToWatch...)
 	})
-	runutil.RunEvery(s.ctx, time.Hour, s.Peers().Decay)
+	runutil.RunEvery(s.ctx, peers.DecayInterval, s.Peers().Decay)
 	runutil.RunEvery(s.ctx, 10*time.Second, s.updateMetrics)
 	runutil.RunEvery(s.ctx, refreshRate, func() {
 		s.RefreshENR()
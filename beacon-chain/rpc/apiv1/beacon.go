@@ -0,0 +1,414 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func (s *Server) getGenesis(ctx context.Context, _ *request) (interface{}, error) {
+	genesis, err := s.NodeServer.GetGenesis(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	if genesis.GenesisTime == nil || genesis.GenesisTime.Seconds == 0 {
+		return nil, newAPIError(http.StatusNotFound, "Chain genesis info is not yet known")
+	}
+	return map[string]interface{}{
+		"genesis_time":            strconv.FormatInt(genesis.GenesisTime.Seconds, 10),
+		"genesis_validators_root": encodeHex(genesis.GenesisValidatorsRoot),
+		"genesis_fork_version":    encodeHex(params.BeaconConfig().GenesisForkVersion),
+	}, nil
+}
+
+func (s *Server) getStateRoot(ctx context.Context, req *request) (interface{}, error) {
+	st, err := s.stateByID(ctx, req.params["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	root, err := st.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get state root: %v", err))
+	}
+	return map[string]interface{}{"root": encodeHex(root[:])}, nil
+}
+
+func (s *Server) getStateFork(ctx context.Context, req *request) (interface{}, error) {
+	st, err := s.stateByID(ctx, req.params["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(st.Fork()), nil
+}
+
+func (s *Server) getFinalityCheckpoints(ctx context.Context, req *request) (interface{}, error) {
+	st, err := s.stateByID(ctx, req.params["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"previous_justified": encode(st.PreviousJustifiedCheckpoint()),
+		"current_justified":  encode(st.CurrentJustifiedCheckpoint()),
+		"finalized":          encode(st.FinalizedCheckpoint()),
+	}, nil
+}
+
+func (s *Server) listValidators(ctx context.Context, req *request) (interface{}, error) {
+	st, err := s.stateByID(ctx, req.params["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	var indices []uint64
+	ids := splitQuery(req.query["id"])
+	if len(ids) == 0 {
+		indices = make([]uint64, st.NumValidators())
+		for i := range indices {
+			indices[i] = uint64(i)
+		}
+	} else {
+		indices = make([]uint64, 0, len(ids))
+		for _, id := range ids {
+			idx, err := validatorIndex(st, id)
+			if err != nil {
+				if e, ok := err.(*apiError); ok && e.code == http.StatusNotFound {
+					continue
+				}
+				return nil, err
+			}
+			indices = append(indices, idx)
+		}
+	}
+	statuses := make(map[string]bool)
+	for _, name := range splitQuery(req.query["status"]) {
+		statuses[name] = true
+	}
+
+	res := make([]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		v, err := validatorContainer(st, idx)
+		if err != nil {
+			return nil, err
+		}
+		if len(statuses) > 0 && !statuses[v["status"].(string)] {
+			continue
+		}
+		res = append(res, v)
+	}
+	return res, nil
+}
+
+func (s *Server) getValidator(ctx context.Context, req *request) (interface{}, error) {
+	st, err := s.stateByID(ctx, req.params["state_id"])
+	if err != nil {
+		return nil, err
+	}
+	idx, err := validatorIndex(st, req.params["validator_id"])
+	if err != nil {
+		return nil, err
+	}
+	return validatorContainer(st, idx)
+}
+
+func validatorContainer(st *stateTrie.BeaconState, idx uint64) (map[string]interface{}, error) {
+	v, err := st.ValidatorAtIndex(idx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get validator: %v", err))
+	}
+	balance, err := st.BalanceAtIndex(idx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get validator balance: %v", err))
+	}
+	return map[string]interface{}{
+		"index":     strconv.FormatUint(idx, 10),
+		"balance":   strconv.FormatUint(balance, 10),
+		"status":    validatorStatus(v, helpers.CurrentEpoch(st)),
+		"validator": encode(v),
+	}, nil
+}
+
+// validatorStatus returns the status of a validator at an epoch, as defined by the
+// API specification.
+func validatorStatus(v *ethpb.Validator, epoch uint64) string {
+	farFutureEpoch := params.BeaconConfig().FarFutureEpoch
+	switch {
+	case v.ActivationEpoch > epoch:
+		if v.ActivationEligibilityEpoch == farFutureEpoch {
+			return "pending_initialized"
+		}
+		return "pending_queued"
+	case epoch < v.ExitEpoch:
+		if v.Slashed {
+			return "active_slashed"
+		}
+		if v.ExitEpoch != farFutureEpoch {
+			return "active_exiting"
+		}
+		return "active_ongoing"
+	case epoch < v.WithdrawableEpoch:
+		if v.Slashed {
+			return "exited_slashed"
+		}
+		return "exited_unslashed"
+	case v.EffectiveBalance != 0:
+		return "withdrawal_possible"
+	default:
+		return "withdrawal_done"
+	}
+}
+
+func (s *Server) listBlockHeaders(ctx context.Context, req *request) (interface{}, error) {
+	var blks []*ethpb.SignedBeaconBlock
+	slotParam := req.queryValue("slot")
+	parentRootParam := req.queryValue("parent_root")
+	switch {
+	case slotParam == "" && parentRootParam == "":
+		blk, err := s.HeadFetcher.HeadBlock(ctx)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get head block: %v", err))
+		}
+		if blk != nil {
+			blks = append(blks, blk)
+		}
+	default:
+		f := filters.NewFilter()
+		if slotParam != "" {
+			slot, err := uint64Param("slot", slotParam)
+			if err != nil {
+				return nil, err
+			}
+			f = f.SetStartSlot(slot).SetEndSlot(slot)
+		}
+		if parentRootParam != "" {
+			parentRoot, err := decodeHex(parentRootParam)
+			if err != nil || len(parentRoot) != 32 {
+				return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid parent_root: %s", parentRootParam))
+			}
+			f = f.SetParentRoot(parentRoot)
+		}
+		var err error
+		blks, err = s.BeaconDB.Blocks(ctx, f)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get blocks: %v", err))
+		}
+	}
+
+	res := make([]interface{}, 0, len(blks))
+	for _, blk := range blks {
+		root, err := stateutil.BlockRoot(blk.Block)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get block root: %v", err))
+		}
+		h, err := s.blockHeaderContainer(ctx, blk, root)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, h)
+	}
+	return res, nil
+}
+
+func (s *Server) getBlockHeader(ctx context.Context, req *request) (interface{}, error) {
+	blk, root, err := s.blockByID(ctx, req.params["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return s.blockHeaderContainer(ctx, blk, root)
+}
+
+func (s *Server) blockHeaderContainer(ctx context.Context, blk *ethpb.SignedBeaconBlock, root [32]byte) (map[string]interface{}, error) {
+	bodyRoot, err := stateutil.BlockBodyRoot(blk.Block.Body)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get block body root: %v", err))
+	}
+	canonical, err := s.CanonicalFetcher.IsCanonical(ctx, root)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not determine if block is canonical: %v", err))
+	}
+	header := &ethpb.SignedBeaconBlockHeader{
+		Header: &ethpb.BeaconBlockHeader{
+			Slot:          blk.Block.Slot,
+			ProposerIndex: blk.Block.ProposerIndex,
+			ParentRoot:    blk.Block.ParentRoot,
+			StateRoot:     blk.Block.StateRoot,
+			BodyRoot:      bodyRoot[:],
+		},
+		Signature: blk.Signature,
+	}
+	return map[string]interface{}{
+		"root":      encodeHex(root[:]),
+		"canonical": canonical,
+		"header":    encode(header),
+	}, nil
+}
+
+func (s *Server) getBlock(ctx context.Context, req *request) (interface{}, error) {
+	blk, _, err := s.blockByID(ctx, req.params["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk), nil
+}
+
+func (s *Server) getBlockRoot(ctx context.Context, req *request) (interface{}, error) {
+	_, root, err := s.blockByID(ctx, req.params["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"root": encodeHex(root[:])}, nil
+}
+
+func (s *Server) listBlockAttestations(ctx context.Context, req *request) (interface{}, error) {
+	blk, _, err := s.blockByID(ctx, req.params["block_id"])
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk.Block.Body.Attestations), nil
+}
+
+func (s *Server) submitBlock(ctx context.Context, req *request) (interface{}, error) {
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := decode(req.body, blk); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid block: %v", err))
+	}
+	if blk.Block == nil || blk.Block.Body == nil {
+		return nil, newAPIError(http.StatusBadRequest, "Invalid block: missing block message or body")
+	}
+	if _, err := s.ValidatorServer.ProposeBlock(ctx, blk); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolAttestations(_ context.Context, req *request) (interface{}, error) {
+	filterSlot := req.queryValue("slot") != ""
+	filterIndex := req.queryValue("committee_index") != ""
+	var slot, committeeIndex uint64
+	var err error
+	if filterSlot {
+		if slot, err = uint64Param("slot", req.queryValue("slot")); err != nil {
+			return nil, err
+		}
+	}
+	if filterIndex {
+		if committeeIndex, err = uint64Param("committee_index", req.queryValue("committee_index")); err != nil {
+			return nil, err
+		}
+	}
+	atts := append(s.AttestationsPool.AggregatedAttestations(), s.AttestationsPool.UnaggregatedAttestations()...)
+	res := make([]*ethpb.Attestation, 0, len(atts))
+	for _, att := range atts {
+		if filterSlot && att.Data.Slot != slot {
+			continue
+		}
+		if filterIndex && att.Data.CommitteeIndex != committeeIndex {
+			continue
+		}
+		res = append(res, att)
+	}
+	return encode(res), nil
+}
+
+func (s *Server) submitAttestations(ctx context.Context, req *request) (interface{}, error) {
+	var atts []*ethpb.Attestation
+	if err := decode(req.body, &atts); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid attestations: %v", err))
+	}
+	var failures []string
+	for i, att := range atts {
+		if att.Data == nil || att.Data.Source == nil || att.Data.Target == nil {
+			failures = append(failures, fmt.Sprintf("%d: missing attestation data", i))
+			continue
+		}
+		if _, err := s.ValidatorServer.ProposeAttestation(ctx, att); err != nil {
+			failures = append(failures, fmt.Sprintf("%d: %v", i, err))
+		}
+	}
+	if len(failures) > 0 {
+		return nil, newAPIError(http.StatusBadRequest, "Some attestations failed validation: "+strings.Join(failures, "; "))
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolAttesterSlashings(ctx context.Context, _ *request) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get head state: %v", err))
+	}
+	return encode(s.SlashingsPool.PendingAttesterSlashings(ctx, headState)), nil
+}
+
+func (s *Server) submitAttesterSlashing(ctx context.Context, req *request) (interface{}, error) {
+	slashing := &ethpb.AttesterSlashing{}
+	if err := decode(req.body, slashing); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid attester slashing: %v", err))
+	}
+	if _, err := s.BeaconServer.SubmitAttesterSlashing(ctx, slashing); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolProposerSlashings(ctx context.Context, _ *request) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get head state: %v", err))
+	}
+	return encode(s.SlashingsPool.PendingProposerSlashings(ctx, headState)), nil
+}
+
+func (s *Server) submitProposerSlashing(ctx context.Context, req *request) (interface{}, error) {
+	slashing := &ethpb.ProposerSlashing{}
+	if err := decode(req.body, slashing); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid proposer slashing: %v", err))
+	}
+	if _, err := s.BeaconServer.SubmitProposerSlashing(ctx, slashing); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (s *Server) listPoolVoluntaryExits(ctx context.Context, _ *request) (interface{}, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get head state: %v", err))
+	}
+	return encode(s.ExitPool.PendingExits(headState, headState.Slot())), nil
+}
+
+func (s *Server) submitVoluntaryExit(ctx context.Context, req *request) (interface{}, error) {
+	exit := &ethpb.SignedVoluntaryExit{}
+	if err := decode(req.body, exit); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid voluntary exit: %v", err))
+	}
+	if exit.Exit == nil {
+		return nil, newAPIError(http.StatusBadRequest, "Invalid voluntary exit: missing exit message")
+	}
+	if _, err := s.ValidatorServer.ProposeExit(ctx, exit); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// splitQuery returns the values of a query parameter given either repeatedly or as a
+// comma separated list.
+func splitQuery(values []string) []string {
+	res := make([]string, 0, len(values))
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				res = append(res, item)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,77 @@
+package apiv1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// getSpec returns the beacon chain configuration keyed by the names used in the
+// specification's config files.
+func (s *Server) getSpec(_ context.Context, _ *request) (interface{}, error) {
+	res := make(map[string]string)
+	specConfigValues(reflect.ValueOf(params.BeaconConfig()).Elem(), res)
+	specConfigValues(reflect.ValueOf(params.BeaconNetworkConfig()).Elem(), res)
+	return res, nil
+}
+
+func specConfigValues(v reflect.Value, res map[string]string) {
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Tag.Get("yaml")
+		if name == "" || name == "-" {
+			continue
+		}
+		f := v.Field(i)
+		switch f.Kind() {
+		case reflect.Slice, reflect.Array:
+			if f.Type().Elem().Kind() != reflect.Uint8 {
+				continue
+			}
+			res[name] = encode(f.Interface()).(string)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			res[name] = strconv.FormatUint(f.Uint(), 10)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			res[name] = strconv.FormatInt(f.Int(), 10)
+		default:
+			res[name] = fmt.Sprintf("%v", f.Interface())
+		}
+	}
+}
+
+func (s *Server) getForkSchedule(_ context.Context, _ *request) (interface{}, error) {
+	cfg := params.BeaconConfig()
+	schedule := []interface{}{
+		map[string]interface{}{
+			"previous_version": encodeHex(cfg.GenesisForkVersion),
+			"current_version":  encodeHex(cfg.GenesisForkVersion),
+			"epoch":            strconv.FormatUint(cfg.GenesisEpoch, 10),
+		},
+	}
+	if cfg.NextForkEpoch != cfg.FarFutureEpoch && cfg.NextForkEpoch != cfg.GenesisEpoch {
+		schedule = append(schedule, map[string]interface{}{
+			"previous_version": encodeHex(cfg.GenesisForkVersion),
+			"current_version":  encodeHex(cfg.NextForkVersion),
+			"epoch":            strconv.FormatUint(cfg.NextForkEpoch, 10),
+		})
+	}
+	return schedule, nil
+}
+
+func (s *Server) getDepositContract(ctx context.Context, _ *request) (interface{}, error) {
+	addr, err := s.BeaconDB.DepositContractAddress(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get deposit contract address: %v", err))
+	}
+	address := params.BeaconNetworkConfig().DepositContractAddress
+	if len(addr) > 0 {
+		address = encodeHex(addr)
+	}
+	return map[string]interface{}{
+		"chain_id": strconv.FormatUint(params.BeaconNetworkConfig().DepositChainID, 10),
+		"address":  address,
+	}, nil
+}
@@ -0,0 +1,55 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+)
+
+func (s *Server) getStateReconstruction(_ context.Context, _ *request) (interface{}, error) {
+	return reconstructionContainer(s.StateGen.ReconstructionStatus()), nil
+}
+
+// startStateReconstruction starts reconstructing the historical states of the slot range
+// given as {"start_slot": "...", "end_slot": "..."}. The reconstruction runs in the
+// background until the node stops, its progress is reported by getStateReconstruction.
+func (s *Server) startStateReconstruction(_ context.Context, req *request) (interface{}, error) {
+	var body struct {
+		StartSlot string `json:"start_slot"`
+		EndSlot   string `json:"end_slot"`
+	}
+	if err := json.Unmarshal(req.body, &body); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid slot range: %v", err))
+	}
+	startSlot, err := uint64Param("start slot", body.StartSlot)
+	if err != nil {
+		return nil, err
+	}
+	endSlot, err := uint64Param("end slot", body.EndSlot)
+	if err != nil {
+		return nil, err
+	}
+	// The reconstruction outlives the request.
+	if err := s.StateGen.ReconstructStates(s.Ctx, startSlot, endSlot); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Could not start state reconstruction: %v", err))
+	}
+	return reconstructionContainer(s.StateGen.ReconstructionStatus()), nil
+}
+
+func reconstructionContainer(status stategen.ReconstructionStatus) map[string]interface{} {
+	res := map[string]interface{}{
+		"start_slot":   strconv.FormatUint(status.StartSlot, 10),
+		"end_slot":     strconv.FormatUint(status.EndSlot, 10),
+		"slot":         strconv.FormatUint(status.Slot, 10),
+		"states_saved": strconv.FormatUint(status.StatesSaved, 10),
+		"running":      status.Running,
+	}
+	if status.Err != nil {
+		res["error"] = status.Err.Error()
+	}
+	return res
+}
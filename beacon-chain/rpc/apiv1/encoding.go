@@ -0,0 +1,215 @@
+package apiv1
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+// maxRequestBodySize is the maximum size of an accepted request body.
+const maxRequestBodySize = 1 << 24 // 16 MiB
+
+// specFieldNames maps the protobuf JSON names of v1alpha1 fields to their names in
+// the Ethereum 2.0 API specification, where the two differ.
+var specFieldNames = map[reflect.Type]map[string]string{
+	reflect.TypeOf(ethpb.SignedBeaconBlock{}):       {"block": "message"},
+	reflect.TypeOf(ethpb.SignedBeaconBlockHeader{}): {"header": "message"},
+	reflect.TypeOf(ethpb.SignedVoluntaryExit{}):     {"exit": "message"},
+	reflect.TypeOf(ethpb.ProposerSlashing{}):        {"header_1": "signed_header_1", "header_2": "signed_header_2"},
+	reflect.TypeOf(ethpb.AttestationData{}):         {"committee_index": "index"},
+	reflect.TypeOf(ethpb.Deposit_Data{}):            {"public_key": "pubkey"},
+	reflect.TypeOf(ethpb.Validator{}):               {"public_key": "pubkey"},
+}
+
+// encode converts a v1alpha1 protobuf message, or a slice of them, into a value which
+// marshals to the JSON representation defined by the API specification. Integers are
+// encoded as decimal strings and byte slices as 0x prefixed hex strings.
+func encode(v interface{}) interface{} {
+	return encodeValue(reflect.ValueOf(v))
+}
+
+func encodeValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(v.Elem())
+	case reflect.Struct:
+		res := make(map[string]interface{})
+		for i := 0; i < v.NumField(); i++ {
+			name, ok := fieldName(v.Type(), i)
+			if !ok {
+				continue
+			}
+			res[name] = encodeValue(v.Field(i))
+		}
+		return res
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return encodeHex(b)
+		}
+		res := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res[i] = encodeValue(v.Index(i))
+		}
+		return res
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return v.Interface()
+	}
+}
+
+// decode parses the JSON representation defined by the API specification into the
+// v1alpha1 protobuf message pointed to by v.
+func decode(data []byte, v interface{}) error {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errors.Wrap(err, "could not unmarshal JSON")
+	}
+	return decodeValue(raw, reflect.ValueOf(v).Elem(), "")
+}
+
+func decodeValue(raw interface{}, v reflect.Value, path string) error {
+	if raw == nil {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return decodeValue(raw, v.Elem(), path)
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an object", fieldPath(path))
+		}
+		for i := 0; i < v.NumField(); i++ {
+			name, ok := fieldName(v.Type(), i)
+			if !ok {
+				continue
+			}
+			if err := decodeValue(m[name], v.Field(i), path+"."+name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			s, ok := raw.(string)
+			if !ok {
+				return fmt.Errorf("%s: expected a hex string", fieldPath(path))
+			}
+			b, err := decodeHex(s)
+			if err != nil {
+				return errors.Wrapf(err, "%s", fieldPath(path))
+			}
+			v.Set(reflect.ValueOf(b).Convert(v.Type()))
+			return nil
+		}
+		items, ok := raw.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected an array", fieldPath(path))
+		}
+		res := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(item, res.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		v.Set(res)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a decimal string", fieldPath(path))
+		}
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "%s", fieldPath(path))
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a decimal string", fieldPath(path))
+		}
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "%s", fieldPath(path))
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return fmt.Errorf("%s: expected a boolean", fieldPath(path))
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", fieldPath(path))
+		}
+		v.SetString(s)
+		return nil
+	default:
+		return fmt.Errorf("%s: unsupported type %s", fieldPath(path), v.Type())
+	}
+}
+
+// fieldName returns the API specification name of the i'th field of a protobuf
+// message type, and false for fields which are not part of the message.
+func fieldName(t reflect.Type, i int) (string, bool) {
+	f := t.Field(i)
+	if f.PkgPath != "" || strings.HasPrefix(f.Name, "XXX_") {
+		return "", false
+	}
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return "", false
+	}
+	if renamed, ok := specFieldNames[t][name]; ok {
+		return renamed, true
+	}
+	return name, true
+}
+
+func fieldPath(path string) string {
+	if path == "" {
+		return "request body"
+	}
+	return strings.TrimPrefix(path, ".")
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+func decodeHex(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "0x") {
+		return nil, errors.New("hex string without 0x prefix")
+	}
+	return hex.DecodeString(s[2:])
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	return ioutil.ReadAll(io.LimitReader(r.Body, maxRequestBodySize))
+}
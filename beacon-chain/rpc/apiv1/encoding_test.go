@@ -0,0 +1,119 @@
+package apiv1
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestEncode_SpecFieldNames(t *testing.T) {
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 5
+	blk.Block.ParentRoot = bytesutil.PadTo([]byte{0xab}, 32)
+	blk.Block.Body.Attestations = []*ethpb.Attestation{{
+		AggregationBits: bitfield.Bitlist{0b1101},
+		Data: &ethpb.AttestationData{
+			Slot:            4,
+			CommitteeIndex:  2,
+			BeaconBlockRoot: make([]byte, 32),
+			Source:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+			Target:          &ethpb.Checkpoint{Root: make([]byte, 32)},
+		},
+		Signature: make([]byte, 96),
+	}}
+
+	enc, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(enc)
+	for _, want := range []string{
+		`"message":{`,
+		`"slot":"5"`,
+		`"parent_root":"0xab00`,
+		`"aggregation_bits":"0x0d"`,
+		`"index":"2"`,
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected %s in encoded block %s", want, s)
+		}
+	}
+	if strings.Contains(s, "XXX_") || strings.Contains(s, `"block":`) {
+		t.Errorf("Unexpected field in encoded block %s", s)
+	}
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 1 << 60
+	blk.Block.ProposerIndex = 7
+	blk.Block.Body.ProposerSlashings = []*ethpb.ProposerSlashing{{
+		Header_1: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{Slot: 3, ParentRoot: make([]byte, 32), StateRoot: make([]byte, 32), BodyRoot: make([]byte, 32)},
+			Signature: make([]byte, 96),
+		},
+		Header_2: &ethpb.SignedBeaconBlockHeader{
+			Header:    &ethpb.BeaconBlockHeader{Slot: 3, ParentRoot: make([]byte, 32), StateRoot: make([]byte, 32), BodyRoot: make([]byte, 32)},
+			Signature: make([]byte, 96),
+		},
+	}}
+	blk.Block.Body.VoluntaryExits = []*ethpb.SignedVoluntaryExit{{
+		Exit:      &ethpb.VoluntaryExit{Epoch: 2, ValidatorIndex: 9},
+		Signature: make([]byte, 96),
+	}}
+
+	enc, err := json.Marshal(encode(blk))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &ethpb.SignedBeaconBlock{}
+	if err := decode(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(blk, decoded) {
+		t.Errorf("Wanted %v, received %v", blk, decoded)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantedErr string
+	}{
+		{
+			name:      "Invalid JSON",
+			input:     `{`,
+			wantedErr: "could not unmarshal JSON",
+		},
+		{
+			name:      "Number instead of string",
+			input:     `{"message":{"slot":1}}`,
+			wantedErr: "message.slot: expected a decimal string",
+		},
+		{
+			name:      "Hex without prefix",
+			input:     `{"signature":"abcd"}`,
+			wantedErr: "signature: hex string without 0x prefix",
+		},
+		{
+			name:      "Array instead of object",
+			input:     `[]`,
+			wantedErr: "request body: expected an object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := decode([]byte(tt.input), &ethpb.SignedBeaconBlock{})
+			if err == nil || !strings.Contains(err.Error(), tt.wantedErr) {
+				t.Errorf("Expected error %q, received %v", tt.wantedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+)
+
+// Topics which can be subscribed to through the event stream.
+const (
+	topicHead                = "head"
+	topicBlock               = "block"
+	topicAttestation         = "attestation"
+	topicVoluntaryExit       = "voluntary_exit"
+	topicFinalizedCheckpoint = "finalized_checkpoint"
+	topicChainReorg          = "chain_reorg"
+)
+
+var eventTopics = map[string]bool{
+	topicHead:                true,
+	topicBlock:               true,
+	topicAttestation:         true,
+	topicVoluntaryExit:       true,
+	topicFinalizedCheckpoint: true,
+	topicChainReorg:          true,
+}
+
+const (
+	// eventBufferSize is the number of feed events buffered per subscriber.
+	eventBufferSize = 64
+	// eventQueueSize is the number of events queued for writing to a client. A client
+	// falling further behind is disconnected, rather than holding up the services
+	// sending to the feeds.
+	eventQueueSize = 256
+)
+
+// event is a server-sent event queued for writing to a client.
+type event struct {
+	topic string
+	data  interface{}
+}
+
+// streamEvents writes the events of the requested topics to the client as
+// server-sent events until the client disconnects.
+func (s *Server) streamEvents(w http.ResponseWriter, r *http.Request, req *request) error {
+	topics := make(map[string]bool)
+	for _, topic := range splitQuery(req.query["topics"]) {
+		if !eventTopics[topic] {
+			return newAPIError(http.StatusBadRequest, "Invalid topic: "+topic)
+		}
+		topics[topic] = true
+	}
+	if len(topics) == 0 {
+		return newAPIError(http.StatusBadRequest, "No topics requested")
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return newAPIError(http.StatusInternalServerError, "Streaming is not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	queue := make(chan *event, eventQueueSize)
+	go s.forwardEvents(ctx, cancel, topics, queue)
+
+	for {
+		select {
+		case ev := <-queue:
+			if err := writeEvent(w, ev.topic, ev.data); err != nil {
+				log.WithError(err).Debug("Could not write event, closing stream")
+				return nil
+			}
+			flusher.Flush()
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// forwardEvents queues the feed events of the requested topics for writing to the client.
+// It never blocks on the client, so that sending to the feeds is never held up by a slow
+// client: if the queue is full, the stream is cancelled. The feeds are unsubscribed from
+// once the stream is done.
+func (s *Server) forwardEvents(ctx context.Context, cancel context.CancelFunc, topics map[string]bool, queue chan<- *event) {
+	stateChannel := make(chan *feed.Event, eventBufferSize)
+	stateSub := s.StateNotifier.StateFeed().Subscribe(stateChannel)
+	defer stateSub.Unsubscribe()
+	opChannel := make(chan *feed.Event, eventBufferSize)
+	opSub := s.OperationNotifier.OperationFeed().Subscribe(opChannel)
+	defer opSub.Unsubscribe()
+
+	for {
+		ev := &event{}
+		select {
+		case e := <-stateChannel:
+			ev.topic, ev.data = stateEvent(e)
+		case e := <-opChannel:
+			ev.topic, ev.data = operationEvent(e)
+		case <-stateSub.Err():
+			cancel()
+			return
+		case <-opSub.Err():
+			cancel()
+			return
+		case <-ctx.Done():
+			return
+		}
+		if ev.topic == "" || !topics[ev.topic] {
+			continue
+		}
+		select {
+		case queue <- ev:
+		default:
+			log.Debug("Event stream client is too slow, closing stream")
+			cancel()
+			return
+		}
+	}
+}
+
+// writeEvent writes a single server-sent event frame.
+func writeEvent(w http.ResponseWriter, topic string, data interface{}) error {
+	enc, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", topic, enc)
+	return err
+}
+
+// stateEvent returns the topic and payload of a state feed event, or an empty
+// topic if the event is not exposed through the event stream.
+func stateEvent(event *feed.Event) (string, interface{}) {
+	switch event.Type {
+	case statefeed.NewHead:
+		data, ok := event.Data.(*statefeed.NewHeadData)
+		if !ok {
+			return "", nil
+		}
+		return topicHead, map[string]interface{}{
+			"slot":             strconv.FormatUint(data.Slot, 10),
+			"block":            encodeHex(data.BlockRoot[:]),
+			"state":            encodeHex(data.StateRoot[:]),
+			"epoch_transition": data.EpochTransition,
+		}
+	case statefeed.BlockProcessed:
+		data, ok := event.Data.(*statefeed.BlockProcessedData)
+		if !ok {
+			return "", nil
+		}
+		return topicBlock, map[string]interface{}{
+			"slot":  strconv.FormatUint(data.Slot, 10),
+			"block": encodeHex(data.BlockRoot[:]),
+		}
+	case statefeed.FinalizedCheckpoint:
+		data, ok := event.Data.(*statefeed.FinalizedCheckpointData)
+		if !ok {
+			return "", nil
+		}
+		return topicFinalizedCheckpoint, map[string]interface{}{
+			"block": encodeHex(data.BlockRoot[:]),
+			"state": encodeHex(data.StateRoot[:]),
+			"epoch": strconv.FormatUint(data.Epoch, 10),
+		}
+	case statefeed.Reorg:
+		data, ok := event.Data.(*statefeed.ReorgData)
+		if !ok {
+			return "", nil
+		}
+		return topicChainReorg, map[string]interface{}{
+			"slot":           strconv.FormatUint(data.NewSlot, 10),
+			"depth":          strconv.FormatUint(data.Depth, 10),
+			"old_head_block": encodeHex(data.OldHeadRoot[:]),
+			"new_head_block": encodeHex(data.NewHeadRoot[:]),
+			"epoch":          strconv.FormatUint(helpers.SlotToEpoch(data.NewSlot), 10),
+		}
+	}
+	return "", nil
+}
+
+// operationEvent returns the topic and payload of an operation feed event, or
+// an empty topic if the event is not exposed through the event stream.
+func operationEvent(event *feed.Event) (string, interface{}) {
+	switch event.Type {
+	case opfeed.UnaggregatedAttReceived:
+		data, ok := event.Data.(*opfeed.UnAggregatedAttReceivedData)
+		if !ok || data.Attestation == nil {
+			return "", nil
+		}
+		return topicAttestation, encode(data.Attestation)
+	case opfeed.AggregatedAttReceived:
+		data, ok := event.Data.(*opfeed.AggregatedAttReceivedData)
+		if !ok || data.Attestation == nil || data.Attestation.Aggregate == nil {
+			return "", nil
+		}
+		return topicAttestation, encode(data.Attestation.Aggregate)
+	case opfeed.ExitReceived:
+		data, ok := event.Data.(*opfeed.ExitReceivedData)
+		if !ok || data.Exit == nil {
+			return "", nil
+		}
+		return topicVoluntaryExit, encode(data.Exit)
+	}
+	return "", nil
+}
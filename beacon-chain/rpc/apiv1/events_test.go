@@ -0,0 +1,163 @@
+package apiv1
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/feed"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/shared/event"
+)
+
+// sendUntilSubscribed sends the event once the stream has subscribed to the feed.
+func sendUntilSubscribed(t *testing.T, f *event.Feed, ev *feed.Event) {
+	for i := 0; i < 100; i++ {
+		if f.Send(ev) > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("Stream did not subscribe to the feed")
+}
+
+// readEvent reads a single server-sent event frame and returns its topic and data.
+func readEvent(t *testing.T, r *bufio.Reader) (string, string) {
+	var topic, data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			return topic, data
+		case strings.HasPrefix(line, "event: "):
+			topic = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestServer_StreamEvents(t *testing.T) {
+	stateNotifier := &mock.MockStateNotifier{}
+	opNotifier := &mock.MockOperationNotifier{}
+	stateFeed := stateNotifier.StateFeed()
+	opFeed := opNotifier.OperationFeed()
+	s := &Server{StateNotifier: stateNotifier, OperationNotifier: opNotifier}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	res, err := http.Get(srv.URL + "/eth/v1/events?topics=head,voluntary_exit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Wanted content type text/event-stream, received %s", ct)
+	}
+	reader := bufio.NewReader(res.Body)
+
+	// Events of topics which were not requested are not written to the stream.
+	sendUntilSubscribed(t, stateFeed, &feed.Event{
+		Type: statefeed.BlockProcessed,
+		Data: &statefeed.BlockProcessedData{Slot: 4},
+	})
+	sendUntilSubscribed(t, stateFeed, &feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{Slot: 5, BlockRoot: [32]byte{0xab}, EpochTransition: true},
+	})
+	topic, data := readEvent(t, reader)
+	if topic != topicHead {
+		t.Errorf("Wanted topic %s, received %s", topicHead, topic)
+	}
+	for _, want := range []string{`"slot":"5"`, `"block":"0xab00`, `"epoch_transition":true`} {
+		if !strings.Contains(data, want) {
+			t.Errorf("Expected %s in event data %s", want, data)
+		}
+	}
+
+	sendUntilSubscribed(t, opFeed, &feed.Event{
+		Type: opfeed.ExitReceived,
+		Data: &opfeed.ExitReceivedData{
+			Exit: &ethpb.SignedVoluntaryExit{
+				Exit:      &ethpb.VoluntaryExit{Epoch: 2, ValidatorIndex: 9},
+				Signature: make([]byte, 96),
+			},
+		},
+	})
+	topic, data = readEvent(t, reader)
+	if topic != topicVoluntaryExit {
+		t.Errorf("Wanted topic %s, received %s", topicVoluntaryExit, topic)
+	}
+	if !strings.Contains(data, `"validator_index":"9"`) {
+		t.Errorf("Expected validator index 9 in event data %s", data)
+	}
+}
+
+func TestServer_StreamEvents_InvalidTopic(t *testing.T) {
+	s := &Server{}
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/events?topics=head,unknown", nil)
+	if code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, code)
+	}
+	if res["message"] != "Invalid topic: unknown" {
+		t.Errorf("Unexpected error message %v", res["message"])
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/events", nil); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestServer_StreamEvents_SlowClientDoesNotBlockFeed(t *testing.T) {
+	stateNotifier := &mock.MockStateNotifier{}
+	opNotifier := &mock.MockOperationNotifier{}
+	stateFeed := stateNotifier.StateFeed()
+	s := &Server{StateNotifier: stateNotifier, OperationNotifier: opNotifier}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	// The client never reads the stream.
+	res, err := http.Get(srv.URL + "/eth/v1/events?topics=head")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}()
+	ev := &feed.Event{
+		Type: statefeed.NewHead,
+		Data: &statefeed.NewHeadData{Slot: 1},
+	}
+	sendUntilSubscribed(t, stateFeed, ev)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10*(eventQueueSize+eventBufferSize); i++ {
+			stateFeed.Send(ev)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sending to the feed was held up by a slow client")
+	}
+}
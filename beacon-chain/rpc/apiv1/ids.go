@@ -0,0 +1,220 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db/filters"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// stateByID returns the state identified by one of "head", "genesis", "finalized",
+// "justified", a decimal slot or a 0x prefixed state root.
+func (s *Server) stateByID(ctx context.Context, stateID string) (*stateTrie.BeaconState, error) {
+	var st *stateTrie.BeaconState
+	var err error
+	switch stateID {
+	case "head":
+		st, err = s.HeadFetcher.HeadState(ctx)
+	case "genesis":
+		st, err = s.BeaconDB.GenesisState(ctx)
+	case "finalized":
+		st, err = s.checkpointState(ctx, s.FinalizationFetcher.FinalizedCheckpt())
+	case "justified":
+		st, err = s.checkpointState(ctx, s.FinalizationFetcher.CurrentJustifiedCheckpt())
+	default:
+		if strings.HasPrefix(stateID, "0x") {
+			root, decodeErr := decodeHex(stateID)
+			if decodeErr != nil || len(root) != 32 {
+				return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid state ID: %s", stateID))
+			}
+			st, err = s.stateByStateRoot(ctx, bytesutil.ToBytes32(root))
+			break
+		}
+		slot, parseErr := strconv.ParseUint(stateID, 10, 64)
+		if parseErr != nil {
+			return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid state ID: %s", stateID))
+		}
+		if slot > s.HeadFetcher.HeadSlot() {
+			return nil, newAPIError(http.StatusNotFound, fmt.Sprintf("State for slot %d not found", slot))
+		}
+		st, err = s.StateGen.StateBySlot(ctx, slot)
+	}
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get state: %v", err))
+	}
+	if st == nil {
+		return nil, newAPIError(http.StatusNotFound, "State not found")
+	}
+	return st, nil
+}
+
+// stateByStateRoot returns the state with the given state root, or nil if the state root is
+// unknown. State roots are resolved through the state roots of the head state, which cover
+// the most recent SLOTS_PER_HISTORICAL_ROOT slots, and the state is then regenerated by slot.
+func (s *Server) stateByStateRoot(ctx context.Context, root [32]byte) (*stateTrie.BeaconState, error) {
+	headState, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil || headState == nil {
+		return nil, err
+	}
+	headRoot, err := headState.HashTreeRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if headRoot == root {
+		return headState, nil
+	}
+	roots := headState.StateRoots()
+	n := uint64(len(roots))
+	for i := uint64(1); i <= n && i <= headState.Slot(); i++ {
+		slot := headState.Slot() - i
+		if bytes.Equal(roots[slot%n], root[:]) {
+			return s.StateGen.StateBySlot(ctx, slot)
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) checkpointState(ctx context.Context, cp *ethpb.Checkpoint) (*stateTrie.BeaconState, error) {
+	root, err := s.checkpointRoot(ctx, cp)
+	if err != nil {
+		return nil, err
+	}
+	return s.StateGen.StateByRoot(ctx, root)
+}
+
+// checkpointRoot returns the block root of a checkpoint, which is the zero hash for
+// the checkpoints of the genesis epoch.
+func (s *Server) checkpointRoot(ctx context.Context, cp *ethpb.Checkpoint) ([32]byte, error) {
+	root := bytesutil.ToBytes32(cp.Root)
+	if root != params.BeaconConfig().ZeroHash {
+		return root, nil
+	}
+	genBlock, err := s.BeaconDB.GenesisBlock(ctx)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if genBlock == nil {
+		return [32]byte{}, fmt.Errorf("genesis block not found")
+	}
+	return stateutil.BlockRoot(genBlock.Block)
+}
+
+// blockByID returns the block and its root identified by one of "head", "genesis",
+// "finalized", a decimal slot or a 0x prefixed block root. Blocks requested by slot
+// are only returned when they are part of the canonical chain.
+func (s *Server) blockByID(ctx context.Context, blockID string) (*ethpb.SignedBeaconBlock, [32]byte, error) {
+	var blk *ethpb.SignedBeaconBlock
+	var err error
+	switch blockID {
+	case "head":
+		blk, err = s.HeadFetcher.HeadBlock(ctx)
+	case "genesis":
+		blk, err = s.BeaconDB.GenesisBlock(ctx)
+	case "finalized":
+		var root [32]byte
+		root, err = s.checkpointRoot(ctx, s.FinalizationFetcher.FinalizedCheckpt())
+		if err == nil {
+			blk, err = s.BeaconDB.Block(ctx, root)
+		}
+	default:
+		if strings.HasPrefix(blockID, "0x") {
+			root, decodeErr := decodeHex(blockID)
+			if decodeErr != nil || len(root) != 32 {
+				return nil, [32]byte{}, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid block ID: %s", blockID))
+			}
+			blk, err = s.BeaconDB.Block(ctx, bytesutil.ToBytes32(root))
+			break
+		}
+		slot, parseErr := strconv.ParseUint(blockID, 10, 64)
+		if parseErr != nil {
+			return nil, [32]byte{}, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid block ID: %s", blockID))
+		}
+		blk, err = s.canonicalBlockAtSlot(ctx, slot)
+	}
+	if err != nil {
+		return nil, [32]byte{}, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get block: %v", err))
+	}
+	if blk == nil || blk.Block == nil {
+		return nil, [32]byte{}, newAPIError(http.StatusNotFound, "Block not found")
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return nil, [32]byte{}, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get block root: %v", err))
+	}
+	return blk, root, nil
+}
+
+func (s *Server) canonicalBlockAtSlot(ctx context.Context, slot uint64) (*ethpb.SignedBeaconBlock, error) {
+	blks, err := s.BeaconDB.Blocks(ctx, filters.NewFilter().SetStartSlot(slot).SetEndSlot(slot))
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blks {
+		canonical, err := s.isCanonical(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		if canonical {
+			return b, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Server) isCanonical(ctx context.Context, blk *ethpb.SignedBeaconBlock) (bool, error) {
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		return false, err
+	}
+	return s.CanonicalFetcher.IsCanonical(ctx, root)
+}
+
+// validatorIndex returns the index of a validator identified by a decimal index or a
+// 0x prefixed public key in the given state.
+func validatorIndex(st *stateTrie.BeaconState, validatorID string) (uint64, error) {
+	if strings.HasPrefix(validatorID, "0x") {
+		pubKey, err := decodeHex(validatorID)
+		if err != nil || len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			return 0, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid validator ID: %s", validatorID))
+		}
+		idx, ok := st.ValidatorIndexByPubkey(bytesutil.ToBytes48(pubKey))
+		if !ok {
+			return 0, newAPIError(http.StatusNotFound, fmt.Sprintf("Validator %s not found", validatorID))
+		}
+		return idx, nil
+	}
+	idx, err := strconv.ParseUint(validatorID, 10, 64)
+	if err != nil {
+		return 0, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid validator ID: %s", validatorID))
+	}
+	if idx >= uint64(st.NumValidators()) {
+		return 0, newAPIError(http.StatusNotFound, fmt.Sprintf("Validator %d not found", idx))
+	}
+	return idx, nil
+}
+
+// uint64Param parses a decimal path or query parameter.
+func uint64Param(name, value string) (uint64, error) {
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid %s: %s", name, value))
+	}
+	return n, nil
+}
+
+// queryValue returns the first value of a query parameter, or an empty string.
+func (r *request) queryValue(name string) string {
+	if v := r.query[name]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
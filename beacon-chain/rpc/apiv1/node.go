@@ -0,0 +1,93 @@
+package apiv1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ptypes "github.com/gogo/protobuf/types"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+)
+
+func (s *Server) getIdentity(ctx context.Context, _ *request) (interface{}, error) {
+	host, err := s.NodeServer.GetHost(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, len(host.Addresses))
+	for i, addr := range host.Addresses {
+		addrs[i] = addr + "/p2p/" + host.PeerId
+	}
+	return map[string]interface{}{
+		"peer_id":             host.PeerId,
+		"enr":                 host.Enr,
+		"p2p_addresses":       addrs,
+		"discovery_addresses": []string{},
+		"metadata":            map[string]interface{}{},
+	}, nil
+}
+
+func (s *Server) listPeers(ctx context.Context, _ *request) (interface{}, error) {
+	peers, err := s.NodeServer.ListPeers(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]interface{}, len(peers.Peers))
+	for i, p := range peers.Peers {
+		res[i] = peerContainer(p)
+	}
+	return res, nil
+}
+
+func (s *Server) getPeer(ctx context.Context, req *request) (interface{}, error) {
+	p, err := s.NodeServer.GetPeer(ctx, &ethpb.PeerRequest{PeerId: req.params["peer_id"]})
+	if err != nil {
+		return nil, err
+	}
+	return peerContainer(p), nil
+}
+
+func peerContainer(p *ethpb.Peer) map[string]interface{} {
+	return map[string]interface{}{
+		"peer_id":               p.PeerId,
+		"enr":                   p.Enr,
+		"last_seen_p2p_address": p.Address,
+		"state":                 strings.ToLower(p.ConnectionState.String()),
+		"direction":             strings.ToLower(p.Direction.String()),
+	}
+}
+
+func (s *Server) getVersion(ctx context.Context, _ *request) (interface{}, error) {
+	v, err := s.NodeServer.GetVersion(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"version": v.Version}, nil
+}
+
+func (s *Server) getSyncing(_ context.Context, _ *request) (interface{}, error) {
+	headSlot := s.HeadFetcher.HeadSlot()
+	currentSlot := s.GenesisTimeFetcher.CurrentSlot()
+	distance := uint64(0)
+	if currentSlot > headSlot {
+		distance = currentSlot - headSlot
+	}
+	return map[string]interface{}{
+		"head_slot":     strconv.FormatUint(headSlot, 10),
+		"sync_distance": strconv.FormatUint(distance, 10),
+		"is_syncing":    s.SyncChecker.Syncing(),
+	}, nil
+}
+
+// getHealth responds with 200 if the node is ready, 206 if it is syncing and
+// 503 if it has not started yet.
+func (s *Server) getHealth(_ context.Context, _ *request) (interface{}, error) {
+	if s.SyncChecker.Syncing() {
+		return statusOnly(http.StatusPartialContent), nil
+	}
+	if s.SyncChecker.Status() != nil {
+		return statusOnly(http.StatusServiceUnavailable), nil
+	}
+	return statusOnly(http.StatusOK), nil
+}
@@ -0,0 +1,302 @@
+// Package apiv1 defines an HTTP server implementing the standard Ethereum 2.0
+// beacon node REST API under /eth/v1, built on top of the v1alpha1 gRPC
+// beacon chain, node and validator servers.
+package apiv1
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
+	opfeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/operation"
+	statefeed "github.com/prysmaticlabs/prysm/beacon-chain/core/feed/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/attestations"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/slashings"
+	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server defines a server implementation of the standard beacon node REST API.
+// Requests are served by the in-process gRPC servers where an equivalent
+// v1alpha1 endpoint exists, and from the chain data otherwise.
+type Server struct {
+	Ctx                 context.Context
+	BeaconServer        ethpb.BeaconChainServer
+	NodeServer          ethpb.NodeServer
+	ValidatorServer     ethpb.BeaconNodeValidatorServer
+	BeaconDB            db.ReadOnlyDatabase
+	HeadFetcher         blockchain.HeadFetcher
+	FinalizationFetcher blockchain.FinalizationFetcher
+	CanonicalFetcher    blockchain.CanonicalFetcher
+	GenesisTimeFetcher  blockchain.TimeFetcher
+	GenesisFetcher      blockchain.GenesisFetcher
+	StateGen            *stategen.State
+	SyncChecker         sync.Checker
+	AttestationsPool    attestations.Pool
+	SlashingsPool       *slashings.Pool
+	ExitPool            *voluntaryexits.Pool
+	StateNotifier       statefeed.Notifier
+	OperationNotifier   opfeed.Notifier
+	// EnableDebugEndpoints serves the non-standard /eth/v1/debug endpoints.
+	EnableDebugEndpoints bool
+}
+
+// request holds the parsed parts of an API request passed to a route handler.
+type request struct {
+	params map[string]string
+	query  map[string][]string
+	body   []byte
+}
+
+// handlerFunc serves a single API route. The returned value is encoded as the
+// data field of the response, a nil value results in an empty response body.
+type handlerFunc func(ctx context.Context, req *request) (interface{}, error)
+
+// streamFunc serves a route which writes to the response directly, such as
+// the event stream. An error is only returned if nothing has been written yet.
+type streamFunc func(w http.ResponseWriter, r *http.Request, req *request) error
+
+type route struct {
+	method  string
+	pattern []string
+	handler handlerFunc
+	stream  streamFunc
+}
+
+// statusOnly is returned by handlers which respond with a status code and no body.
+type statusOnly int
+
+// apiError is returned by handlers to respond with a specific HTTP status code.
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func newAPIError(code int, message string) *apiError {
+	return &apiError{code: code, message: message}
+}
+
+// Handler returns an http.Handler serving all the /eth/v1 routes.
+func (s *Server) Handler() http.Handler {
+	routes := make([]*route, 0)
+	add := func(method, pattern string, h handlerFunc) {
+		routes = append(routes, &route{
+			method:  method,
+			pattern: strings.Split(strings.Trim(pattern, "/"), "/"),
+			handler: h,
+		})
+	}
+	addStream := func(method, pattern string, h streamFunc) {
+		routes = append(routes, &route{
+			method:  method,
+			pattern: strings.Split(strings.Trim(pattern, "/"), "/"),
+			stream:  h,
+		})
+	}
+
+	// Beacon endpoints.
+	add(http.MethodGet, "/eth/v1/beacon/genesis", s.getGenesis)
+	add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/root", s.getStateRoot)
+	add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/fork", s.getStateFork)
+	add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/finality_checkpoints", s.getFinalityCheckpoints)
+	add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators", s.listValidators)
+	add(http.MethodGet, "/eth/v1/beacon/states/{state_id}/validators/{validator_id}", s.getValidator)
+	add(http.MethodGet, "/eth/v1/beacon/headers", s.listBlockHeaders)
+	add(http.MethodGet, "/eth/v1/beacon/headers/{block_id}", s.getBlockHeader)
+	add(http.MethodPost, "/eth/v1/beacon/blocks", s.submitBlock)
+	add(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}", s.getBlock)
+	add(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/root", s.getBlockRoot)
+	add(http.MethodGet, "/eth/v1/beacon/blocks/{block_id}/attestations", s.listBlockAttestations)
+	add(http.MethodGet, "/eth/v1/beacon/pool/attestations", s.listPoolAttestations)
+	add(http.MethodPost, "/eth/v1/beacon/pool/attestations", s.submitAttestations)
+	add(http.MethodGet, "/eth/v1/beacon/pool/attester_slashings", s.listPoolAttesterSlashings)
+	add(http.MethodPost, "/eth/v1/beacon/pool/attester_slashings", s.submitAttesterSlashing)
+	add(http.MethodGet, "/eth/v1/beacon/pool/proposer_slashings", s.listPoolProposerSlashings)
+	add(http.MethodPost, "/eth/v1/beacon/pool/proposer_slashings", s.submitProposerSlashing)
+	add(http.MethodGet, "/eth/v1/beacon/pool/voluntary_exits", s.listPoolVoluntaryExits)
+	add(http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", s.submitVoluntaryExit)
+
+	// Node endpoints.
+	add(http.MethodGet, "/eth/v1/node/identity", s.getIdentity)
+	add(http.MethodGet, "/eth/v1/node/peers", s.listPeers)
+	add(http.MethodGet, "/eth/v1/node/peers/{peer_id}", s.getPeer)
+	add(http.MethodGet, "/eth/v1/node/version", s.getVersion)
+	add(http.MethodGet, "/eth/v1/node/syncing", s.getSyncing)
+	add(http.MethodGet, "/eth/v1/node/health", s.getHealth)
+
+	// Config endpoints.
+	add(http.MethodGet, "/eth/v1/config/spec", s.getSpec)
+	add(http.MethodGet, "/eth/v1/config/fork_schedule", s.getForkSchedule)
+	add(http.MethodGet, "/eth/v1/config/deposit_contract", s.getDepositContract)
+
+	// Validator endpoints.
+	add(http.MethodPost, "/eth/v1/validator/duties/attester/{epoch}", s.getAttesterDuties)
+	add(http.MethodGet, "/eth/v1/validator/duties/proposer/{epoch}", s.getProposerDuties)
+	add(http.MethodGet, "/eth/v1/validator/blocks/{slot}", s.produceBlock)
+	add(http.MethodGet, "/eth/v1/validator/attestation_data", s.produceAttestationData)
+	add(http.MethodGet, "/eth/v1/validator/aggregate_attestation", s.getAggregateAttestation)
+	add(http.MethodPost, "/eth/v1/validator/aggregate_and_proofs", s.submitAggregateAndProofs)
+	add(http.MethodPost, "/eth/v1/validator/beacon_committee_subscriptions", s.submitBeaconCommitteeSubscriptions)
+
+	// Event endpoints.
+	addStream(http.MethodGet, "/eth/v1/events", s.streamEvents)
+
+	// Debug endpoints.
+	if s.EnableDebugEndpoints {
+		add(http.MethodGet, "/eth/v1/debug/states/reconstruction", s.getStateReconstruction)
+		add(http.MethodPost, "/eth/v1/debug/states/reconstruction", s.startStateReconstruction)
+	}
+
+	return &router{routes: routes}
+}
+
+// router matches requests to routes by method and path, where path segments
+// of the form {name} match any value and are passed to the handler.
+type router struct {
+	routes []*route
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	pathMatched := false
+	for _, rte := range rt.routes {
+		params, ok := matchPattern(rte.pattern, segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rte.method != r.Method {
+			continue
+		}
+		req := &request{
+			params: params,
+			query:  r.URL.Query(),
+		}
+		if r.Body != nil && r.Method == http.MethodPost {
+			body, err := readBody(r)
+			if err != nil {
+				writeError(w, newAPIError(http.StatusBadRequest, "Could not read request body: "+err.Error()))
+				return
+			}
+			req.body = body
+		}
+		if rte.stream != nil {
+			if err := rte.stream(w, r, req); err != nil {
+				writeError(w, err)
+			}
+			return
+		}
+		data, err := rte.handler(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeData(w, data)
+		return
+	}
+	if pathMatched {
+		writeError(w, newAPIError(http.StatusMethodNotAllowed, "Method not allowed"))
+		return
+	}
+	writeError(w, newAPIError(http.StatusNotFound, "Route not found"))
+}
+
+func matchPattern(pattern []string, segments []string) (map[string]string, bool) {
+	if len(pattern) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(p, "{}")] = segments[i]
+			continue
+		}
+		if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	if code, ok := data.(statusOnly); ok {
+		w.WriteHeader(int(code))
+		return
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+// writeError responds with the error message and an HTTP status code derived
+// from the error, translating gRPC status codes from the underlying servers.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	msg := err.Error()
+	if e, ok := err.(*apiError); ok {
+		code = e.code
+	} else if st, ok := status.FromError(err); ok {
+		code = httpStatusFromCode(st.Code())
+		msg = st.Message()
+	}
+	writeJSON(w, code, map[string]interface{}{
+		"code":    code,
+		"message": msg,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	enc, err := json.Marshal(v)
+	if err != nil {
+		log.WithError(err).Error("Could not encode API response")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if _, err := w.Write(enc); err != nil {
+		log.WithError(err).Debug("Could not write API response")
+	}
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,306 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	mock "github.com/prysmaticlabs/prysm/beacon-chain/blockchain/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	dbTest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stategen"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	mockSync "github.com/prysmaticlabs/prysm/beacon-chain/sync/initial-sync/testing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func doRequest(t *testing.T, s *Server, method, path string, body []byte) (int, map[string]interface{}) {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	if rec.Body.Len() == 0 {
+		return rec.Code, nil
+	}
+	res := make(map[string]interface{})
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("Could not unmarshal response %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, res
+}
+
+func TestRouter_Errors(t *testing.T) {
+	s := &Server{}
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/unknown", nil)
+	if code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, code)
+	}
+	if res["code"] != float64(http.StatusNotFound) {
+		t.Errorf("Wanted error code %d in response, received %v", http.StatusNotFound, res["code"])
+	}
+	code, _ = doRequest(t, s, http.MethodPost, "/eth/v1/node/version", nil)
+	if code != http.StatusMethodNotAllowed {
+		t.Errorf("Wanted status %d, received %d", http.StatusMethodNotAllowed, code)
+	}
+}
+
+func TestServer_GetSyncing(t *testing.T) {
+	st := testutil.NewBeaconState()
+	if err := st.SetSlot(10); err != nil {
+		t.Fatal(err)
+	}
+	secondsPerSlot := time.Duration(params.BeaconConfig().SecondsPerSlot) * time.Second
+	chain := &mock.ChainService{State: st, Genesis: time.Now().Add(-15 * secondsPerSlot)}
+	s := &Server{
+		HeadFetcher:        chain,
+		GenesisTimeFetcher: chain,
+		SyncChecker:        &mockSync.Sync{IsSyncing: true},
+	}
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/node/syncing", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	data := res["data"].(map[string]interface{})
+	if data["head_slot"] != "10" {
+		t.Errorf("Wanted head slot 10, received %v", data["head_slot"])
+	}
+	if data["sync_distance"] != "5" {
+		t.Errorf("Wanted sync distance 5, received %v", data["sync_distance"])
+	}
+	if data["is_syncing"] != true {
+		t.Errorf("Wanted is_syncing true, received %v", data["is_syncing"])
+	}
+}
+
+func TestServer_GetHealth(t *testing.T) {
+	s := &Server{SyncChecker: &mockSync.Sync{IsSyncing: true}}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/node/health", nil); code != http.StatusPartialContent {
+		t.Errorf("Wanted status %d, received %d", http.StatusPartialContent, code)
+	}
+	s.SyncChecker = &mockSync.Sync{IsSyncing: false}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/node/health", nil); code != http.StatusOK {
+		t.Errorf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+}
+
+func TestServer_GetSpec(t *testing.T) {
+	s := &Server{}
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/config/spec", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	data := res["data"].(map[string]interface{})
+	want := strconv.FormatUint(params.BeaconConfig().SlotsPerEpoch, 10)
+	if data["SLOTS_PER_EPOCH"] != want {
+		t.Errorf("Wanted SLOTS_PER_EPOCH %s, received %v", want, data["SLOTS_PER_EPOCH"])
+	}
+	if data["DOMAIN_BEACON_PROPOSER"] != "0x00000000" {
+		t.Errorf("Wanted DOMAIN_BEACON_PROPOSER 0x00000000, received %v", data["DOMAIN_BEACON_PROPOSER"])
+	}
+}
+
+func TestServer_GetValidator(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 16)
+	s := &Server{HeadFetcher: &mock.ChainService{State: st}}
+
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators/3", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	data := res["data"].(map[string]interface{})
+	if data["index"] != "3" {
+		t.Errorf("Wanted index 3, received %v", data["index"])
+	}
+	if data["status"] != "active_ongoing" {
+		t.Errorf("Wanted status active_ongoing, received %v", data["status"])
+	}
+	pubKey := st.PubkeyAtIndex(3)
+	validator := data["validator"].(map[string]interface{})
+	if validator["pubkey"] != encodeHex(pubKey[:]) {
+		t.Errorf("Wanted pubkey %#x, received %v", pubKey, validator["pubkey"])
+	}
+
+	code, res = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators/"+encodeHex(pubKey[:]), nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	if res["data"].(map[string]interface{})["index"] != "3" {
+		t.Errorf("Wanted index 3, received %v", res["data"])
+	}
+
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators/16", nil); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, code)
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/0xabcd/validators/1", nil); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, code)
+	}
+
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, res = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/"+encodeHex(stateRoot[:])+"/validators/3", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	unknownRoot := bytes.Repeat([]byte{0x01}, 32)
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/"+encodeHex(unknownRoot)+"/validators/3", nil); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, code)
+	}
+}
+
+func TestServer_ListValidators_Filters(t *testing.T) {
+	st, _ := testutil.DeterministicGenesisState(t, 16)
+	s := &Server{HeadFetcher: &mock.ChainService{State: st}}
+
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators?id=1,2&id=5", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	if n := len(res["data"].([]interface{})); n != 3 {
+		t.Errorf("Wanted 3 validators, received %d", n)
+	}
+	code, res = doRequest(t, s, http.MethodGet, "/eth/v1/beacon/states/head/validators?status=pending_queued", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	if n := len(res["data"].([]interface{})); n != 0 {
+		t.Errorf("Wanted 0 validators, received %d", n)
+	}
+}
+
+func TestServer_GetBlock(t *testing.T) {
+	db, _ := dbTest.SetupDB(t)
+	ctx := context.Background()
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = 3
+	if err := db.SaveBlock(ctx, blk); err != nil {
+		t.Fatal(err)
+	}
+	root, err := stateutil.BlockRoot(blk.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Server{
+		BeaconDB:         db,
+		HeadFetcher:      &mock.ChainService{Block: blk},
+		CanonicalFetcher: &mock.ChainService{},
+	}
+
+	for _, id := range []string{"head", "3", fmt.Sprintf("%#x", root)} {
+		code, res := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/"+id, nil)
+		if code != http.StatusOK {
+			t.Fatalf("Wanted status %d for block ID %s, received %d: %v", http.StatusOK, id, code, res)
+		}
+		msg := res["data"].(map[string]interface{})["message"].(map[string]interface{})
+		if msg["slot"] != "3" {
+			t.Errorf("Wanted slot 3 for block ID %s, received %v", id, msg["slot"])
+		}
+	}
+
+	code, res := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/head/root", nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d: %v", http.StatusOK, code, res)
+	}
+	if res["data"].(map[string]interface{})["root"] != fmt.Sprintf("%#x", root) {
+		t.Errorf("Wanted root %#x, received %v", root, res["data"])
+	}
+	if code, _ := doRequest(t, s, http.MethodGet, "/eth/v1/beacon/blocks/4", nil); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d, received %d", http.StatusNotFound, code)
+	}
+}
+
+func TestServer_SubmitVoluntaryExit_InvalidBody(t *testing.T) {
+	s := &Server{}
+	code, _ := doRequest(t, s, http.MethodPost, "/eth/v1/beacon/pool/voluntary_exits", []byte(`{"message":{"epoch":1}}`))
+	if code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, code)
+	}
+}
+
+func TestValidatorStatus(t *testing.T) {
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	tests := []struct {
+		validator *ethpb.Validator
+		want      string
+	}{
+		{
+			validator: &ethpb.Validator{ActivationEligibilityEpoch: farFuture, ActivationEpoch: farFuture, ExitEpoch: farFuture},
+			want:      "pending_initialized",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEligibilityEpoch: 1, ActivationEpoch: farFuture, ExitEpoch: farFuture},
+			want:      "pending_queued",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: farFuture},
+			want:      "active_ongoing",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 20},
+			want:      "active_exiting",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 20, Slashed: true},
+			want:      "active_slashed",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 5, WithdrawableEpoch: 20},
+			want:      "exited_unslashed",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 5, WithdrawableEpoch: 20, Slashed: true},
+			want:      "exited_slashed",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 5, WithdrawableEpoch: 6, EffectiveBalance: 1},
+			want:      "withdrawal_possible",
+		},
+		{
+			validator: &ethpb.Validator{ActivationEpoch: 1, ExitEpoch: 5, WithdrawableEpoch: 6},
+			want:      "withdrawal_done",
+		},
+	}
+	for _, tt := range tests {
+		if got := validatorStatus(tt.validator, 10); got != tt.want {
+			t.Errorf("Wanted status %s for %v, received %s", tt.want, tt.validator, got)
+		}
+	}
+}
+
+func TestServer_StateReconstruction(t *testing.T) {
+	db, _ := dbTest.SetupDB(t)
+	s := &Server{
+		Ctx:      context.Background(),
+		StateGen: stategen.New(db, cache.NewStateSummaryCache()),
+	}
+	path := "/eth/v1/debug/states/reconstruction"
+	if code, _ := doRequest(t, s, http.MethodGet, path, nil); code != http.StatusNotFound {
+		t.Errorf("Wanted status %d without debug endpoints, received %d", http.StatusNotFound, code)
+	}
+
+	s.EnableDebugEndpoints = true
+	code, res := doRequest(t, s, http.MethodGet, path, nil)
+	if code != http.StatusOK {
+		t.Fatalf("Wanted status %d, received %d", http.StatusOK, code)
+	}
+	data, ok := res["data"].(map[string]interface{})
+	if !ok || data["running"] != false || data["states_saved"] != "0" {
+		t.Errorf("Wanted an idle reconstruction, received %v", res)
+	}
+	if code, _ := doRequest(t, s, http.MethodPost, path, []byte(`{"start_slot": "a"}`)); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d for an invalid slot, received %d", http.StatusBadRequest, code)
+	}
+	// Nothing is finalized yet, and the reconstruction interval is not set.
+	body := []byte(`{"start_slot": "0", "end_slot": "64"}`)
+	if code, _ := doRequest(t, s, http.MethodPost, path, body); code != http.StatusBadRequest {
+		t.Errorf("Wanted status %d, received %d", http.StatusBadRequest, code)
+	}
+}
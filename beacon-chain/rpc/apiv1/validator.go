@@ -0,0 +1,291 @@
+package apiv1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	attaggregation "github.com/prysmaticlabs/prysm/shared/aggregation/attestations"
+)
+
+// dutiesState returns the head state advanced to the start of the requested epoch, which
+// may be at most one epoch ahead of the current epoch.
+func (s *Server) dutiesState(ctx context.Context, epoch uint64) (*stateTrie.BeaconState, error) {
+	if s.SyncChecker.Syncing() {
+		return nil, newAPIError(http.StatusServiceUnavailable, "Syncing to latest head, not ready to respond")
+	}
+	currentEpoch := helpers.SlotToEpoch(s.GenesisTimeFetcher.CurrentSlot())
+	if epoch > currentEpoch+1 {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Epoch %d is more than one epoch ahead of current epoch %d", epoch, currentEpoch))
+	}
+	st, err := s.HeadFetcher.HeadState(ctx)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get head state: %v", err))
+	}
+	if epochStartSlot := helpers.StartSlot(epoch); st.Slot() < epochStartSlot {
+		st, err = state.ProcessSlots(ctx, st, epochStartSlot)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not process slots up to %d: %v", epochStartSlot, err))
+		}
+	}
+	return st, nil
+}
+
+func (s *Server) getAttesterDuties(ctx context.Context, req *request) (interface{}, error) {
+	epoch, err := uint64Param("epoch", req.params["epoch"])
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal(req.body, &ids); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid validator indices: %v", err))
+	}
+	indices := make([]uint64, len(ids))
+	for i, id := range ids {
+		if indices[i], err = uint64Param("validator index", id); err != nil {
+			return nil, err
+		}
+	}
+
+	st, err := s.dutiesState(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	assignments, _, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not compute committee assignments: %v", err))
+	}
+	activeCount, err := helpers.ActiveValidatorCount(st, epoch)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get active validator count: %v", err))
+	}
+	committeesAtSlot := helpers.SlotCommitteeCount(activeCount)
+
+	res := make([]interface{}, 0, len(indices))
+	for _, idx := range indices {
+		ca, ok := assignments[idx]
+		if !ok {
+			continue
+		}
+		v, err := st.ValidatorAtIndexReadOnly(idx)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get validator: %v", err))
+		}
+		pubKey := v.PublicKey()
+		position := 0
+		for i, member := range ca.Committee {
+			if member == idx {
+				position = i
+				break
+			}
+		}
+		res = append(res, map[string]interface{}{
+			"pubkey":                    encodeHex(pubKey[:]),
+			"validator_index":           strconv.FormatUint(idx, 10),
+			"committee_index":           strconv.FormatUint(ca.CommitteeIndex, 10),
+			"committee_length":          strconv.Itoa(len(ca.Committee)),
+			"committees_at_slot":        strconv.FormatUint(committeesAtSlot, 10),
+			"validator_committee_index": strconv.Itoa(position),
+			"slot":                      strconv.FormatUint(ca.AttesterSlot, 10),
+		})
+	}
+	return res, nil
+}
+
+func (s *Server) getProposerDuties(ctx context.Context, req *request) (interface{}, error) {
+	epoch, err := uint64Param("epoch", req.params["epoch"])
+	if err != nil {
+		return nil, err
+	}
+	st, err := s.dutiesState(ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	_, proposerIndexToSlots, err := helpers.CommitteeAssignments(st, epoch)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not compute committee assignments: %v", err))
+	}
+
+	res := make([]interface{}, 0, len(proposerIndexToSlots))
+	for slot := helpers.StartSlot(epoch); slot < helpers.StartSlot(epoch+1); slot++ {
+		for idx, slots := range proposerIndexToSlots {
+			if !containsSlot(slots, slot) {
+				continue
+			}
+			v, err := st.ValidatorAtIndexReadOnly(idx)
+			if err != nil {
+				return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not get validator: %v", err))
+			}
+			pubKey := v.PublicKey()
+			res = append(res, map[string]interface{}{
+				"pubkey":          encodeHex(pubKey[:]),
+				"validator_index": strconv.FormatUint(idx, 10),
+				"slot":            strconv.FormatUint(slot, 10),
+			})
+		}
+	}
+	return res, nil
+}
+
+func containsSlot(slots []uint64, slot uint64) bool {
+	for _, s := range slots {
+		if s == slot {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) produceBlock(ctx context.Context, req *request) (interface{}, error) {
+	slot, err := uint64Param("slot", req.params["slot"])
+	if err != nil {
+		return nil, err
+	}
+	randaoReveal, err := decodeHex(req.queryValue("randao_reveal"))
+	if err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid randao_reveal: %v", err))
+	}
+	var graffiti []byte
+	if g := req.queryValue("graffiti"); g != "" {
+		if graffiti, err = decodeHex(g); err != nil || len(graffiti) > 32 {
+			return nil, newAPIError(http.StatusBadRequest, "Invalid graffiti")
+		}
+	}
+	blk, err := s.ValidatorServer.GetBlock(ctx, &ethpb.BlockRequest{
+		Slot:         slot,
+		RandaoReveal: randaoReveal,
+		Graffiti:     graffiti,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encode(blk), nil
+}
+
+func (s *Server) produceAttestationData(ctx context.Context, req *request) (interface{}, error) {
+	slot, err := uint64Param("slot", req.queryValue("slot"))
+	if err != nil {
+		return nil, err
+	}
+	committeeIndex, err := uint64Param("committee_index", req.queryValue("committee_index"))
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.ValidatorServer.GetAttestationData(ctx, &ethpb.AttestationDataRequest{
+		Slot:           slot,
+		CommitteeIndex: committeeIndex,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return encode(data), nil
+}
+
+// getAggregateAttestation aggregates the attestations in the pool matching the requested
+// slot and attestation data root, and returns the aggregate with the most participants.
+func (s *Server) getAggregateAttestation(_ context.Context, req *request) (interface{}, error) {
+	slot, err := uint64Param("slot", req.queryValue("slot"))
+	if err != nil {
+		return nil, err
+	}
+	dataRoot, err := decodeHex(req.queryValue("attestation_data_root"))
+	if err != nil || len(dataRoot) != 32 {
+		return nil, newAPIError(http.StatusBadRequest, "Invalid attestation_data_root")
+	}
+
+	atts := append(s.AttestationsPool.AggregatedAttestations(), s.AttestationsPool.UnaggregatedAttestations()...)
+	matching := make([]*ethpb.Attestation, 0)
+	for _, att := range atts {
+		if att.Data.Slot != slot {
+			continue
+		}
+		root, err := stateutil.AttestationDataRoot(att.Data)
+		if err != nil {
+			return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not hash attestation data: %v", err))
+		}
+		if bytes.Equal(root[:], dataRoot) {
+			matching = append(matching, att)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, newAPIError(http.StatusNotFound, "No matching attestations in the pool")
+	}
+	aggregated, err := attaggregation.Aggregate(matching)
+	if err != nil {
+		return nil, newAPIError(http.StatusInternalServerError, fmt.Sprintf("Could not aggregate attestations: %v", err))
+	}
+	best := aggregated[0]
+	for _, att := range aggregated[1:] {
+		if att.AggregationBits.Count() > best.AggregationBits.Count() {
+			best = att
+		}
+	}
+	return encode(best), nil
+}
+
+func (s *Server) submitAggregateAndProofs(ctx context.Context, req *request) (interface{}, error) {
+	var aggregates []*ethpb.SignedAggregateAttestationAndProof
+	if err := decode(req.body, &aggregates); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid aggregate and proofs: %v", err))
+	}
+	var failures []string
+	for i, agg := range aggregates {
+		if _, err := s.ValidatorServer.SubmitSignedAggregateSelectionProof(ctx, &ethpb.SignedAggregateSubmitRequest{
+			SignedAggregateAndProof: agg,
+		}); err != nil {
+			failures = append(failures, fmt.Sprintf("%d: %v", i, err))
+		}
+	}
+	if len(failures) > 0 {
+		return nil, newAPIError(http.StatusBadRequest, "Some aggregate and proofs failed validation: "+strings.Join(failures, "; "))
+	}
+	return nil, nil
+}
+
+// committeeSubscription is a beacon committee subnet subscription as defined by the
+// API specification.
+type committeeSubscription struct {
+	ValidatorIndex   string `json:"validator_index"`
+	CommitteeIndex   string `json:"committee_index"`
+	CommitteesAtSlot string `json:"committees_at_slot"`
+	Slot             string `json:"slot"`
+	IsAggregator     bool   `json:"is_aggregator"`
+}
+
+func (s *Server) submitBeaconCommitteeSubscriptions(ctx context.Context, req *request) (interface{}, error) {
+	var subs []*committeeSubscription
+	if err := json.Unmarshal(req.body, &subs); err != nil {
+		return nil, newAPIError(http.StatusBadRequest, fmt.Sprintf("Invalid subscriptions: %v", err))
+	}
+	subReq := &ethpb.CommitteeSubnetsSubscribeRequest{
+		Slots:        make([]uint64, len(subs)),
+		CommitteeIds: make([]uint64, len(subs)),
+		IsAggregator: make([]bool, len(subs)),
+	}
+	for i, sub := range subs {
+		slot, err := uint64Param("slot", sub.Slot)
+		if err != nil {
+			return nil, err
+		}
+		committeeIndex, err := uint64Param("committee_index", sub.CommitteeIndex)
+		if err != nil {
+			return nil, err
+		}
+		subReq.Slots[i] = slot
+		subReq.CommitteeIds[i] = committeeIndex
+		subReq.IsAggregator[i] = sub.IsAggregator
+	}
+	if _, err := s.ValidatorServer.SubscribeCommitteeSubnets(ctx, subReq); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
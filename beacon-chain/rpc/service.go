@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"math/rand"
 	"net"
+	"net/http"
 	"os"
+	"sync"
 
 	middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
@@ -26,6 +28,7 @@ import (
 	"github.com/prysmaticlabs/prysm/beacon-chain/operations/voluntaryexits"
 	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
 	"github.com/prysmaticlabs/prysm/beacon-chain/powchain"
+	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/apiv1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/beacon"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/debug"
 	"github.com/prysmaticlabs/prysm/beacon-chain/rpc/node"
@@ -61,6 +64,7 @@ type Service struct {
 	headFetcher             blockchain.HeadFetcher
 	forkFetcher             blockchain.ForkFetcher
 	finalizationFetcher     blockchain.FinalizationFetcher
+	canonicalFetcher        blockchain.CanonicalFetcher
 	participationFetcher    blockchain.ParticipationFetcher
 	genesisTimeFetcher      blockchain.TimeFetcher
 	genesisFetcher          blockchain.GenesisFetcher
@@ -98,6 +102,8 @@ type Service struct {
 	slasherClient           slashpb.SlasherClient
 	stateGen                *stategen.State
 	connectedRPCClients     map[net.Addr]bool
+	apiHandler              http.Handler
+	apiHandlerLock          sync.RWMutex
 }
 
 // Config options for the beacon node RPC server.
@@ -110,6 +116,7 @@ type Config struct {
 	HeadFetcher             blockchain.HeadFetcher
 	ForkFetcher             blockchain.ForkFetcher
 	FinalizationFetcher     blockchain.FinalizationFetcher
+	CanonicalFetcher        blockchain.CanonicalFetcher
 	ParticipationFetcher    blockchain.ParticipationFetcher
 	AttestationReceiver     blockchain.AttestationReceiver
 	BlockReceiver           blockchain.BlockReceiver
@@ -147,6 +154,7 @@ func NewService(ctx context.Context, cfg *Config) *Service {
 		headFetcher:             cfg.HeadFetcher,
 		forkFetcher:             cfg.ForkFetcher,
 		finalizationFetcher:     cfg.FinalizationFetcher,
+		canonicalFetcher:        cfg.CanonicalFetcher,
 		participationFetcher:    cfg.ParticipationFetcher,
 		genesisTimeFetcher:      cfg.GenesisTimeFetcher,
 		genesisFetcher:          cfg.GenesisFetcher,
@@ -297,6 +305,29 @@ func (s *Service) Start() {
 		pbrpc.RegisterDebugServer(s.grpcServer, debugServer)
 	}
 	ethpb.RegisterBeaconNodeValidatorServer(s.grpcServer, validatorServer)
+	apiHandler := (&apiv1.Server{
+		Ctx:                  s.ctx,
+		BeaconServer:         beaconChainServer,
+		NodeServer:           nodeServer,
+		ValidatorServer:      validatorServer,
+		BeaconDB:             s.beaconDB,
+		HeadFetcher:          s.headFetcher,
+		FinalizationFetcher:  s.finalizationFetcher,
+		CanonicalFetcher:     s.canonicalFetcher,
+		GenesisTimeFetcher:   s.genesisTimeFetcher,
+		GenesisFetcher:       s.genesisFetcher,
+		StateGen:             s.stateGen,
+		SyncChecker:          s.syncService,
+		AttestationsPool:     s.attestationsPool,
+		SlashingsPool:        s.slashingsPool,
+		ExitPool:             s.exitPool,
+		StateNotifier:        s.stateNotifier,
+		OperationNotifier:    s.operationNotifier,
+		EnableDebugEndpoints: s.enableDebugRPCEndpoints,
+	}).Handler()
+	s.apiHandlerLock.Lock()
+	s.apiHandler = apiHandler
+	s.apiHandlerLock.Unlock()
 
 	// Register reflection service on gRPC server.
 	reflection.Register(s.grpcServer)
@@ -348,6 +379,21 @@ func (s *Service) startSlasherClient() {
 	s.slasherClient = slashpb.NewSlasherClient(s.slasherConn)
 }
 
+// APIHandler returns an http.Handler serving the standard /eth/v1 REST API, backed by
+// the same servers as the gRPC API. Requests fail with 503 until the service is started.
+func (s *Service) APIHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.apiHandlerLock.RLock()
+		apiHandler := s.apiHandler
+		s.apiHandlerLock.RUnlock()
+		if apiHandler == nil {
+			http.Error(w, "RPC service is not started", http.StatusServiceUnavailable)
+			return
+		}
+		apiHandler.ServeHTTP(w, r)
+	})
+}
+
 // Stop the service.
 func (s *Service) Stop() error {
 	s.cancel()
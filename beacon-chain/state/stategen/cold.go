@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 
 	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/go-ssz"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/sirupsen/logrus"
@@ -33,9 +34,141 @@ func (s *State) saveColdState(ctx context.Context, blockRoot [32]byte, state *st
 		"slot":      state.Slot(),
 		"blockRoot": hex.EncodeToString(bytesutil.Trunc(blockRoot[:]))}).Info("Saved full state on archived point")
 
+	if archivedIndex > 0 {
+		s.saveArchivedStateDiff(ctx, archivedIndex-1)
+	}
+	return nil
+}
+
+// This replaces the full state of the archived point with its diff from the state of the previous
+// archived point, unless the archived point is due for a full snapshot. It is called once the
+// next archived point is saved, so that the state of the last archived point is always stored in
+// full. Failures are only logged, as the full state is kept.
+func (s *State) saveArchivedStateDiff(ctx context.Context, index uint64) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.saveArchivedStateDiff")
+	defer span.End()
+
+	if s.archivedSnapshotInterval <= 1 || index%s.archivedSnapshotInterval == 0 {
+		return
+	}
+	if !s.beaconDB.HasArchivedPoint(ctx, index) || !s.beaconDB.HasArchivedPoint(ctx, index-1) {
+		return
+	}
+	root := s.beaconDB.ArchivedPointRoot(ctx, index)
+	baseRoot := s.beaconDB.ArchivedPointRoot(ctx, index-1)
+	if root == baseRoot {
+		return
+	}
+	if err := s.saveStateDiff(ctx, root, baseRoot); err != nil {
+		log.WithError(err).WithField("archiveIndex", index).Warn("Could not save archived state as diff")
+	}
+}
+
+// This replaces the full state stored under the root with its diff from the state stored under
+// the base root.
+func (s *State) saveStateDiff(ctx context.Context, root [32]byte, baseRoot [32]byte) error {
+	st, err := s.beaconDB.State(ctx, root)
+	if err != nil {
+		return err
+	}
+	// The state is already stored as a diff.
+	if st == nil {
+		return nil
+	}
+	base, err := s.storedState(ctx, baseRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not load base state")
+	}
+	if base == nil {
+		return errUnknownState
+	}
+	diff, err := computeStateDiff(base.InnerStateUnsafe(), st.InnerStateUnsafe(), baseRoot)
+	if err != nil {
+		return err
+	}
+	enc, err := ssz.Marshal(diff)
+	if err != nil {
+		return errors.Wrap(err, "could not encode state diff")
+	}
+	if err := s.beaconDB.SaveStateDiff(ctx, root, enc); err != nil {
+		return err
+	}
+	log.WithFields(logrus.Fields{
+		"slot":      st.Slot(),
+		"blockRoot": hex.EncodeToString(bytesutil.Trunc(root[:])),
+		"size":      len(enc),
+	}).Debug("Saved archived state as diff")
 	return nil
 }
 
+// StoredState returns the state stored in the DB under the block root, rebuilding it from the
+// last full state if it is stored as a diff. Unlike StateByRoot, no blocks are replayed, so nil
+// is returned if no state is stored under the block root.
+func (s *State) StoredState(ctx context.Context, blockRoot [32]byte) (*state.BeaconState, error) {
+	return s.storedState(ctx, blockRoot)
+}
+
+func (s *State) storedState(ctx context.Context, blockRoot [32]byte) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.storedState")
+	defer span.End()
+
+	var diffs []*stateDiff
+	root := blockRoot
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		st, err := s.beaconDB.State(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if st != nil {
+			if len(diffs) == 0 {
+				return st, nil
+			}
+			return applyStateDiffs(st, diffs)
+		}
+		enc, err := s.beaconDB.StateDiff(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if enc == nil {
+			if len(diffs) == 0 {
+				return nil, nil
+			}
+			return nil, errors.Wrapf(errUnknownState, "missing base state %#x of state diff", root)
+		}
+		diff := &stateDiff{}
+		if err := ssz.Unmarshal(enc, diff); err != nil {
+			return nil, errors.Wrapf(err, "could not decode state diff of %#x", root)
+		}
+		diffs = append(diffs, diff)
+		root = bytesutil.ToBytes32(diff.BaseRoot)
+	}
+}
+
+// This applies the diffs, ordered from the last to the first, on the full state.
+func applyStateDiffs(base *state.BeaconState, diffs []*stateDiff) (*state.BeaconState, error) {
+	st := base.CloneInnerState()
+	for i := len(diffs) - 1; i >= 0; i-- {
+		var err error
+		st, err = applyStateDiff(st, diffs[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return state.InitializeFromProtoUnsafe(st)
+}
+
+// This returns true if a full state or a state diff is stored under the block root.
+func (s *State) hasStoredState(ctx context.Context, blockRoot [32]byte) (bool, error) {
+	if s.beaconDB.HasState(ctx, blockRoot) {
+		return true, nil
+	}
+	diff, err := s.beaconDB.StateDiff(ctx, blockRoot)
+	return diff != nil, err
+}
+
 // This loads the cold state by block root.
 func (s *State) loadColdStateByRoot(ctx context.Context, blockRoot [32]byte) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "stateGen.loadColdStateByRoot")
@@ -76,5 +209,19 @@ func (s *State) loadColdStateBySlot(ctx context.Context, slot uint64) (*state.Be
 		}
 	}
 
+	// Start from a reconstructed state in between archived points, if there is one.
+	s.reconstructionLock.RLock()
+	reconstructionEnabled := s.reconstructionInterval > 0
+	s.reconstructionLock.RUnlock()
+	if reconstructionEnabled {
+		reconstructedState, err := s.reconstructedState(ctx, slot, archivedState.Slot())
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get reconstructed state")
+		}
+		if reconstructedState != nil {
+			archivedState = reconstructedState
+		}
+	}
+
 	return s.processStateUpTo(ctx, archivedState, slot)
 }
@@ -5,9 +5,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gogo/protobuf/proto"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
 	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
 	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
 	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
 	"github.com/prysmaticlabs/prysm/shared/testutil"
@@ -139,3 +141,47 @@ func TestLoadColdStateBySlot_CanGet(t *testing.T) {
 		t.Error("Did not correctly save state")
 	}
 }
+
+func TestSaveColdState_SavesPreviousArchivedStateAsDiff(t *testing.T) {
+	ctx := context.Background()
+	db, _ := testDB.SetupDB(t)
+
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = 1
+	service.SetArchivedSnapshotInterval(4)
+	beaconState, _ := testutil.DeterministicGenesisState(t, 32)
+
+	roots := [][32]byte{{'a'}, {'b'}, {'c'}, {'d'}}
+	states := make([]*state.BeaconState, len(roots))
+	for i, r := range roots {
+		st := beaconState.Copy()
+		if err := st.SetSlot(uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.UpdateBalancesAtIndex(0, uint64(i)); err != nil {
+			t.Fatal(err)
+		}
+		states[i] = st
+		if err := service.saveColdState(ctx, r, st); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The genesis archived point is a snapshot, the last archived point has no successor yet.
+	for i, r := range roots {
+		wantFull := i == 0 || i == len(roots)-1
+		if db.HasState(ctx, r) != wantFull {
+			t.Errorf("Expected archived state %d stored in full: %v", i, wantFull)
+		}
+		st, err := service.archivedState(ctx, uint64(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st == nil {
+			t.Fatalf("Expected archived state %d", i)
+		}
+		if !proto.Equal(st.InnerStateUnsafe(), states[i].InnerStateUnsafe()) {
+			t.Errorf("Expected archived state %d to be rebuilt from its diff", i)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package stategen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+)
+
+// stateDiff holds a state as its changes from the state of the previous archived point,
+// the base state. Validators are stored when they changed or were appended, balances as
+// the difference to the balance in the base state, and randao mixes, block roots, state
+// roots and slashings as sparse updates of the base state vectors. The other fields of the
+// state are stored in full. It is encoded with SSZ.
+type stateDiff struct {
+	// BaseRoot is the block root the base state is stored under.
+	BaseRoot                    []byte `ssz-size:"32"`
+	GenesisTime                 uint64
+	GenesisValidatorsRoot       []byte `ssz-size:"32"`
+	Slot                        uint64
+	Fork                        *pb.Fork
+	LatestBlockHeader           *ethpb.BeaconBlockHeader
+	HistoricalRoots             [][]byte `ssz-size:"?,32" ssz-max:"16777216"`
+	Eth1Data                    *ethpb.Eth1Data
+	Eth1DataVotes               []*ethpb.Eth1Data `ssz-max:"1024"`
+	Eth1DepositIndex            uint64
+	PreviousEpochAttestations   []*pb.PendingAttestation `ssz-max:"4096"`
+	CurrentEpochAttestations    []*pb.PendingAttestation `ssz-max:"4096"`
+	JustificationBits           []byte                   `ssz-size:"1"`
+	PreviousJustifiedCheckpoint *ethpb.Checkpoint
+	CurrentJustifiedCheckpoint  *ethpb.Checkpoint
+	FinalizedCheckpoint         *ethpb.Checkpoint
+	ValidatorCount              uint64
+	ValidatorIndices            []uint64           `ssz-max:"1099511627776"`
+	Validators                  []*ethpb.Validator `ssz-max:"1099511627776"`
+	// BalanceDeltas holds the differences of the balances to the balances of the base state,
+	// as varints in validator index order. Appended validators have a base balance of 0.
+	BalanceDeltas    []byte   `ssz-max:"10995116277760"`
+	RandaoMixIndices []uint64 `ssz-max:"65536"`
+	RandaoMixes      [][]byte `ssz-size:"?,32" ssz-max:"65536"`
+	BlockRootIndices []uint64 `ssz-max:"8192"`
+	BlockRoots       [][]byte `ssz-size:"?,32" ssz-max:"8192"`
+	StateRootIndices []uint64 `ssz-max:"8192"`
+	StateRoots       [][]byte `ssz-size:"?,32" ssz-max:"8192"`
+	SlashingIndices  []uint64 `ssz-max:"8192"`
+	Slashings        []uint64 `ssz-max:"8192"`
+}
+
+// computeStateDiff returns the diff of the state from the base state stored under the base root.
+func computeStateDiff(base *pb.BeaconState, st *pb.BeaconState, baseRoot [32]byte) (*stateDiff, error) {
+	if len(st.Validators) < len(base.Validators) || len(st.Balances) != len(st.Validators) {
+		return nil, fmt.Errorf("state with %d validators does not build on base state with %d validators",
+			len(st.Validators), len(base.Validators))
+	}
+	diff := &stateDiff{
+		BaseRoot:                    baseRoot[:],
+		GenesisTime:                 st.GenesisTime,
+		GenesisValidatorsRoot:       st.GenesisValidatorsRoot,
+		Slot:                        st.Slot,
+		Fork:                        st.Fork,
+		LatestBlockHeader:           st.LatestBlockHeader,
+		HistoricalRoots:             st.HistoricalRoots,
+		Eth1Data:                    st.Eth1Data,
+		Eth1DataVotes:               st.Eth1DataVotes,
+		Eth1DepositIndex:            st.Eth1DepositIndex,
+		PreviousEpochAttestations:   st.PreviousEpochAttestations,
+		CurrentEpochAttestations:    st.CurrentEpochAttestations,
+		JustificationBits:           st.JustificationBits,
+		PreviousJustifiedCheckpoint: st.PreviousJustifiedCheckpoint,
+		CurrentJustifiedCheckpoint:  st.CurrentJustifiedCheckpoint,
+		FinalizedCheckpoint:         st.FinalizedCheckpoint,
+		ValidatorCount:              uint64(len(st.Validators)),
+	}
+
+	for i, v := range st.Validators {
+		if i < len(base.Validators) && proto.Equal(v, base.Validators[i]) {
+			continue
+		}
+		diff.ValidatorIndices = append(diff.ValidatorIndices, uint64(i))
+		diff.Validators = append(diff.Validators, v)
+	}
+	buf := make([]byte, binary.MaxVarintLen64)
+	diff.BalanceDeltas = make([]byte, 0, len(st.Balances))
+	for i, balance := range st.Balances {
+		var baseBalance uint64
+		if i < len(base.Balances) {
+			baseBalance = base.Balances[i]
+		}
+		n := binary.PutVarint(buf, int64(balance-baseBalance))
+		diff.BalanceDeltas = append(diff.BalanceDeltas, buf[:n]...)
+	}
+
+	var err error
+	if diff.RandaoMixIndices, diff.RandaoMixes, err = sparseRoots(base.RandaoMixes, st.RandaoMixes); err != nil {
+		return nil, err
+	}
+	if diff.BlockRootIndices, diff.BlockRoots, err = sparseRoots(base.BlockRoots, st.BlockRoots); err != nil {
+		return nil, err
+	}
+	if diff.StateRootIndices, diff.StateRoots, err = sparseRoots(base.StateRoots, st.StateRoots); err != nil {
+		return nil, err
+	}
+	if len(base.Slashings) != len(st.Slashings) {
+		return nil, fmt.Errorf("slashings length %d does not match base length %d", len(st.Slashings), len(base.Slashings))
+	}
+	for i, slashing := range st.Slashings {
+		if slashing != base.Slashings[i] {
+			diff.SlashingIndices = append(diff.SlashingIndices, uint64(i))
+			diff.Slashings = append(diff.Slashings, slashing)
+		}
+	}
+	return diff, nil
+}
+
+// sparseRoots returns the indices and values of the roots which differ from the base roots.
+func sparseRoots(base [][]byte, roots [][]byte) ([]uint64, [][]byte, error) {
+	if len(base) != len(roots) {
+		return nil, nil, fmt.Errorf("vector length %d does not match base length %d", len(roots), len(base))
+	}
+	var indices []uint64
+	var changed [][]byte
+	for i, r := range roots {
+		if !bytes.Equal(r, base[i]) {
+			indices = append(indices, uint64(i))
+			changed = append(changed, r)
+		}
+	}
+	return indices, changed, nil
+}
+
+// applyStateDiff returns the state of the diff, built on the base state. The base state is
+// modified.
+func applyStateDiff(base *pb.BeaconState, diff *stateDiff) (*pb.BeaconState, error) {
+	st := base
+	st.GenesisTime = diff.GenesisTime
+	st.GenesisValidatorsRoot = diff.GenesisValidatorsRoot
+	st.Slot = diff.Slot
+	st.Fork = diff.Fork
+	st.LatestBlockHeader = diff.LatestBlockHeader
+	st.HistoricalRoots = diff.HistoricalRoots
+	st.Eth1Data = diff.Eth1Data
+	st.Eth1DataVotes = diff.Eth1DataVotes
+	st.Eth1DepositIndex = diff.Eth1DepositIndex
+	st.PreviousEpochAttestations = diff.PreviousEpochAttestations
+	st.CurrentEpochAttestations = diff.CurrentEpochAttestations
+	st.JustificationBits = diff.JustificationBits
+	st.PreviousJustifiedCheckpoint = diff.PreviousJustifiedCheckpoint
+	st.CurrentJustifiedCheckpoint = diff.CurrentJustifiedCheckpoint
+	st.FinalizedCheckpoint = diff.FinalizedCheckpoint
+
+	count := diff.ValidatorCount
+	if count < uint64(len(st.Validators)) || len(diff.ValidatorIndices) != len(diff.Validators) {
+		return nil, fmt.Errorf("malformed validators of state diff at slot %d", diff.Slot)
+	}
+	if count > uint64(len(st.Validators)) {
+		st.Validators = append(st.Validators, make([]*ethpb.Validator, count-uint64(len(st.Validators)))...)
+	}
+	for i, idx := range diff.ValidatorIndices {
+		if idx >= count {
+			return nil, fmt.Errorf("validator index %d of state diff out of range", idx)
+		}
+		st.Validators[idx] = diff.Validators[i]
+	}
+	balances := make([]uint64, count)
+	r := bytes.NewReader(diff.BalanceDeltas)
+	for i := range balances {
+		delta, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("malformed balances of state diff at slot %d", diff.Slot)
+		}
+		if i < len(st.Balances) {
+			balances[i] = st.Balances[i]
+		}
+		balances[i] += uint64(delta)
+	}
+	st.Balances = balances
+	for _, v := range st.Validators {
+		if v == nil {
+			return nil, fmt.Errorf("missing appended validator of state diff at slot %d", diff.Slot)
+		}
+	}
+
+	if err := applySparseRoots(st.RandaoMixes, diff.RandaoMixIndices, diff.RandaoMixes); err != nil {
+		return nil, err
+	}
+	if err := applySparseRoots(st.BlockRoots, diff.BlockRootIndices, diff.BlockRoots); err != nil {
+		return nil, err
+	}
+	if err := applySparseRoots(st.StateRoots, diff.StateRootIndices, diff.StateRoots); err != nil {
+		return nil, err
+	}
+	if len(diff.SlashingIndices) != len(diff.Slashings) {
+		return nil, fmt.Errorf("malformed slashings of state diff at slot %d", diff.Slot)
+	}
+	for i, idx := range diff.SlashingIndices {
+		if idx >= uint64(len(st.Slashings)) {
+			return nil, fmt.Errorf("slashing index %d of state diff out of range", idx)
+		}
+		st.Slashings[idx] = diff.Slashings[i]
+	}
+	return st, nil
+}
+
+// applySparseRoots sets the roots at the indices.
+func applySparseRoots(roots [][]byte, indices []uint64, changed [][]byte) error {
+	if len(indices) != len(changed) {
+		return fmt.Errorf("%d indices do not match %d roots", len(indices), len(changed))
+	}
+	for i, idx := range indices {
+		if idx >= uint64(len(roots)) {
+			return fmt.Errorf("root index %d out of range", idx)
+		}
+		roots[idx] = changed[i]
+	}
+	return nil
+}
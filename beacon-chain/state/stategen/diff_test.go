@@ -0,0 +1,79 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestStateDiff_RoundTrip(t *testing.T) {
+	genesis, _ := testutil.DeterministicGenesisState(t, 32)
+	base := genesis.CloneInnerState()
+	st := genesis.CloneInnerState()
+	st.Slot = 100
+	st.Validators[3].Slashed = true
+	st.Validators = append(st.Validators, &ethpb.Validator{
+		PublicKey:             make([]byte, 48),
+		WithdrawalCredentials: make([]byte, 32),
+		EffectiveBalance:      1,
+	})
+	st.Balances[0] += 10
+	st.Balances[1] -= 10
+	st.Balances = append(st.Balances, 1)
+	st.RandaoMixes[5] = []byte{'r', 31: 0}
+	st.BlockRoots[7] = []byte{'b', 31: 0}
+	st.StateRoots[9] = []byte{'s', 31: 0}
+	st.Slashings[2] = 5
+	st.HistoricalRoots = append(st.HistoricalRoots, []byte{'h', 31: 0})
+
+	diff, err := computeStateDiff(base, st, [32]byte{'a'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Validators) != 2 || len(diff.RandaoMixes) != 1 || len(diff.BlockRoots) != 1 || len(diff.Slashings) != 1 {
+		t.Errorf("Expected only the changes to be stored, received %d validators, %d randao mixes, %d block roots, %d slashings",
+			len(diff.Validators), len(diff.RandaoMixes), len(diff.BlockRoots), len(diff.Slashings))
+	}
+	enc, err := ssz.Marshal(diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &stateDiff{}
+	if err := ssz.Unmarshal(enc, decoded); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := applyStateDiff(proto.Clone(base).(*pb.BeaconState), decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted, err := stateTrie.InitializeFromProto(st)
+	if err != nil {
+		t.Fatal(err)
+	}
+	received, err := stateTrie.InitializeFromProto(applied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantedRoot, err := wanted.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	receivedRoot, err := received.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantedRoot != receivedRoot {
+		t.Error("Expected the state rebuilt from the diff to match the state")
+	}
+
+	if _, err := computeStateDiff(st, base, [32]byte{'a'}); err == nil {
+		t.Error("Expected a diff from a state with more validators to fail")
+	}
+}
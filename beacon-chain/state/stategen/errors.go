@@ -7,3 +7,5 @@ var errUnknownArchivedState = errors.New("unknown archived state")
 var errUnknownBoundaryState = errors.New("unknown boundary state")
 var errUnknownState = errors.New("unknown state")
 var errUnknownBlock = errors.New("unknown block")
+var errReconstructionDisabled = errors.New("historical state reconstruction is disabled")
+var errReconstructionRunning = errors.New("historical state reconstruction is already running")
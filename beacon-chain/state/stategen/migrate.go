@@ -80,6 +80,9 @@ func (s *State) MigrateToCold(ctx context.Context, finalizedSlot uint64, finaliz
 				"archiveIndex": archivedPointIndex,
 				"root":         hex.EncodeToString(bytesutil.Trunc(r[:])),
 			}).Info("Saved archived point during state migration")
+			if archivedPointIndex > 0 {
+				s.saveArchivedStateDiff(ctx, archivedPointIndex-1)
+			}
 		} else {
 			// Do not delete the current finalized state in case user wants to
 			// switch back to old state service, deleting the recent finalized state
@@ -146,6 +149,9 @@ func (s *State) recoverArchivedPoint(ctx context.Context, currentArchivedPoint u
 		"archiveIndex": missingIndex,
 		"root":         hex.EncodeToString(bytesutil.Trunc(missingRoot[:])),
 	}).Info("Saved recovered archived point during state migration")
+	if missingIndex > 0 {
+		s.saveArchivedStateDiff(ctx, missingIndex-1)
+	}
 
 	return missingIndex, nil
 }
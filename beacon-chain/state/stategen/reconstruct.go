@@ -0,0 +1,208 @@
+package stategen
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+// ReconstructionStatus reports the progress of the reconstruction of historical states.
+type ReconstructionStatus struct {
+	StartSlot uint64
+	EndSlot   uint64
+	// Slot is the slot up to which states have been reconstructed.
+	Slot        uint64
+	StatesSaved uint64
+	Running     bool
+	// Err is the error which stopped the last reconstruction, if any.
+	Err error
+}
+
+// VerifyReconstructionInterval checks that states can be reconstructed at the interval: it
+// must be a multiple of the number of slots per epoch, and divide the slots per archived point.
+func VerifyReconstructionInterval(interval uint64, slotsPerArchivedPoint uint64) error {
+	if interval == 0 || interval%params.BeaconConfig().SlotsPerEpoch != 0 {
+		return fmt.Errorf("historical state interval %d is not a multiple of %d slots per epoch",
+			interval, params.BeaconConfig().SlotsPerEpoch)
+	}
+	if interval >= slotsPerArchivedPoint || slotsPerArchivedPoint%interval != 0 {
+		return fmt.Errorf("historical state interval %d does not divide %d slots per archived point",
+			interval, slotsPerArchivedPoint)
+	}
+	return nil
+}
+
+// SetReconstructionInterval enables the reconstruction of historical states every interval
+// slots, in between the archived points. Cold states are then loaded from the closest
+// reconstructed state.
+func (s *State) SetReconstructionInterval(interval uint64) error {
+	if err := VerifyReconstructionInterval(interval, s.slotsPerArchivedPoint); err != nil {
+		return err
+	}
+	s.reconstructionLock.Lock()
+	defer s.reconstructionLock.Unlock()
+	s.reconstructionInterval = interval
+	return nil
+}
+
+// ReconstructionStatus returns the progress of the current, or last, reconstruction of
+// historical states.
+func (s *State) ReconstructionStatus() ReconstructionStatus {
+	s.reconstructionLock.RLock()
+	defer s.reconstructionLock.RUnlock()
+	return s.reconstruction
+}
+
+// ReconstructStates starts reconstructing the states of the finalized slots from start up to,
+// but not including, end in the background. A state is saved every reconstruction interval
+// slots, under the root of the last block at or before the slot. The reconstruction runs
+// until it completes or the context is cancelled, and its progress is reported by
+// ReconstructionStatus.
+func (s *State) ReconstructStates(ctx context.Context, startSlot uint64, endSlot uint64) error {
+	if endSlot > s.splitInfo.slot {
+		endSlot = s.splitInfo.slot
+	}
+	if startSlot >= endSlot {
+		return fmt.Errorf("no finalized slots to reconstruct from slot %d to %d, finalized slot %d",
+			startSlot, endSlot, s.splitInfo.slot)
+	}
+
+	s.reconstructionLock.Lock()
+	defer s.reconstructionLock.Unlock()
+	if s.reconstructionInterval == 0 {
+		return errReconstructionDisabled
+	}
+	if s.reconstruction.Running {
+		return errReconstructionRunning
+	}
+	s.reconstruction = ReconstructionStatus{
+		StartSlot: startSlot,
+		EndSlot:   endSlot,
+		Slot:      startSlot,
+		Running:   true,
+	}
+	go s.reconstructStates(ctx, startSlot, endSlot, s.reconstructionInterval)
+	return nil
+}
+
+func (s *State) reconstructStates(ctx context.Context, startSlot uint64, endSlot uint64, interval uint64) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.reconstructStates")
+	defer span.End()
+
+	log.WithFields(logrus.Fields{
+		"startSlot": startSlot,
+		"endSlot":   endSlot,
+		"interval":  interval,
+	}).Info("Reconstructing historical states")
+	err := s.replayAndSaveStates(ctx, startSlot, endSlot, interval)
+
+	s.reconstructionLock.Lock()
+	s.reconstruction.Running = false
+	s.reconstruction.Err = err
+	status := s.reconstruction
+	s.reconstructionLock.Unlock()
+
+	if err != nil {
+		log.WithError(err).WithField("slot", status.Slot).Error("Could not reconstruct historical states")
+		return
+	}
+	log.WithField("states", status.StatesSaved).Info("Reconstructed historical states")
+}
+
+// This replays the blocks from the start slot to the end slot, and saves the state of the last
+// block at or before every interval slot which does not have a state yet.
+func (s *State) replayAndSaveStates(ctx context.Context, startSlot uint64, endSlot uint64, interval uint64) error {
+	first := startSlot + (interval-startSlot%interval)%interval
+	if first == 0 {
+		first = interval
+	}
+
+	var st *state.BeaconState
+	var lastSlot uint64
+	for slot := first; slot < endSlot; slot += interval {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		root, blockSlot, err := s.lastSavedBlock(ctx, slot)
+		if err != nil {
+			return err
+		}
+		stored, err := s.hasStoredState(ctx, root)
+		if err != nil {
+			return err
+		}
+		saved := false
+		switch {
+		case st != nil && blockSlot <= lastSlot:
+			// No blocks since the last saved state.
+		case stored:
+			// The state of archived points is kept as is, as it may be at a later slot than its block.
+			st = nil
+		default:
+			if st == nil {
+				st, err = s.loadColdStateBySlot(ctx, blockSlot)
+			} else {
+				st, err = s.processStateUpTo(ctx, st, blockSlot)
+			}
+			if err != nil {
+				return err
+			}
+			if err := s.beaconDB.SaveState(ctx, st, root); err != nil {
+				return err
+			}
+			saved = true
+		}
+		lastSlot = blockSlot
+
+		s.reconstructionLock.Lock()
+		s.reconstruction.Slot = slot
+		if saved {
+			s.reconstruction.StatesSaved++
+		}
+		s.reconstructionLock.Unlock()
+	}
+
+	s.reconstructionLock.Lock()
+	s.reconstruction.Slot = endSlot
+	s.reconstructionLock.Unlock()
+	return nil
+}
+
+// This returns the reconstructed state closest to, and at or before, the input slot which is
+// newer than the lower bound slot. It returns nil if there is no such state.
+func (s *State) reconstructedState(ctx context.Context, slot uint64, lowerBound uint64) (*state.BeaconState, error) {
+	ctx, span := trace.StartSpan(ctx, "stateGen.reconstructedState")
+	defer span.End()
+
+	root, _, err := s.lastSavedBlock(ctx, slot)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		b, err := s.beaconDB.Block(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil || b.Block == nil || b.Block.Slot <= lowerBound {
+			return nil, nil
+		}
+		if s.beaconDB.HasState(ctx, root) {
+			st, err := s.beaconDB.State(ctx, root)
+			if err != nil {
+				return nil, err
+			}
+			if st != nil && st.Slot() <= slot {
+				return st, nil
+			}
+		}
+		root = bytesutil.ToBytes32(b.Block.ParentRoot)
+	}
+}
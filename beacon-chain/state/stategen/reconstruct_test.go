@@ -0,0 +1,150 @@
+package stategen
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/beacon-chain/cache"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/blocks"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/state"
+	testDB "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func TestVerifyReconstructionInterval(t *testing.T) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	tests := []struct {
+		interval uint64
+		valid    bool
+	}{
+		{interval: 0, valid: false},
+		{interval: slotsPerEpoch - 1, valid: false},
+		{interval: slotsPerEpoch, valid: true},
+		{interval: slotsPerEpoch * 2, valid: true},
+		{interval: slotsPerEpoch * 3, valid: false},
+		{interval: slotsPerEpoch * 4, valid: false},
+	}
+	for _, tt := range tests {
+		if err := VerifyReconstructionInterval(tt.interval, slotsPerEpoch*4); (err == nil) != tt.valid {
+			t.Errorf("Interval %d: expected valid %v, received %v", tt.interval, tt.valid, err)
+		}
+	}
+}
+
+func TestReconstructStates(t *testing.T) {
+	params.SetupTestConfigCleanup(t)
+	params.OverrideBeaconConfig(params.MinimalSpecConfig())
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+
+	ctx := context.Background()
+	db, _ := testDB.SetupDB(t)
+	service := New(db, cache.NewStateSummaryCache())
+	service.slotsPerArchivedPoint = slotsPerEpoch * 4
+
+	beaconState, privs := testutil.DeterministicGenesisState(t, 32)
+	stateRoot, err := beaconState.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := blocks.NewGenesisBlock(stateRoot[:])
+	genesisRoot, err := stateutil.BlockRoot(genesis.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlock(ctx, genesis); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveGenesisBlockRoot(ctx, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveState(ctx, beaconState, genesisRoot); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveArchivedPointRoot(ctx, genesisRoot, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Blocks up to the middle of the third epoch, the first slot of every epoch is skipped.
+	end := slotsPerEpoch*2 + slotsPerEpoch/2
+	for slot := uint64(1); slot <= end; slot++ {
+		if slot%slotsPerEpoch == 0 {
+			continue
+		}
+		blk, err := testutil.GenerateFullBlock(beaconState, privs, &testutil.BlockGenConfig{}, slot)
+		if err != nil {
+			t.Fatal(err)
+		}
+		beaconState, err = state.ExecuteStateTransition(ctx, beaconState, blk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.SaveBlock(ctx, blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	service.splitInfo.slot = end + 1
+
+	if err := service.ReconstructStates(ctx, 0, end); err != errReconstructionDisabled {
+		t.Errorf("Expected %v, received %v", errReconstructionDisabled, err)
+	}
+	if err := service.SetReconstructionInterval(slotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.ReconstructStates(ctx, end+1, end+2); err == nil {
+		t.Error("Expected reconstructing unfinalized slots to fail")
+	}
+	if err := service.ReconstructStates(ctx, 0, end); err != nil {
+		t.Fatal(err)
+	}
+	status := service.ReconstructionStatus()
+	for i := 0; status.Running && i < 100; i++ {
+		time.Sleep(100 * time.Millisecond)
+		status = service.ReconstructionStatus()
+	}
+	if status.Running || status.Err != nil {
+		t.Fatalf("Expected reconstruction to complete, received %+v", status)
+	}
+	if status.StatesSaved != 2 || status.Slot != end {
+		t.Errorf("Expected 2 states saved up to slot %d, received %+v", end, status)
+	}
+
+	// The states are saved at the last block before every epoch boundary.
+	for _, slot := range []uint64{slotsPerEpoch - 1, slotsPerEpoch*2 - 1} {
+		blks, err := db.HighestSlotBlocksBelow(ctx, slot+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root, err := stateutil.BlockRoot(blks[0].Block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !db.HasState(ctx, root) {
+			t.Errorf("Expected a reconstructed state at slot %d", slot)
+		}
+	}
+
+	st, err := service.reconstructedState(ctx, slotsPerEpoch*2+1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st == nil || st.Slot() != slotsPerEpoch*2-1 {
+		t.Fatalf("Expected the reconstructed state of slot %d, received %v", slotsPerEpoch*2-1, st)
+	}
+	loaded, err := service.loadColdStateBySlot(ctx, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loadedRoot, err := loaded.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantedRoot, err := beaconState.HashTreeRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedRoot != wantedRoot {
+		t.Error("Expected the state loaded from the reconstructed state to match the head state")
+	}
+}
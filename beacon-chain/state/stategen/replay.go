@@ -336,7 +336,7 @@ func (s *State) archivedState(ctx context.Context, slot uint64) (*state.BeaconSt
 	if err != nil {
 		return nil, err
 	}
-	return s.beaconDB.State(ctx, archivedRoot)
+	return s.storedState(ctx, archivedRoot)
 }
 
 // This recomputes a state given the block root.
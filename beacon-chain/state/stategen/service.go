@@ -26,6 +26,12 @@ type State struct {
 	hotStateCache           *cache.HotStateCache
 	splitInfo               *splitSlotAndRoot
 	stateSummaryCache       *cache.StateSummaryCache
+	reconstructionInterval  uint64
+	reconstruction          ReconstructionStatus
+	reconstructionLock      sync.RWMutex
+	// archivedSnapshotInterval is the number of archived points between the archived points
+	// whose state is stored in full, the others are stored as state diffs.
+	archivedSnapshotInterval uint64
 }
 
 // This tracks the split point. The point where slot and the block root of
@@ -47,13 +53,25 @@ func New(db db.NoHeadAccessDatabase, stateSummaryCache *cache.StateSummaryCache)
 	}
 }
 
+// SetArchivedSnapshotInterval stores the state of every interval-th archived point in full, and
+// the states of the archived points in between as diffs from the state of the previous archived
+// point. An interval of 0 or 1 stores every archived state in full.
+func (s *State) SetArchivedSnapshotInterval(interval uint64) {
+	s.archivedSnapshotInterval = interval
+}
+
+// SetHotStateCache replaces the cache of the hot states.
+func (s *State) SetHotStateCache(c *cache.HotStateCache) {
+	s.hotStateCache = c
+}
+
 // Resume resumes a new state management object from previously saved finalized check point in DB.
 func (s *State) Resume(ctx context.Context) (*state.BeaconState, error) {
 	ctx, span := trace.StartSpan(ctx, "stateGen.Resume")
 	defer span.End()
 
 	lastArchivedRoot := s.beaconDB.LastArchivedIndexRoot(ctx)
-	lastArchivedState, err := s.beaconDB.State(ctx, lastArchivedRoot)
+	lastArchivedState, err := s.storedState(ctx, lastArchivedRoot)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,187 @@
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	stateTrie "github.com/prysmaticlabs/prysm/beacon-chain/state"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	pbrpc "github.com/prysmaticlabs/prysm/proto/beacon/rpc/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+)
+
+// anchor is a trusted, finalized block and its post state which a node
+// bootstraps its database from instead of the genesis state.
+type anchor struct {
+	block *ethpb.SignedBeaconBlock
+	state *stateTrie.BeaconState
+}
+
+// anchorFromFiles reads an ssz encoded signed beacon block and beacon state from disk.
+func anchorFromFiles(statePath string, blockPath string) (*anchor, error) {
+	encState, err := ioutil.ReadFile(statePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read checkpoint state file")
+	}
+	encBlock, err := ioutil.ReadFile(blockPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read checkpoint block file")
+	}
+	return decodeAnchor(encState, encBlock)
+}
+
+// anchorFromProvider fetches the latest finalized block and its post state from a trusted
+// beacon node's debug endpoints.
+func anchorFromProvider(ctx context.Context, provider string, maxCallRecvMsgSize int) (*anchor, error) {
+	conn, err := grpc.DialContext(
+		ctx,
+		provider,
+		grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxCallRecvMsgSize)),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not dial checkpoint sync provider %s", provider)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.WithError(err).Error("Could not close connection to checkpoint sync provider")
+		}
+	}()
+
+	head, err := ethpb.NewBeaconChainClient(conn).GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch chain head from checkpoint sync provider")
+	}
+	debugClient := pbrpc.NewDebugClient(conn)
+	encBlock, err := debugClient.GetBlock(ctx, &pbrpc.BlockRequest{BlockRoot: head.FinalizedBlockRoot})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch finalized block from checkpoint sync provider")
+	}
+	encState, err := debugClient.GetBeaconState(ctx, &pbrpc.BeaconStateRequest{
+		QueryFilter: &pbrpc.BeaconStateRequest_BlockRoot{BlockRoot: head.FinalizedBlockRoot},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch finalized state from checkpoint sync provider")
+	}
+	log.WithFields(logrus.Fields{
+		"provider": provider,
+		"epoch":    head.FinalizedEpoch,
+		"root":     fmt.Sprintf("%#x", head.FinalizedBlockRoot),
+	}).Info("Fetched finalized checkpoint from provider")
+	return decodeAnchor(encState.Encoded, encBlock.Encoded)
+}
+
+func decodeAnchor(encState []byte, encBlock []byte) (*anchor, error) {
+	blk := &ethpb.SignedBeaconBlock{}
+	if err := blk.UnmarshalSSZ(encBlock); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal checkpoint block")
+	}
+	if blk.Block == nil {
+		return nil, errors.New("checkpoint block is empty")
+	}
+	protoState := &pb.BeaconState{}
+	if err := protoState.UnmarshalSSZ(encState); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal checkpoint state")
+	}
+	st, err := stateTrie.InitializeFromProtoUnsafe(protoState)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not initialize checkpoint state")
+	}
+	return &anchor{block: blk, state: st}, nil
+}
+
+// verify checks the anchor state is the post state of the anchor block, and that
+// the anchor is still within the weak subjectivity period of the chain. Beyond that
+// period, a node can no longer safely follow the chain from the anchor.
+func (a *anchor) verify(ctx context.Context) error {
+	if a.block.Block.Slot != a.state.Slot() {
+		return fmt.Errorf("checkpoint block slot %d does not match state slot %d", a.block.Block.Slot, a.state.Slot())
+	}
+	stateRoot, err := a.state.HashTreeRoot(ctx)
+	if err != nil {
+		return errors.Wrap(err, "could not hash checkpoint state")
+	}
+	if stateRoot != bytesutil.ToBytes32(a.block.Block.StateRoot) {
+		return fmt.Errorf("checkpoint block state root %#x does not match state root %#x", a.block.Block.StateRoot, stateRoot)
+	}
+	if !helpers.IsEpochStart(a.state.Slot()) {
+		log.WithField("slot", a.state.Slot()).Warn("Checkpoint state is not at an epoch boundary")
+	}
+
+	genesisTime := time.Unix(int64(a.state.GenesisTime()), 0)
+	if genesisTime.After(roughtime.Now()) {
+		return nil
+	}
+	currentEpoch := helpers.SlotToEpoch(helpers.SlotsSince(genesisTime))
+	anchorEpoch := helpers.SlotToEpoch(a.state.Slot())
+	if currentEpoch > anchorEpoch+params.BeaconConfig().WeakSubjectivityPeriod {
+		return fmt.Errorf(
+			"checkpoint epoch %d is outside of the weak subjectivity period of %d epochs, current epoch %d",
+			anchorEpoch,
+			params.BeaconConfig().WeakSubjectivityPeriod,
+			currentEpoch,
+		)
+	}
+	return nil
+}
+
+// save writes the anchor block and state to the database, marking them as the
+// origin of the node's chain data and as its justified and finalized checkpoint.
+func (a *anchor) save(ctx context.Context, beaconDB db.HeadAccessDatabase) ([32]byte, error) {
+	blockRoot, err := stateutil.BlockRoot(a.block.Block)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not get checkpoint block root")
+	}
+	if err := beaconDB.SaveBlock(ctx, a.block); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save checkpoint block")
+	}
+	if err := beaconDB.SaveStateSummary(ctx, &pb.StateSummary{
+		Slot: a.state.Slot(),
+		Root: blockRoot[:],
+	}); err != nil {
+		return [32]byte{}, err
+	}
+	if err := beaconDB.SaveState(ctx, a.state, blockRoot); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save checkpoint state")
+	}
+	if err := beaconDB.SaveOriginBlockRoot(ctx, blockRoot); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save origin block root")
+	}
+	if err := beaconDB.SaveHeadBlockRoot(ctx, blockRoot); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save head block root")
+	}
+
+	// The anchor state is the only state the node has to regenerate other states
+	// from, so it is also the archived point the state generator resumes from.
+	archivedIndex := a.state.Slot() / params.BeaconConfig().SlotsPerArchivedPoint
+	if err := beaconDB.SaveArchivedPointRoot(ctx, blockRoot, archivedIndex); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save archived point root")
+	}
+	if err := beaconDB.SaveLastArchivedIndex(ctx, archivedIndex); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save last archived index")
+	}
+
+	checkpoint := &ethpb.Checkpoint{
+		Epoch: helpers.SlotToEpoch(a.state.Slot()),
+		Root:  blockRoot[:],
+	}
+	if err := beaconDB.SaveJustifiedCheckpoint(ctx, checkpoint); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save justified checkpoint")
+	}
+	if err := beaconDB.SaveFinalizedCheckpoint(ctx, checkpoint); err != nil {
+		return [32]byte{}, errors.Wrap(err, "could not save finalized checkpoint")
+	}
+	return blockRoot, nil
+}
@@ -0,0 +1,157 @@
+package checkpoint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	dbtest "github.com/prysmaticlabs/prysm/beacon-chain/db/testing"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/roughtime"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+)
+
+func testAnchor(t *testing.T, genesisTime uint64) *anchor {
+	st, _ := testutil.DeterministicGenesisState(t, 64)
+	if err := st.SetGenesisTime(genesisTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetSlot(params.BeaconConfig().SlotsPerEpoch); err != nil {
+		t.Fatal(err)
+	}
+	stateRoot, err := st.HashTreeRoot(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk := testutil.NewBeaconBlock()
+	blk.Block.Slot = st.Slot()
+	blk.Block.StateRoot = stateRoot[:]
+	blk.Block.ParentRoot = bytesutil.PadTo([]byte{'a'}, 32)
+	return &anchor{block: blk, state: st}
+}
+
+func TestAnchor_Verify(t *testing.T) {
+	ctx := context.Background()
+	a := testAnchor(t, uint64(roughtime.Now().Unix()))
+	if err := a.verify(ctx); err != nil {
+		t.Fatalf("Could not verify anchor: %v", err)
+	}
+
+	a.block.Block.StateRoot = bytesutil.PadTo([]byte{'b'}, 32)
+	want := "does not match state root"
+	if err := a.verify(ctx); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error %q, received %v", want, err)
+	}
+
+	a = testAnchor(t, uint64(roughtime.Now().Unix()))
+	a.block.Block.Slot++
+	want = "does not match state slot"
+	if err := a.verify(ctx); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error %q, received %v", want, err)
+	}
+}
+
+func TestAnchor_Verify_OutsideWeakSubjectivityPeriod(t *testing.T) {
+	period := params.BeaconConfig().WeakSubjectivityPeriod + 2
+	secondsPerEpoch := params.BeaconConfig().SecondsPerSlot * params.BeaconConfig().SlotsPerEpoch
+	genesisTime := uint64(roughtime.Now().Unix()) - period*secondsPerEpoch
+	a := testAnchor(t, genesisTime)
+	want := "outside of the weak subjectivity period"
+	if err := a.verify(context.Background()); err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error %q, received %v", want, err)
+	}
+}
+
+func TestAnchor_Save(t *testing.T) {
+	db, _ := dbtest.SetupDB(t)
+	ctx := context.Background()
+	a := testAnchor(t, uint64(roughtime.Now().Unix()))
+
+	root, err := a.save(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRoot, err := stateutil.BlockRoot(a.block.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root != wantRoot {
+		t.Errorf("Wanted root %#x, received %#x", wantRoot, root)
+	}
+	originRoot, err := db.OriginBlockRoot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if originRoot != root {
+		t.Errorf("Wanted origin root %#x, received %#x", root, originRoot)
+	}
+	headBlock, err := db.HeadBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(headBlock, a.block) {
+		t.Errorf("Wanted head block %v, received %v", a.block, headBlock)
+	}
+	if !db.HasState(ctx, root) {
+		t.Error("Expected checkpoint state to be saved")
+	}
+	if db.LastArchivedIndexRoot(ctx) != root {
+		t.Error("Expected checkpoint to be the last archived point")
+	}
+	wantCheckpoint := &ethpb.Checkpoint{Epoch: 1, Root: root[:]}
+	finalized, err := db.FinalizedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(finalized, wantCheckpoint) {
+		t.Errorf("Wanted finalized checkpoint %v, received %v", wantCheckpoint, finalized)
+	}
+	justified, err := db.JustifiedCheckpoint(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(justified, wantCheckpoint) {
+		t.Errorf("Wanted justified checkpoint %v, received %v", wantCheckpoint, justified)
+	}
+	if !db.IsFinalizedBlock(ctx, root) {
+		t.Error("Expected checkpoint block to be finalized")
+	}
+}
+
+func TestService_LowestBlock(t *testing.T) {
+	db, _ := dbtest.SetupDB(t)
+	ctx := context.Background()
+
+	parent := testutil.NewBeaconBlock()
+	parent.Block.Slot = 5
+	parentRoot, err := stateutil.BlockRoot(parent.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	origin := testutil.NewBeaconBlock()
+	origin.Block.Slot = 10
+	origin.Block.ParentRoot = parentRoot[:]
+	originRoot, err := stateutil.BlockRoot(origin.Block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveBlocks(ctx, []*ethpb.SignedBeaconBlock{parent, origin}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Service{beaconDB: db, originRoot: originRoot}
+	lowest, lowestRoot, err := s.lowestBlock(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lowestRoot != parentRoot {
+		t.Errorf("Wanted lowest root %#x, received %#x", parentRoot, lowestRoot)
+	}
+	if !proto.Equal(lowest, parent) {
+		t.Errorf("Wanted lowest block %v, received %v", parent, lowest)
+	}
+}
@@ -0,0 +1,221 @@
+package checkpoint
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/beacon-chain/state/stateutil"
+	prysmsync "github.com/prysmaticlabs/prysm/beacon-chain/sync"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// backfillBatchSize is the number of slots requested from a peer at once.
+	backfillBatchSize = 64
+	// peerPollingInterval is the interval to wait for when no suitable peers are connected,
+	// or after a failed request.
+	peerPollingInterval = 5 * time.Second
+	// maxBackfillRestarts is the number of times backfilling starts over from the lowest
+	// block when peers did not serve blocks linked to it, before giving up until restart.
+	maxBackfillRestarts = 8
+	// maxBackfillBackoff caps the delay before backfilling starts over.
+	maxBackfillBackoff = 5 * time.Minute
+)
+
+// backfill walks the chain backwards from the lowest block in the database, requesting blocks by
+// range from peers. A block is only saved if its root matches the parent root of the lowest saved
+// block, which links every backfilled block to the trusted checkpoint. Once the genesis block is
+// reached, it is saved as such and backfilling is complete.
+func (s *Service) backfill() {
+	lowest, lowestRoot, err := s.lowestBlock(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("Could not find lowest block to backfill from")
+		return
+	}
+	log.WithField("slot", lowest.Block.Slot).Info("Backfilling blocks behind checkpoint")
+
+	// cursor is the exclusive upper slot bound of the next range to request.
+	cursor := lowest.Block.Slot
+	restarts := 0
+	for lowest.Block.Slot > 0 {
+		if s.ctx.Err() != nil {
+			return
+		}
+		if cursor == 0 {
+			// No blocks linked to the lowest block all the way down to genesis, the
+			// peers served incomplete ranges. Start over from the lowest block after
+			// backing off, as the peers are unlikely to serve different ranges right away.
+			restarts++
+			if restarts > maxBackfillRestarts {
+				log.WithField("slot", lowest.Block.Slot).Error("Peers did not serve the blocks behind the checkpoint, backfilling stopped until restart")
+				return
+			}
+			backoff := peerPollingInterval << uint(restarts-1)
+			if backoff > maxBackfillBackoff {
+				backoff = maxBackfillBackoff
+			}
+			log.WithFields(logrus.Fields{
+				"slot":    lowest.Block.Slot,
+				"attempt": restarts,
+				"backoff": backoff,
+			}).Warn("No peer served blocks linked to the lowest block, retrying")
+			if !s.wait(backoff) {
+				return
+			}
+			cursor = lowest.Block.Slot
+		}
+		pid, err := s.selectPeer()
+		if err != nil {
+			return
+		}
+		start := uint64(0)
+		if cursor > backfillBatchSize {
+			start = cursor - backfillBatchSize
+		}
+		blks, err := s.requestBlocks(s.ctx, &pb.BeaconBlocksByRangeRequest{
+			StartSlot: start,
+			Count:     cursor - start,
+			Step:      1,
+		}, pid)
+		if err != nil {
+			log.WithError(err).WithField("peer", pid).Debug("Could not request blocks")
+			if !s.wait(peerPollingInterval) {
+				return
+			}
+			continue
+		}
+
+		linked := make([]*ethpb.SignedBeaconBlock, 0, len(blks))
+		parentRoot := bytesutil.ToBytes32(lowest.Block.ParentRoot)
+		for i := len(blks) - 1; i >= 0; i-- {
+			r, err := stateutil.BlockRoot(blks[i].Block)
+			if err != nil {
+				log.WithError(err).Error("Could not get block root")
+				continue
+			}
+			if r != parentRoot {
+				continue
+			}
+			linked = append(linked, blks[i])
+			lowest, lowestRoot = blks[i], r
+			parentRoot = bytesutil.ToBytes32(blks[i].Block.ParentRoot)
+		}
+		// If nothing linked, the whole range is assumed to be skipped slots.
+		cursor = start
+		if len(linked) == 0 {
+			continue
+		}
+		if err := s.beaconDB.SaveBlocks(s.ctx, linked); err != nil {
+			log.WithError(err).Error("Could not save backfilled blocks")
+			return
+		}
+		cursor = lowest.Block.Slot
+		restarts = 0
+		log.WithFields(logrus.Fields{
+			"slot":  lowest.Block.Slot,
+			"epoch": helpers.SlotToEpoch(lowest.Block.Slot),
+		}).Debug("Backfilled blocks")
+	}
+
+	if err := s.beaconDB.SaveGenesisBlockRoot(s.ctx, lowestRoot); err != nil {
+		log.WithError(err).Error("Could not save genesis block root")
+		return
+	}
+	s.setBackfilled()
+	log.Info("Backfilled all blocks down to genesis")
+}
+
+// wait for the duration, returning false if the service is stopped in the meantime.
+func (s *Service) wait(d time.Duration) bool {
+	select {
+	case <-s.ctx.Done():
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// lowestBlock returns the lowest block linked to the origin block in the database, as
+// backfilling may have been interrupted by a restart.
+func (s *Service) lowestBlock(ctx context.Context) (*ethpb.SignedBeaconBlock, [32]byte, error) {
+	root := s.originRoot
+	blk, err := s.beaconDB.Block(ctx, root)
+	if err != nil {
+		return nil, [32]byte{}, err
+	}
+	if blk == nil {
+		return nil, [32]byte{}, errors.New("origin block is missing from the database")
+	}
+	for blk.Block.Slot > 0 {
+		parentRoot := bytesutil.ToBytes32(blk.Block.ParentRoot)
+		parent, err := s.beaconDB.Block(ctx, parentRoot)
+		if err != nil {
+			return nil, [32]byte{}, err
+		}
+		if parent == nil {
+			break
+		}
+		blk, root = parent, parentRoot
+	}
+	return blk, root, nil
+}
+
+// selectPeer blocks until a peer which has finalized the origin block is connected and returns
+// a random one of them.
+func (s *Service) selectPeer() (peer.ID, error) {
+	originBlock, err := s.beaconDB.Block(s.ctx, s.originRoot)
+	if err != nil {
+		return "", err
+	}
+	originEpoch := helpers.SlotToEpoch(originBlock.Block.Slot)
+	for {
+		_, _, pids := s.p2p.Peers().BestFinalized(params.BeaconConfig().MaxPeersToSync, originEpoch)
+		if len(pids) > 0 {
+			return pids[rand.Intn(len(pids))], nil
+		}
+		select {
+		case <-s.ctx.Done():
+			return "", s.ctx.Err()
+		case <-time.After(peerPollingInterval):
+		}
+	}
+}
+
+func (s *Service) requestBlocks(
+	ctx context.Context,
+	req *pb.BeaconBlocksByRangeRequest,
+	pid peer.ID,
+) ([]*ethpb.SignedBeaconBlock, error) {
+	stream, err := s.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := stream.Reset(); err != nil {
+			log.WithError(err).Errorf("Failed to close stream with protocol %s", stream.Protocol())
+		}
+	}()
+
+	resp := make([]*ethpb.SignedBeaconBlock, 0, req.Count)
+	for i := uint64(0); i < req.Count; i++ {
+		blk, err := prysmsync.ReadChunkedBlock(stream, s.p2p)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, blk)
+	}
+	return resp, nil
+}
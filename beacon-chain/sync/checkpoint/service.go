@@ -0,0 +1,136 @@
+// Package checkpoint allows a beacon node to start from a trusted, finalized
+// checkpoint state and block instead of syncing from genesis, backfilling the
+// historical blocks behind that checkpoint in the background.
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
+	"github.com/prysmaticlabs/prysm/beacon-chain/db"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	"github.com/prysmaticlabs/prysm/shared"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var _ = shared.Service(&Service{})
+
+// Config options for the checkpoint sync service.
+type Config struct {
+	BeaconDB           db.HeadAccessDatabase
+	P2P                p2p.P2P
+	StatePath          string
+	BlockPath          string
+	Provider           string
+	MaxCallRecvMsgSize int
+}
+
+// Service bootstraps an empty beacon node database from a weak subjectivity
+// checkpoint, and backfills the blocks behind it once the node is running.
+type Service struct {
+	ctx        context.Context
+	cancel     context.CancelFunc
+	beaconDB   db.HeadAccessDatabase
+	p2p        p2p.P2P
+	originRoot [32]byte
+	lock       sync.RWMutex
+	backfilled bool
+}
+
+// NewCheckpointSyncService initializes the beacon node database from the configured checkpoint
+// state and block if the database is empty. This has to happen when the service is created, as
+// other services expect the chain data to be present in the database once they are started.
+func NewCheckpointSyncService(ctx context.Context, cfg *Config) (*Service, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Service{
+		ctx:      ctx,
+		cancel:   cancel,
+		beaconDB: cfg.BeaconDB,
+		p2p:      cfg.P2P,
+	}
+
+	headBlock, err := s.beaconDB.HeadBlock(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get head block")
+	}
+	if headBlock != nil {
+		if cfg.StatePath != "" || cfg.Provider != "" {
+			log.Info("Chain data already exists in DB, ignoring checkpoint sync options")
+		}
+	} else {
+		var a *anchor
+		if cfg.StatePath != "" {
+			a, err = anchorFromFiles(cfg.StatePath, cfg.BlockPath)
+		} else {
+			a, err = anchorFromProvider(ctx, cfg.Provider, cfg.MaxCallRecvMsgSize)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := a.verify(ctx); err != nil {
+			return nil, errors.Wrap(err, "could not verify checkpoint")
+		}
+		root, err := a.save(ctx, s.beaconDB)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not save checkpoint")
+		}
+		log.WithFields(logrus.Fields{
+			"slot":  a.state.Slot(),
+			"epoch": helpers.SlotToEpoch(a.state.Slot()),
+			"root":  fmt.Sprintf("%#x", root),
+		}).Info("Initialized beacon node from checkpoint")
+	}
+
+	s.originRoot, err = s.beaconDB.OriginBlockRoot(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get origin block root")
+	}
+	return s, nil
+}
+
+// Start backfilling blocks behind the checkpoint the node was started from, if any are missing.
+func (s *Service) Start() {
+	if s.originRoot == params.BeaconConfig().ZeroHash {
+		s.setBackfilled()
+		return
+	}
+	genesisBlock, err := s.beaconDB.GenesisBlock(s.ctx)
+	if err != nil {
+		log.WithError(err).Error("Could not get genesis block")
+		return
+	}
+	if genesisBlock != nil {
+		s.setBackfilled()
+		return
+	}
+	go s.backfill()
+}
+
+// Stop the checkpoint sync service.
+func (s *Service) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// Status of the checkpoint sync service. Backfilling is done in the background and does
+// not affect the health of the node.
+func (s *Service) Status() error {
+	return nil
+}
+
+// Backfilled returns true once all blocks down to genesis are present in the database.
+func (s *Service) Backfilled() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.backfilled
+}
+
+func (s *Service) setBackfilled() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.backfilled = true
+}
@@ -8,6 +8,7 @@ import (
 	"math"
 	"math/big"
 	mathRand "math/rand"
+	"net"
 	"sort"
 	"sync"
 	"time"
@@ -92,6 +93,7 @@ type fetchRequestParams struct {
 type fetchRequestResponse struct {
 	start, count uint64
 	blocks       []*eth.SignedBeaconBlock
+	pid          peer.ID
 	err          error
 }
 
@@ -248,16 +250,17 @@ func (f *blocksFetcher) handleRequest(ctx context.Context, start, count uint64)
 		return response
 	}
 
-	response.blocks, response.err = f.fetchBlocksFromPeer(ctx, start, count, peers)
+	response.blocks, response.pid, response.err = f.fetchBlocksFromPeer(ctx, start, count, peers)
 	return response
 }
 
-// fetchBlocksFromPeer fetches blocks from a single randomly selected peer.
+// fetchBlocksFromPeer fetches blocks from a single randomly selected peer, and returns the peer
+// which served them.
 func (f *blocksFetcher) fetchBlocksFromPeer(
 	ctx context.Context,
 	start, count uint64,
 	peers []peer.ID,
-) ([]*eth.SignedBeaconBlock, error) {
+) ([]*eth.SignedBeaconBlock, peer.ID, error) {
 	ctx, span := trace.StartSpan(ctx, "initialsync.fetchBlocksFromPeer")
 	defer span.End()
 
@@ -265,10 +268,10 @@ func (f *blocksFetcher) fetchBlocksFromPeer(
 	var err error
 	peers, err = f.filterPeers(peers, peersPercentagePerRequest)
 	if err != nil {
-		return blocks, err
+		return blocks, "", err
 	}
 	if len(peers) == 0 {
-		return blocks, errNoPeersAvailable
+		return blocks, "", errNoPeersAvailable
 	}
 	req := &p2ppb.BeaconBlocksByRangeRequest{
 		StartSlot: start,
@@ -277,10 +280,10 @@ func (f *blocksFetcher) fetchBlocksFromPeer(
 	}
 	for i := 0; i < len(peers); i++ {
 		if blocks, err = f.requestBlocks(ctx, req, peers[i]); err == nil {
-			return blocks, err
+			return blocks, peers[i], err
 		}
 	}
-	return blocks, nil
+	return blocks, "", nil
 }
 
 // requestBlocks is a wrapper for handling BeaconBlocksByRangeRequest requests/streams.
@@ -319,6 +322,11 @@ func (f *blocksFetcher) requestBlocks(
 	l.Unlock()
 	stream, err := f.p2p.Send(ctx, req, p2p.RPCBlocksByRangeTopic, pid)
 	if err != nil {
+		if isTimeout(err) {
+			f.p2p.Peers().IncrementTimeouts(pid)
+		} else {
+			f.p2p.Peers().IncrementRPCErrors(pid)
+		}
 		return nil, err
 	}
 	defer func() {
@@ -338,6 +346,7 @@ func (f *blocksFetcher) requestBlocks(
 			break
 		}
 		if err != nil {
+			f.recordReadFailure(pid, err)
 			return nil, err
 		}
 		resp = append(resp, blk)
@@ -346,6 +355,29 @@ func (f *blocksFetcher) requestBlocks(
 	return resp, nil
 }
 
+// recordReadFailure records a failure to read a response against the peer's score, depending on
+// whether the response timed out, was an error response or contained invalid data.
+func (f *blocksFetcher) recordReadFailure(pid peer.ID, err error) {
+	if isTimeout(err) {
+		f.p2p.Peers().IncrementTimeouts(pid)
+		return
+	}
+	if prysmsync.IsErrorResponse(err) {
+		f.p2p.Peers().IncrementRPCErrors(pid)
+		return
+	}
+	f.p2p.Peers().IncrementBadResponses(pid)
+}
+
+// isTimeout returns true if the error is caused by a request timing out.
+func isTimeout(err error) bool {
+	cause := errors.Cause(err)
+	if netErr, ok := cause.(net.Error); ok && netErr.Timeout() {
+		return true
+	}
+	return cause == context.DeadlineExceeded
+}
+
 // getPeerLock returns peer lock for a given peer. If lock is not found, it is created.
 func (f *blocksFetcher) getPeerLock(pid peer.ID) *peerLock {
 	f.Lock()
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/blockchain"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
@@ -49,8 +50,14 @@ type blocksQueue struct {
 	blocksFetcher       *blocksFetcher
 	headFetcher         blockchain.HeadFetcher
 	highestExpectedSlot uint64
-	fetchedBlocks       chan *eth.SignedBeaconBlock // output channel for ready blocks
-	quit                chan struct{}               // termination notifier
+	fetchedBlocks       chan *fetchedBlock // output channel for ready blocks
+	quit                chan struct{}      // termination notifier
+}
+
+// fetchedBlock is a block ready for processing, along with the peer which served it.
+type fetchedBlock struct {
+	block *eth.SignedBeaconBlock
+	pid   peer.ID
 }
 
 // newBlocksQueue creates initialized priority queue.
@@ -75,7 +82,7 @@ func newBlocksQueue(ctx context.Context, cfg *blocksQueueConfig) *blocksQueue {
 		highestExpectedSlot: highestExpectedSlot,
 		blocksFetcher:       blocksFetcher,
 		headFetcher:         cfg.headFetcher,
-		fetchedBlocks:       make(chan *eth.SignedBeaconBlock, blocksFetcher.blocksPerSecond),
+		fetchedBlocks:       make(chan *fetchedBlock, blocksFetcher.blocksPerSecond),
 		quit:                make(chan struct{}),
 	}
 
@@ -241,6 +248,7 @@ func (q *blocksQueue) onDataReceivedEvent(ctx context.Context) eventHandlerFn {
 			return m.state, response.err
 		}
 		m.blocks = response.blocks
+		m.pid = response.pid
 		return stateDataParsed, nil
 	}
 }
@@ -264,7 +272,7 @@ func (q *blocksQueue) onReadyToSendEvent(ctx context.Context) eventHandlerFn {
 				select {
 				case <-ctx.Done():
 					return m.state, ctx.Err()
-				case q.fetchedBlocks <- block:
+				case q.fetchedBlocks <- &fetchedBlock{block: block, pid: m.pid}:
 				}
 			}
 			return stateSent, nil
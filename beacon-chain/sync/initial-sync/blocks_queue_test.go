@@ -287,11 +287,14 @@ func TestBlocksQueueLoop(t *testing.T) {
 			}
 
 			var blocks []*eth.SignedBeaconBlock
-			for block := range queue.fetchedBlocks {
-				if err := processBlock(block); err != nil {
+			for data := range queue.fetchedBlocks {
+				if data.pid == "" {
+					t.Errorf("Block at slot %d has no serving peer", data.block.Block.Slot)
+				}
+				if err := processBlock(data.block); err != nil {
 					continue
 				}
-				blocks = append(blocks, block)
+				blocks = append(blocks, data.block)
 			}
 
 			if err := queue.stop(); err != nil {
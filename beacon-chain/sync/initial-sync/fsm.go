@@ -6,6 +6,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/libp2p/go-libp2p-core/peer"
 	eth "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
@@ -44,6 +45,7 @@ type stateMachine struct {
 	start   uint64
 	state   stateID
 	blocks  []*eth.SignedBeaconBlock
+	pid     peer.ID
 	updated time.Time
 }
 
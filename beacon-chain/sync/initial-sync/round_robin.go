@@ -58,11 +58,13 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 	blockReceiver := s.chain.ReceiveBlockInitialSync
 
 	// Step 1 - Sync to end of finalized epoch.
-	for blk := range queue.fetchedBlocks {
-		if err := s.processBlock(ctx, genesis, blk, blockReceiver); err != nil {
+	for data := range queue.fetchedBlocks {
+		if err := s.processBlock(ctx, genesis, data.block, blockReceiver); err != nil {
 			log.WithError(err).Info("Block is not processed")
 			continue
 		}
+		// Peers are only credited for blocks which passed processing.
+		s.p2p.Peers().IncrementBlocksServed(data.pid, 1)
 	}
 
 	log.Debug("Synced to finalized epoch - now syncing blocks up to current head")
@@ -116,6 +118,7 @@ func (s *Service) roundRobinSync(genesis time.Time) error {
 				return nil
 			}
 		}
+		s.p2p.Peers().IncrementBlocksServed(best, uint64(len(resp)))
 		if len(resp) == 0 {
 			break
 		}
@@ -25,6 +25,13 @@ var (
 		},
 		[]string{"topic"},
 	)
+	rateLimitedRequestsCounter = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "p2p_rpc_rate_limited_total",
+			Help: "Count of RPC requests rejected for exceeding a peer's rate limit, by topic or block quota.",
+		},
+		[]string{"topic"},
+	)
 	messageFailedValidationCounter = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "p2p_message_failed_validation_total",
@@ -0,0 +1,155 @@
+package sync
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/kevinms/leakybucket-go"
+	libp2pcore "github.com/libp2p/go-libp2p-core"
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/beacon-chain/flags"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+)
+
+// blockQuota is the quota of blocks a peer may request across the
+// beacon_blocks_by_range and beacon_blocks_by_root protocols.
+const blockQuota = "blocks"
+
+// rateLimit is the quota of a single rate limited resource, the amount a peer
+// may consume per second and in a single burst.
+type rateLimit struct {
+	rate  float64
+	burst int64
+}
+
+// defaultRateLimits returns the number of requests a peer may make per second
+// for each RPC topic, together with the number of blocks it may request.
+func defaultRateLimits() map[string]rateLimit {
+	blocksPerSecond := flags.Get().BlockBatchLimit
+	return map[string]rateLimit{
+		p2p.RPCStatusTopic:        {rate: 1, burst: 5},
+		p2p.RPCGoodByeTopic:       {rate: 1, burst: 1},
+		p2p.RPCBlocksByRangeTopic: {rate: 4, burst: 16},
+		p2p.RPCBlocksByRootTopic:  {rate: 4, burst: 16},
+		p2p.RPCPingTopic:          {rate: 1, burst: 5},
+		p2p.RPCMetaDataTopic:      {rate: 1, burst: 5},
+		blockQuota: {
+			rate:  float64(blocksPerSecond),
+			burst: int64(flags.Get().BlockBatchLimitBurstFactor * blocksPerSecond),
+		},
+	}
+}
+
+// rateLimiter tracks the quotas of each peer using a leaky bucket per rate
+// limited resource.
+type rateLimiter struct {
+	collectors map[string]*leakybucket.Collector
+}
+
+// newRateLimiter creates a rate limiter with the given quotas, keyed by RPC
+// topic or blockQuota.
+func newRateLimiter(limits map[string]rateLimit) *rateLimiter {
+	collectors := make(map[string]*leakybucket.Collector, len(limits))
+	for key, limit := range limits {
+		collectors[key] = leakybucket.NewCollector(limit.rate, limit.burst, false /* deleteEmptyBuckets */)
+	}
+	return &rateLimiter{collectors: collectors}
+}
+
+// validateRateLimit checks that the remote peer has at least amt of the quota
+// left. Otherwise the peer is penalised, the request is answered with an error
+// response and an error is returned. Resources without a quota are not limited.
+// Peers are not penalised for exceeding the goodbye quota, as a peer may resend its
+// goodbye message while disconnecting.
+func (s *Service) validateRateLimit(stream libp2pcore.Stream, key string, amt int64) error {
+	pid := stream.Conn().RemotePeer()
+	remaining := s.rateLimiter.remaining(pid.String(), key)
+	if remaining < 0 || amt <= remaining {
+		return nil
+	}
+	rateLimitedRequestsCounter.WithLabelValues(key).Inc()
+	if key == p2p.RPCGoodByeTopic {
+		return errors.New(rateLimitedError)
+	}
+	s.p2p.Peers().IncrementBadResponses(pid)
+	if s.p2p.Peers().IsBad(pid) {
+		log.WithField("peer", pid.Pretty()).Debug("Disconnecting bad peer")
+		defer func() {
+			if err := s.p2p.Disconnect(pid); err != nil {
+				log.WithError(err).Error("Failed to disconnect peer")
+			}
+		}()
+	}
+	s.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
+	return errors.New(rateLimitedError)
+}
+
+// add consumes amt of the remote peer's quota.
+func (l *rateLimiter) add(stream libp2pcore.Stream, key string, amt int64) {
+	if collector, ok := l.collectors[key]; ok {
+		collector.Add(stream.Conn().RemotePeer().String(), amt)
+	}
+}
+
+// remaining returns the quota the given peer has left, or -1 if the resource
+// is not limited.
+func (l *rateLimiter) remaining(peerID string, key string) int64 {
+	collector, ok := l.collectors[key]
+	if !ok {
+		return -1
+	}
+	return collector.Remaining(peerID)
+}
+
+// free releases the resources held by the rate limiter.
+func (l *rateLimiter) free() {
+	for _, collector := range l.collectors {
+		collector.Free()
+	}
+}
+
+// rpcTopicName returns the protocol name of an RPC topic, which is used to
+// refer to the topic in the configuration, e.g. beacon_blocks_by_root.
+func rpcTopicName(topic string) string {
+	return path.Base(path.Dir(topic))
+}
+
+// ValidateRateLimits checks the user provided rate limit overrides, given as
+// <protocol>=<requests per second>:<burst>.
+func ValidateRateLimits(overrides []string) error {
+	return parseRateLimits(defaultRateLimits(), overrides)
+}
+
+// parseRateLimits applies the user provided overrides, given as
+// <protocol>=<requests per second>:<burst>, to the limits.
+func parseRateLimits(limits map[string]rateLimit, overrides []string) error {
+	topics := make(map[string]string, len(limits))
+	for key := range limits {
+		topics[rpcTopicName(key)] = key
+	}
+	for _, override := range overrides {
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return errors.Errorf("invalid rate limit %q, expected <protocol>=<requests per second>:<burst>", override)
+		}
+		key, ok := topics[parts[0]]
+		if !ok || key == blockQuota {
+			return errors.Errorf("invalid rate limit %q, unknown protocol %s", override, parts[0])
+		}
+		quota := strings.SplitN(parts[1], ":", 2)
+		if len(quota) != 2 {
+			return errors.Errorf("invalid rate limit %q, expected <protocol>=<requests per second>:<burst>", override)
+		}
+		rate, err := strconv.ParseFloat(quota[0], 64)
+		if err != nil || rate <= 0 {
+			return errors.Errorf("invalid rate limit %q, rate must be a positive number", override)
+		}
+		burst, err := strconv.ParseInt(quota[1], 10, 64)
+		if err != nil || burst <= 0 {
+			return errors.Errorf("invalid rate limit %q, burst must be a positive integer", override)
+		}
+		limits[key] = rateLimit{rate: rate, burst: burst}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package sync
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/prysmaticlabs/prysm/beacon-chain/p2p"
+	p2ptest "github.com/prysmaticlabs/prysm/beacon-chain/p2p/testing"
+)
+
+func TestValidateRateLimit_PenalisesPeer(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	if len(p1.BHost.Network().Peers()) != 1 {
+		t.Error("Expected peers to be connected")
+	}
+	r := &Service{
+		p2p:         p1,
+		rateLimiter: newRateLimiter(map[string]rateLimit{p2p.RPCPingTopic: {rate: 0.000001, burst: 2}}),
+	}
+	pcl := protocol.ID("/testing")
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {})
+
+	for i := 0; i < 3; i++ {
+		stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = r.validateRateLimit(stream, p2p.RPCPingTopic, 1)
+		if i < 2 && err != nil {
+			t.Errorf("Unexpected error for request %d: %v", i, err)
+		}
+		if i == 2 && (err == nil || err.Error() != rateLimitedError) {
+			t.Errorf("Expected error %q for request %d, received %v", rateLimitedError, i, err)
+		}
+		r.rateLimiter.add(stream, p2p.RPCPingTopic, 1)
+		// Topics without a quota are never limited.
+		if err := r.validateRateLimit(stream, p2p.RPCStatusTopic, 100); err != nil {
+			t.Errorf("Unexpected error for unlimited topic: %v", err)
+		}
+	}
+
+	badResponses, err := p1.Peers().BadResponses(p2.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badResponses != 1 {
+		t.Errorf("Wanted 1 bad response, received %d", badResponses)
+	}
+}
+
+func TestValidateRateLimit_DoesNotPenaliseGoodbye(t *testing.T) {
+	p1 := p2ptest.NewTestP2P(t)
+	p2 := p2ptest.NewTestP2P(t)
+	p1.Connect(p2)
+	r := &Service{
+		p2p:         p1,
+		rateLimiter: newRateLimiter(map[string]rateLimit{p2p.RPCGoodByeTopic: {rate: 0.000001, burst: 1}}),
+	}
+	pcl := protocol.ID("/testing")
+	p2.BHost.SetStreamHandler(pcl, func(stream network.Stream) {})
+
+	for i := 0; i < 3; i++ {
+		stream, err := p1.BHost.NewStream(context.Background(), p2.BHost.ID(), pcl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = r.validateRateLimit(stream, p2p.RPCGoodByeTopic, 1)
+		if i > 0 && (err == nil || err.Error() != rateLimitedError) {
+			t.Errorf("Expected error %q for request %d, received %v", rateLimitedError, i, err)
+		}
+		r.rateLimiter.add(stream, p2p.RPCGoodByeTopic, 1)
+	}
+
+	badResponses, err := p1.Peers().BadResponses(p2.PeerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if badResponses != 0 {
+		t.Errorf("Wanted no bad responses, received %d", badResponses)
+	}
+}
+
+func TestParseRateLimits(t *testing.T) {
+	limits := defaultRateLimits()
+	if err := parseRateLimits(limits, []string{"beacon_blocks_by_root=0.5:10", "status=2:4"}); err != nil {
+		t.Fatal(err)
+	}
+	if limits[p2p.RPCBlocksByRootTopic] != (rateLimit{rate: 0.5, burst: 10}) {
+		t.Errorf("Unexpected blocks by root limit %+v", limits[p2p.RPCBlocksByRootTopic])
+	}
+	if limits[p2p.RPCStatusTopic] != (rateLimit{rate: 2, burst: 4}) {
+		t.Errorf("Unexpected status limit %+v", limits[p2p.RPCStatusTopic])
+	}
+	if limits[p2p.RPCPingTopic] != defaultRateLimits()[p2p.RPCPingTopic] {
+		t.Errorf("Ping limit should not have changed, received %+v", limits[p2p.RPCPingTopic])
+	}
+
+	tests := []struct {
+		override  string
+		wantedErr string
+	}{
+		{override: "status", wantedErr: "expected <protocol>="},
+		{override: "status=1", wantedErr: "expected <protocol>="},
+		{override: "unknown=1:1", wantedErr: "unknown protocol"},
+		{override: "status=-1:1", wantedErr: "rate must be a positive number"},
+		{override: "status=1:0.5", wantedErr: "burst must be a positive integer"},
+	}
+	for _, tt := range tests {
+		err := parseRateLimits(defaultRateLimits(), []string{tt.override})
+		if err == nil || !strings.Contains(err.Error(), tt.wantedErr) {
+			t.Errorf("Expected error %q for %s, received %v", tt.wantedErr, tt.override, err)
+		}
+	}
+}
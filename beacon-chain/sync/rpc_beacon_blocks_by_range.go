@@ -52,7 +52,7 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	// The final requested slot from remote peer.
 	endReqSlot := startSlot + (m.Step * (m.Count - 1))
 
-	remainingBucketCapacity := s.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String())
+	remainingBucketCapacity := s.rateLimiter.remaining(stream.Conn().RemotePeer().String(), blockQuota)
 	span.AddAttributes(
 		trace.Int64Attribute("start", int64(startSlot)),
 		trace.Int64Attribute("end", int64(endReqSlot)),
@@ -63,19 +63,9 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 	)
 	maxRequestBlocks := params.BeaconNetworkConfig().MaxRequestBlocks
 	for startSlot <= endReqSlot {
-		remainingBucketCapacity = s.blocksRateLimiter.Remaining(stream.Conn().RemotePeer().String())
-		if int64(allowedBlocksPerSecond) > remainingBucketCapacity {
-			s.p2p.Peers().IncrementBadResponses(stream.Conn().RemotePeer())
-			if s.p2p.Peers().IsBad(stream.Conn().RemotePeer()) {
-				log.Debug("Disconnecting bad peer")
-				defer func() {
-					if err := s.p2p.Disconnect(stream.Conn().RemotePeer()); err != nil {
-						log.WithError(err).Error("Failed to disconnect peer")
-					}
-				}()
-			}
-			s.writeErrorResponseToStream(responseCodeInvalidRequest, rateLimitedError, stream)
-			return errors.New(rateLimitedError)
+		if err := s.validateRateLimit(stream, blockQuota, int64(allowedBlocksPerSecond)); err != nil {
+			traceutil.AnnotateError(span, err)
+			return err
 		}
 
 		if endSlot-startSlot > rangeLimit || m.Step == 0 || m.Count > maxRequestBlocks {
@@ -91,8 +81,7 @@ func (s *Service) beaconBlocksByRangeRPCHandler(ctx context.Context, msg interfa
 
 		// Decrease allowed blocks capacity by the number of streamed blocks.
 		if startSlot <= endSlot {
-			s.blocksRateLimiter.Add(
-				stream.Conn().RemotePeer().String(), int64(1+(endSlot-startSlot)/m.Step))
+			s.rateLimiter.add(stream, blockQuota, int64(1+(endSlot-startSlot)/m.Step))
 		}
 
 		// Recalculate start and end slots for the next batch to be returned to the remote peer.
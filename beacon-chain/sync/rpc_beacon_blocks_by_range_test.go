@@ -6,7 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/kevinms/leakybucket-go"
 	"github.com/libp2p/go-libp2p-core/network"
 	"github.com/libp2p/go-libp2p-core/protocol"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -43,7 +42,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 
 	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, int64(req.Count*10), false),
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 0.000001, burst: int64(req.Count * 10)}}),
 		chain: &chainMock.ChainService{}}
 	pcl := protocol.ID("/testing")
 
@@ -74,7 +73,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsBlocks(t *testing.T) {
 	}
 
 	// Make sure that rate limiter doesn't limit capacity exceedingly.
-	remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+	remainingCapacity := r.rateLimiter.remaining(p2.PeerID().String(), blockQuota)
 	expectedCapacity := int64(req.Count*10 - req.Count)
 	if remainingCapacity != expectedCapacity {
 		t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -109,7 +108,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerReturnsSortedBlocks(t *testing.T) {
 	}
 
 	// Start service with 160 as allowed blocks capacity (and almost zero capacity recovery).
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, int64(req.Count*10), false),
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 0.000001, burst: int64(req.Count * 10)}}),
 		chain: &chainMock.ChainService{}}
 	pcl := protocol.ID("/testing")
 
@@ -178,7 +177,7 @@ func TestRPCBeaconBlocksByRange_ReturnsGenesisBlock(t *testing.T) {
 		}
 	}
 
-	r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(10000, 10000, false), chain: &chainMock.ChainService{}}
+	r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 10000, burst: 10000}}), chain: &chainMock.ChainService{}}
 	pcl := protocol.ID("/testing")
 
 	var wg sync.WaitGroup
@@ -273,7 +272,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * 3)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 0.000001, burst: capacity}}), chain: &chainMock.ChainService{}}
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -288,7 +287,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 		testutil.AssertLogsDoNotContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.remaining(p2.PeerID().String(), blockQuota)
 		expectedCapacity := int64(0) // Whole capacity is used, but no overflow.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -304,7 +303,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * 3)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 0.000001, burst: capacity}}), chain: &chainMock.ChainService{}}
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -323,7 +322,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		// Make sure that we were blocked indeed.
 		testutil.AssertLogsContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.remaining(p2.PeerID().String(), blockQuota)
 		expectedCapacity := int64(0) // Whole capacity is used.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
@@ -339,7 +338,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 
 		capacity := int64(flags.Get().BlockBatchLimit * flags.Get().BlockBatchLimitBurstFactor)
-		r := &Service{p2p: p1, db: d, blocksRateLimiter: leakybucket.NewCollector(0.000001, capacity, false), chain: &chainMock.ChainService{}}
+		r := &Service{p2p: p1, db: d, rateLimiter: newRateLimiter(map[string]rateLimit{blockQuota: {rate: 0.000001, burst: capacity}}), chain: &chainMock.ChainService{}}
 
 		req := &pb.BeaconBlocksByRangeRequest{
 			StartSlot: 100,
@@ -366,7 +365,7 @@ func TestRPCBeaconBlocksByRange_RPCHandlerRateLimitOverflow(t *testing.T) {
 		}
 		testutil.AssertLogsContain(t, hook, "Disconnecting bad peer")
 
-		remainingCapacity := r.blocksRateLimiter.Remaining(p2.PeerID().String())
+		remainingCapacity := r.rateLimiter.remaining(p2.PeerID().String(), blockQuota)
 		expectedCapacity := int64(0) // Whole capacity is used.
 		if remainingCapacity != expectedCapacity {
 			t.Fatalf("Unexpected rate limiting capacity, expected: %v, got: %v", expectedCapacity, remainingCapacity)
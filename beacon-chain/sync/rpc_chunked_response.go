@@ -1,7 +1,6 @@
 package sync
 
 import (
-	"errors"
 	"time"
 
 	libp2pcore "github.com/libp2p/go-libp2p-core"
@@ -48,7 +47,22 @@ func readResponseChunk(stream libp2pcore.Stream, p2p p2p.P2P, to interface{}) er
 	}
 
 	if code != 0 {
-		return errors.New(errMsg)
+		return errorResponse(errMsg)
 	}
 	return p2p.Encoding().DecodeWithMaxLength(stream, to, maxChunkSize)
 }
+
+// errorResponse is the error message of a response chunk with a non-success
+// result code.
+type errorResponse string
+
+func (e errorResponse) Error() string {
+	return string(e)
+}
+
+// IsErrorResponse returns true if the error is an error response sent by the
+// remote peer, rather than a failure to read or decode the response.
+func IsErrorResponse(err error) bool {
+	_, ok := err.(errorResponse)
+	return ok
+}
@@ -10,7 +10,6 @@ import (
 	"time"
 
 	lru "github.com/hashicorp/golang-lru"
-	"github.com/kevinms/leakybucket-go"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
@@ -91,7 +90,7 @@ type Service struct {
 	validateBlockLock         sync.RWMutex
 	stateNotifier             statefeed.Notifier
 	blockNotifier             blockfeed.Notifier
-	blocksRateLimiter         *leakybucket.Collector
+	rateLimiter               *rateLimiter
 	attestationNotifier       operation.Notifier
 	seenBlockLock             sync.RWMutex
 	seenBlockCache            *lru.Cache
@@ -109,9 +108,13 @@ type Service struct {
 
 // NewRegularSync service.
 func NewRegularSync(cfg *Config) *Service {
-	// Initialize block limits.
-	allowedBlocksPerSecond := float64(flags.Get().BlockBatchLimit)
-	allowedBlocksBurst := int64(flags.Get().BlockBatchLimitBurstFactor * flags.Get().BlockBatchLimit)
+	// Initialize request and block limits.
+	// The overrides are validated on startup by ValidateRateLimits.
+	limits := defaultRateLimits()
+	if err := parseRateLimits(limits, flags.Get().RPCRateLimits); err != nil {
+		log.WithError(err).Error("Could not apply RPC rate limits, using defaults")
+		limits = defaultRateLimits()
+	}
 
 	ctx, cancel := context.WithCancel(context.Background())
 	r := &Service{
@@ -132,7 +135,7 @@ func NewRegularSync(cfg *Config) *Service {
 		blockNotifier:        cfg.BlockNotifier,
 		stateSummaryCache:    cfg.StateSummaryCache,
 		stateGen:             cfg.StateGen,
-		blocksRateLimiter:    leakybucket.NewCollector(allowedBlocksPerSecond, allowedBlocksBurst, false /* deleteEmptyBuckets */),
+		rateLimiter:          newRateLimiter(limits),
 	}
 
 	go r.registerHandlers()
@@ -163,12 +166,8 @@ func (s *Service) Start() {
 
 // Stop the regular sync service.
 func (s *Service) Stop() error {
-	defer func() {
-		if s.blocksRateLimiter != nil {
-			s.blocksRateLimiter.Free()
-			s.blocksRateLimiter = nil
-		}
-	}()
+	// The rate limiter is kept, as handlers may still be running.
+	defer s.rateLimiter.free()
 	defer s.cancel()
 	return nil
 }
@@ -97,7 +97,7 @@ func (s *Service) subscribeWithBase(base proto.Message, topic string, validator
 	topic += s.p2p.Encoding().ProtocolSuffix()
 	log := log.WithField("topic", topic)
 
-	if err := s.p2p.PubSub().RegisterTopicValidator(wrapAndReportValidation(topic, validator)); err != nil {
+	if err := s.p2p.PubSub().RegisterTopicValidator(s.wrapAndReportValidation(topic, validator)); err != nil {
 		log.WithError(err).Error("Failed to register validator")
 	}
 
@@ -164,8 +164,8 @@ func (s *Service) subscribeWithBase(base proto.Message, topic string, validator
 }
 
 // Wrap the pubsub validator with a metric monitoring function. This function increments the
-// appropriate counter if the particular message fails to validate.
-func wrapAndReportValidation(topic string, v pubsub.ValidatorEx) (string, pubsub.ValidatorEx) {
+// appropriate counter and penalises the sending peer if the particular message fails to validate.
+func (s *Service) wrapAndReportValidation(topic string, v pubsub.ValidatorEx) (string, pubsub.ValidatorEx) {
 	return topic, func(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
 		defer messagehandler.HandlePanic(ctx, msg)
 		ctx, cancel := context.WithTimeout(ctx, pubsubMessageTimeout)
@@ -174,6 +174,7 @@ func wrapAndReportValidation(topic string, v pubsub.ValidatorEx) (string, pubsub
 		b := v(ctx, pid, msg)
 		if b == pubsub.ValidationReject {
 			messageFailedValidationCounter.WithLabelValues(topic).Inc()
+			s.p2p.Peers().IncrementInvalidGossip(pid)
 		}
 		return b
 	}
@@ -104,7 +104,17 @@ var appHelpFlagGroups = []flagGroup{
 			flags.DisableDiscv5,
 			flags.BlockBatchLimit,
 			flags.BlockBatchLimitBurstFactor,
+			flags.RPCRateLimits,
 			flags.EnableDebugRPCEndpoints,
+			flags.DBEngine,
+			flags.DBBackupRetention,
+			flags.DBBackupFullInterval,
+			flags.DBRetentionEpochs,
+			flags.HistoricalStateInterval,
+			flags.ArchivedStateSnapshotInterval,
+			flags.HotStateCacheSize,
+			flags.HotStateSpillSize,
+			flags.HotStateSpillDir,
 			flags.SlotsPerArchivedPoint,
 		},
 	},
@@ -154,6 +164,15 @@ var appHelpFlagGroups = []flagGroup{
 			flags.ArchiveAttestationsFlag,
 		},
 	},
+	{
+		Name: "checkpoint",
+		Flags: []cli.Flag{
+			flags.CheckpointStateFlag,
+			flags.CheckpointBlockFlag,
+			flags.CheckpointSyncProviderFlag,
+			flags.WeakSubjectivityCheckpointFlag,
+		},
+	},
 }
 
 func init() {
@@ -0,0 +1,170 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/hkdf"
+)
+
+// curveOrder is the order r of the BLS12-381 curve subgroups.
+var curveOrder, _ = new(big.Int).SetString("73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001", 16)
+
+const (
+	// EIP-2334 purpose and coin type of the key derivation paths of Ethereum 2.0 validators.
+	eip2334Purpose  = 12381
+	eip2334CoinType = 3600
+	// Number of lamport secret key chunks derived per parent key in EIP-2333.
+	lamportChunks = 255
+)
+
+// WithdrawalKeyPath returns the EIP-2334 derivation path of the withdrawal key of the validator at the given index.
+func WithdrawalKeyPath(index uint64) string {
+	return fmt.Sprintf("m/%d/%d/%d/0", eip2334Purpose, eip2334CoinType, index)
+}
+
+// SigningKeyPath returns the EIP-2334 derivation path of the signing key of the validator at the given index.
+func SigningKeyPath(index uint64) string {
+	return WithdrawalKeyPath(index) + "/0"
+}
+
+// DeriveMasterSecretKey derives the master secret key from a seed, as defined in EIP-2333.
+func DeriveMasterSecretKey(seed []byte) (SecretKey, error) {
+	sk, err := deriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	return secretKeyFromInt(sk)
+}
+
+// DeriveChildSecretKey derives the child secret key at the given index of a parent secret key,
+// as defined in EIP-2333.
+func DeriveChildSecretKey(parent SecretKey, index uint32) (SecretKey, error) {
+	sk, err := deriveChildSK(new(big.Int).SetBytes(parent.Marshal()), index)
+	if err != nil {
+		return nil, err
+	}
+	return secretKeyFromInt(sk)
+}
+
+// DeriveSecretKeyFromPath derives the secret key at a path of the form m/12381/3600/0/0 from a seed.
+func DeriveSecretKeyFromPath(seed []byte, path string) (SecretKey, error) {
+	indices, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := deriveMasterSK(seed)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range indices {
+		sk, err = deriveChildSK(sk, index)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return secretKeyFromInt(sk)
+}
+
+func parsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+	if segments[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q does not start with m", path)
+	}
+	indices := make([]uint32, 0, len(segments)-1)
+	for _, segment := range segments[1:] {
+		index, err := strconv.ParseUint(segment, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid index in derivation path %q", path)
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+func deriveMasterSK(seed []byte) (*big.Int, error) {
+	if len(seed) < 32 {
+		return nil, errors.New("seed must be at least 32 bytes long")
+	}
+	return hkdfModR(seed)
+}
+
+func deriveChildSK(parentSK *big.Int, index uint32) (*big.Int, error) {
+	lamportPK, err := parentSKToLamportPK(parentSK, index)
+	if err != nil {
+		return nil, err
+	}
+	return hkdfModR(lamportPK)
+}
+
+// hkdfModR derives a non zero secret key from the input key material.
+func hkdfModR(ikm []byte) (*big.Int, error) {
+	// L is ceil((3 * ceil(log2(r))) / 16) = 48 bytes.
+	const l = 48
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, append(append([]byte{}, ikm...), 0), salt)
+		okm := make([]byte, l)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte{0, l}), okm); err != nil {
+			return nil, err
+		}
+		sk.Mod(new(big.Int).SetBytes(okm), curveOrder)
+	}
+	return sk, nil
+}
+
+func parentSKToLamportPK(parentSK *big.Int, index uint32) ([]byte, error) {
+	salt := []byte{byte(index >> 24), byte(index >> 16), byte(index >> 8), byte(index)}
+	ikm := intToBytes32(parentSK)
+	notIKM := make([]byte, len(ikm))
+	for i := range ikm {
+		notIKM[i] = ^ikm[i]
+	}
+	lamport0, err := ikmToLamportSK(ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	lamport1, err := ikmToLamportSK(notIKM, salt)
+	if err != nil {
+		return nil, err
+	}
+	lamportPK := make([]byte, 0, 2*lamportChunks*sha256.Size)
+	for _, chunk := range append(lamport0, lamport1...) {
+		h := sha256.Sum256(chunk)
+		lamportPK = append(lamportPK, h[:]...)
+	}
+	compressed := sha256.Sum256(lamportPK)
+	return compressed[:], nil
+}
+
+func ikmToLamportSK(ikm []byte, salt []byte) ([][]byte, error) {
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	okm := make([]byte, lamportChunks*sha256.Size)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, nil), okm); err != nil {
+		return nil, err
+	}
+	chunks := make([][]byte, lamportChunks)
+	for i := range chunks {
+		chunks[i] = okm[i*sha256.Size : (i+1)*sha256.Size]
+	}
+	return chunks, nil
+}
+
+func intToBytes32(i *big.Int) []byte {
+	b := make([]byte, 32)
+	enc := i.Bytes()
+	copy(b[32-len(enc):], enc)
+	return b
+}
+
+func secretKeyFromInt(sk *big.Int) (SecretKey, error) {
+	return SecretKeyFromBytes(intToBytes32(sk))
+}
@@ -0,0 +1,106 @@
+package bls
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// Test case 0 of EIP-2333, the seed being the BIP-39 seed of the mnemonic below with the password TREZOR.
+const (
+	testMnemonic   = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	testSeed       = "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	testMasterSK   = "6083874454709270928345386274498605044986640685124978867557563392430687146096"
+	testChildSK    = "20397789859736650942317412262472558107875392172444076792671091975210932703118"
+	testChildIndex = 0
+	testSeedPasswd = "TREZOR"
+)
+
+func secretKeyToDecimal(sk SecretKey) string {
+	return new(big.Int).SetBytes(sk.Marshal()).String()
+}
+
+func TestSeedFromMnemonic(t *testing.T) {
+	seed, err := SeedFromMnemonic(testMnemonic, testSeedPasswd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(seed) != testSeed {
+		t.Errorf("Wanted seed %s, received %x", testSeed, seed)
+	}
+	if _, err := SeedFromMnemonic("abandon abandon abandon", ""); err == nil {
+		t.Error("Expected invalid mnemonic to fail")
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := strings.Fields(mnemonic); len(words) != 24 {
+		t.Errorf("Wanted 24 words, received %d", len(words))
+	}
+	if _, err := SeedFromMnemonic(mnemonic, ""); err != nil {
+		t.Errorf("Generated mnemonic is invalid: %v", err)
+	}
+}
+
+func TestDeriveSecretKeys(t *testing.T) {
+	seed, err := hex.DecodeString(testSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	master, err := DeriveMasterSecretKey(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secretKeyToDecimal(master); got != testMasterSK {
+		t.Errorf("Wanted master secret key %s, received %s", testMasterSK, got)
+	}
+	child, err := DeriveChildSecretKey(master, testChildIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secretKeyToDecimal(child); got != testChildSK {
+		t.Errorf("Wanted child secret key %s, received %s", testChildSK, got)
+	}
+	fromPath, err := DeriveSecretKeyFromPath(seed, "m/0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := secretKeyToDecimal(fromPath); got != testChildSK {
+		t.Errorf("Wanted secret key %s at path m/0, received %s", testChildSK, got)
+	}
+}
+
+func TestDeriveSecretKeyFromPath_InvalidInput(t *testing.T) {
+	seed := make([]byte, 32)
+	tests := []struct {
+		name string
+		seed []byte
+		path string
+	}{
+		{name: "Short seed", seed: make([]byte, 16), path: "m/0"},
+		{name: "Missing master node", seed: seed, path: "12381/3600/0/0"},
+		{name: "Invalid index", seed: seed, path: "m/12381/abc"},
+		{name: "Index overflow", seed: seed, path: "m/4294967296"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DeriveSecretKeyFromPath(tt.seed, tt.path); err == nil {
+				t.Error("Expected derivation to fail")
+			}
+		})
+	}
+}
+
+func TestKeyPaths(t *testing.T) {
+	if path := WithdrawalKeyPath(5); path != "m/12381/3600/5/0" {
+		t.Errorf("Unexpected withdrawal key path %s", path)
+	}
+	if path := SigningKeyPath(5); path != "m/12381/3600/5/0/0" {
+		t.Errorf("Unexpected signing key path %s", path)
+	}
+}
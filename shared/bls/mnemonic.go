@@ -0,0 +1,27 @@
+package bls
+
+import (
+	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// mnemonicEntropyBits is the entropy of generated mnemonics, resulting in 24 words.
+const mnemonicEntropyBits = 256
+
+// NewMnemonic generates a new random BIP-39 mnemonic, from which validator keys can be derived.
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits)
+	if err != nil {
+		return "", errors.Wrap(err, "could not generate entropy")
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// SeedFromMnemonic returns the BIP-39 seed of a mnemonic, protected by an optional password.
+func SeedFromMnemonic(mnemonic string, password string) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, password)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid mnemonic")
+	}
+	return seed, nil
+}
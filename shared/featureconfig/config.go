@@ -74,8 +74,9 @@ type Flags struct {
 	EnableSlasherConnection bool // EnableSlasher enable retrieval of slashing events from a slasher instance.
 	EnableBlockTreeCache    bool // EnableBlockTreeCache enable fork choice service to maintain latest filtered block tree.
 
-	KafkaBootstrapServers          string // KafkaBootstrapServers to find kafka servers to stream blocks, attestations, etc.
-	AttestationAggregationStrategy string // AttestationAggregationStrategy defines aggregation strategy to be used when aggregating.
+	KafkaBootstrapServers          string   // KafkaBootstrapServers to find kafka servers to stream blocks, attestations, etc.
+	ExportSinks                    []string // ExportSinks routes the objects saved in the database to export sinks.
+	AttestationAggregationStrategy string   // AttestationAggregationStrategy defines aggregation strategy to be used when aggregating.
 }
 
 var featureConfig *Flags
@@ -151,6 +152,10 @@ func ConfigureBeaconChain(ctx *cli.Context) {
 		log.Warn("Enabling experimental kafka streaming.")
 		cfg.KafkaBootstrapServers = ctx.String(kafkaBootstrapServersFlag.Name)
 	}
+	if sinks := ctx.StringSlice(exportSinkFlag.Name); len(sinks) > 0 {
+		log.Warn("Enabling experimental database export.")
+		cfg.ExportSinks = sinks
+	}
 	if ctx.Bool(enableSlasherFlag.Name) {
 		log.Warn("Enable slasher connection.")
 		cfg.EnableSlasherConnection = true
@@ -45,6 +45,13 @@ var (
 		Name:  "kafka-url",
 		Usage: "Stream attestations and blocks to specified kafka servers. This field is used for bootstrap.servers kafka config field.",
 	}
+	exportSinkFlag = &cli.StringSliceFlag{
+		Name: "export-sink",
+		Usage: "Export the objects saved in the database to a sink, as <types>=<sink>:<target>. The types are a comma " +
+			"separated list of blocks, attestations, slashings, exits and states. The sinks are kafka:<bootstrap servers>, " +
+			"ndjson:<directory>[?max-size-mb=<size>&max-files=<count>] to write newline-delimited JSON files rotated at the " +
+			"max size, and webhook:<url> to POST every object as JSON. The flag can be repeated to export to several sinks.",
+	}
 	initSyncVerifyEverythingFlag = &cli.BoolFlag{
 		Name: "initial-sync-verify-all-signatures",
 		Usage: "Initial sync to finalized checkpoint with verifying block's signature, RANDAO " +
@@ -523,6 +530,7 @@ var BeaconChainFlags = append(deprecatedFlags, []cli.Flag{
 	initSyncVerifyEverythingFlag,
 	skipBLSVerifyFlag,
 	kafkaBootstrapServersFlag,
+	exportSinkFlag,
 	enableBackupWebhookFlag,
 	enableSlasherFlag,
 	cacheFilteredBlockTreeFlag,
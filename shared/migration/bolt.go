@@ -0,0 +1,52 @@
+package migration
+
+import (
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bolt adapts a bolt database to DB.
+func Bolt(db *bolt.DB) DB {
+	return boltDB{db}
+}
+
+// BoltTx adapts a bolt transaction to Tx.
+func BoltTx(tx *bolt.Tx) Tx {
+	return boltTx{tx}
+}
+
+type boltDB struct {
+	db *bolt.DB
+}
+
+func (d boltDB) View(fn func(tx Tx) error) error {
+	return d.db.View(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+func (d boltDB) Update(fn func(tx Tx) error) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return fn(boltTx{tx})
+	})
+}
+
+type boltTx struct {
+	tx *bolt.Tx
+}
+
+func (t boltTx) Bucket(name []byte) Bucket {
+	// A nil *bolt.Bucket must not be wrapped into a non-nil interface.
+	bkt := t.tx.Bucket(name)
+	if bkt == nil {
+		return nil
+	}
+	return bkt
+}
+
+func (t boltTx) CreateBucketIfNotExists(name []byte) (Bucket, error) {
+	bkt, err := t.tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return bkt, nil
+}
@@ -0,0 +1,57 @@
+package migration
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v2"
+)
+
+// DryRunFlag lists the pending migrations of a database without applying them.
+var DryRunFlag = &cli.BoolFlag{
+	Name:  "dry-run",
+	Usage: "List the pending schema migrations of the database without applying them",
+}
+
+// PendingFunc returns the schema version and the pending migrations of the database
+// selected by the command line flags, without modifying it.
+type PendingFunc func(cliCtx *cli.Context) (uint64, []*Migration, error)
+
+// MigrateFunc applies the pending migrations of the database selected by the command
+// line flags.
+type MigrateFunc func(cliCtx *cli.Context) error
+
+// Command returns the migrate subcommand, which upgrades the schema of a database
+// or, with --dry-run, lists the migrations an upgrade would apply.
+func Command(flags []cli.Flag, pending PendingFunc, migrate MigrateFunc) *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "upgrades the database schema to the version supported by this release",
+		Description: `applies the pending schema migrations to the database. Migrations are also applied
+automatically when the node opens its database; use --dry-run to list the pending migrations
+without modifying the database`,
+		Flags: append(flags, DryRunFlag),
+		Action: func(cliCtx *cli.Context) error {
+			version, migrations, err := pending(cliCtx)
+			if err != nil {
+				return err
+			}
+			if len(migrations) == 0 {
+				log.WithField("version", version).Info("Database schema is up to date")
+				return nil
+			}
+			for _, m := range migrations {
+				log.WithFields(logrus.Fields{
+					"version": m.Version,
+					"name":    m.Name,
+				}).Info("Pending migration")
+			}
+			if cliCtx.Bool(DryRunFlag.Name) {
+				return nil
+			}
+			if err := migrate(cliCtx); err != nil {
+				return err
+			}
+			log.WithField("version", Latest(migrations)).Info("Database schema migrated")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,199 @@
+// Package migration upgrades the schema of a key-value database through an ordered
+// list of versioned migrations. The schema version of the database is recorded
+// in its metadata bucket and every migration step is committed together with
+// its progress, so an interrupted upgrade resumes where it stopped the next
+// time the database is opened.
+package migration
+
+import (
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+var log = logrus.WithField("prefix", "migration")
+
+var (
+	// schemaVersionKey is the key under which the schema version of the database is stored.
+	schemaVersionKey = []byte("schema-version")
+	// progressKey is the key under which the progress of the current migration is stored.
+	progressKey = []byte("schema-migration-progress")
+)
+
+// DB is a transactional key-value database organised in buckets, such as a bolt
+// database adapted with Bolt.
+type DB interface {
+	View(fn func(tx Tx) error) error
+	Update(fn func(tx Tx) error) error
+}
+
+// Tx is a transaction of a DB.
+type Tx interface {
+	// Bucket returns the bucket with the given name, or nil if it does not exist.
+	Bucket(name []byte) Bucket
+	CreateBucketIfNotExists(name []byte) (Bucket, error)
+}
+
+// Bucket is a collection of key-value pairs within a DB.
+type Bucket interface {
+	Get(key []byte) []byte
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+}
+
+// Migration is a single upgrade step of a database schema.
+type Migration struct {
+	// Version is the schema version of the database once the migration has completed.
+	Version uint64
+	// Name describes the migration.
+	Name string
+	// Up performs the migration. Each call runs in its own transaction and receives
+	// the progress returned by the previous call, or nil on the first call. Up returns
+	// nil once the migration is complete, otherwise the progress to continue from.
+	// Migrations which are cheap enough for a single transaction ignore the progress
+	// and return nil.
+	Up func(tx Tx, progress []byte) ([]byte, error)
+}
+
+// Validate checks that the migrations are ordered by strictly increasing versions,
+// starting from version 1.
+func Validate(migrations []*Migration) error {
+	var previous uint64
+	for _, m := range migrations {
+		if m.Version != previous+1 {
+			return errors.Errorf("migration %q has version %d, expected %d", m.Name, m.Version, previous+1)
+		}
+		if m.Up == nil {
+			return errors.Errorf("migration %q has no upgrade step", m.Name)
+		}
+		previous = m.Version
+	}
+	return nil
+}
+
+// Latest returns the schema version of a database with all migrations applied.
+func Latest(migrations []*Migration) uint64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Version returns the schema version recorded in the given bucket. Databases which
+// predate schema versioning have version 0.
+func Version(tx Tx, bucket []byte) uint64 {
+	bkt := tx.Bucket(bucket)
+	if bkt == nil {
+		return 0
+	}
+	v := bkt.Get(schemaVersionKey)
+	if v == nil {
+		return 0
+	}
+	return bytesutil.FromBytes8(v)
+}
+
+// Pending returns the current schema version of the database and the migrations
+// which have not been applied to it yet. It does not modify the database, so it
+// may be used on a database opened read-only.
+func Pending(db DB, bucket []byte, migrations []*Migration) (uint64, []*Migration, error) {
+	if err := Validate(migrations); err != nil {
+		return 0, nil, err
+	}
+	var version uint64
+	if err := db.View(func(tx Tx) error {
+		version = Version(tx, bucket)
+		return nil
+	}); err != nil {
+		return 0, nil, err
+	}
+	if latest := Latest(migrations); version > latest {
+		return version, nil, errors.Errorf(
+			"database schema version %d is newer than the latest version %d supported by this release, please upgrade",
+			version,
+			latest,
+		)
+	}
+	return version, migrations[version:], nil
+}
+
+// PendingInFile opens the bolt database file read-only and returns its schema version
+// and pending migrations, see Pending.
+func PendingInFile(datafile string, bucket []byte, migrations []*Migration) (uint64, []*Migration, error) {
+	if _, err := os.Stat(datafile); err != nil {
+		return 0, nil, errors.Wrap(err, "could not find database")
+	}
+	db, err := bolt.Open(datafile, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		if err == bolt.ErrTimeout {
+			return 0, nil, errors.New("cannot obtain database lock, database may be in use by another process")
+		}
+		return 0, nil, err
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.WithError(err).Error("Failed to close database")
+		}
+	}()
+	return Pending(Bolt(db), bucket, migrations)
+}
+
+// Run applies all pending migrations to the database in order. The schema version
+// and the progress of the current migration are stored in the given bucket, which
+// is created if it does not exist.
+func Run(db DB, bucket []byte, migrations []*Migration) error {
+	version, pending, err := Pending(db, bucket, migrations)
+	if err != nil {
+		return err
+	}
+	for _, m := range pending {
+		log.WithFields(logrus.Fields{
+			"from": version,
+			"to":   m.Version,
+			"name": m.Name,
+		}).Info("Migrating database schema")
+		if err := apply(db, bucket, m); err != nil {
+			return errors.Wrapf(err, "could not apply migration %d (%s)", m.Version, m.Name)
+		}
+		version = m.Version
+	}
+	return nil
+}
+
+// apply runs the upgrade step of the migration until it completes, committing its
+// progress with every step and the new schema version with the last one.
+func apply(db DB, bucket []byte, m *Migration) error {
+	for done := false; !done; {
+		if err := db.Update(func(tx Tx) error {
+			bkt, err := tx.CreateBucketIfNotExists(bucket)
+			if err != nil {
+				return err
+			}
+			progress := bytesutil.SafeCopyBytes(bkt.Get(progressKey))
+			next, err := m.Up(tx, progress)
+			if err != nil {
+				return err
+			}
+			// The bucket is looked up again, as the migration may have recreated it.
+			bkt = tx.Bucket(bucket)
+			if bkt == nil {
+				return errors.New("migration removed the metadata bucket")
+			}
+			if next != nil {
+				return bkt.Put(progressKey, next)
+			}
+			done = true
+			if err := bkt.Delete(progressKey); err != nil {
+				return err
+			}
+			return bkt.Put(schemaVersionKey, bytesutil.Bytes8(m.Version))
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package migration
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	metadataBucket = []byte("metadata")
+	dataBucket     = []byte("data")
+)
+
+func setupDB(t *testing.T) *bolt.DB {
+	dir, err := ioutil.TempDir("", "migration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := bolt.Open(filepath.Join(dir, "test.db"), 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return db
+}
+
+func createDataBucket(tx Tx, _ []byte) ([]byte, error) {
+	_, err := tx.CreateBucketIfNotExists(dataBucket)
+	return nil, err
+}
+
+func TestValidate(t *testing.T) {
+	up := func(Tx, []byte) ([]byte, error) { return nil, nil }
+	if err := Validate([]*Migration{{Version: 1, Up: up}, {Version: 2, Up: up}}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := Validate([]*Migration{{Version: 1, Up: up}, {Version: 3, Up: up}}); err == nil {
+		t.Error("Expected error for a gap in the versions")
+	}
+	if err := Validate([]*Migration{{Version: 2, Up: up}, {Version: 1, Up: up}}); err == nil {
+		t.Error("Expected error for unordered versions")
+	}
+	if err := Validate([]*Migration{{Version: 1}}); err == nil {
+		t.Error("Expected error for a migration without upgrade step")
+	}
+}
+
+func TestRun(t *testing.T) {
+	db := setupDB(t)
+	migrations := []*Migration{
+		{Version: 1, Name: "create data bucket", Up: createDataBucket},
+		{Version: 2, Name: "write key", Up: func(tx Tx, _ []byte) ([]byte, error) {
+			return nil, tx.Bucket(dataBucket).Put([]byte("key"), []byte("value"))
+		}},
+	}
+
+	version, pending, err := Pending(Bolt(db), metadataBucket, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 || len(pending) != 2 {
+		t.Errorf("Expected version 0 with 2 pending migrations, received version %d with %d", version, len(pending))
+	}
+	if err := Run(Bolt(db), metadataBucket, migrations[:1]); err != nil {
+		t.Fatal(err)
+	}
+	version, pending, err = Pending(Bolt(db), metadataBucket, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 1 || len(pending) != 1 || pending[0].Version != 2 {
+		t.Errorf("Expected version 1 with migration 2 pending, received version %d with %d", version, len(pending))
+	}
+
+	if err := Run(Bolt(db), metadataBucket, migrations); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.View(func(tx *bolt.Tx) error {
+		if v := Version(BoltTx(tx), metadataBucket); v != 2 {
+			t.Errorf("Expected version 2, received %d", v)
+		}
+		if v := tx.Bucket(dataBucket).Get([]byte("key")); string(v) != "value" {
+			t.Errorf("Expected migrated value, received %q", v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Running the migrations again is a no-op.
+	if err := Run(Bolt(db), metadataBucket, migrations); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_Resumable(t *testing.T) {
+	db := setupDB(t)
+	// The migration writes one key per step and fails once after the second step.
+	failed := false
+	var steps []uint64
+	migration := &Migration{
+		Version: 1,
+		Name:    "write keys",
+		Up: func(tx Tx, progress []byte) ([]byte, error) {
+			var i uint64
+			if progress != nil {
+				i = bytesutil.FromBytes8(progress)
+			}
+			if i == 2 && !failed {
+				failed = true
+				return nil, errors.New("interrupted")
+			}
+			steps = append(steps, i)
+			bkt, err := tx.CreateBucketIfNotExists(dataBucket)
+			if err != nil {
+				return nil, err
+			}
+			if err := bkt.Put(bytesutil.Bytes8(i), []byte{1}); err != nil {
+				return nil, err
+			}
+			if i == 3 {
+				return nil, nil
+			}
+			return bytesutil.Bytes8(i + 1), nil
+		},
+	}
+
+	err := Run(Bolt(db), metadataBucket, []*Migration{migration})
+	if err == nil || !strings.Contains(err.Error(), "interrupted") {
+		t.Fatalf("Expected interrupted migration, received %v", err)
+	}
+	version, pending, err := Pending(Bolt(db), metadataBucket, []*Migration{migration})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != 0 || len(pending) != 1 {
+		t.Errorf("Interrupted migration should still be pending, received version %d", version)
+	}
+
+	if err := Run(Bolt(db), metadataBucket, []*Migration{migration}); err != nil {
+		t.Fatal(err)
+	}
+	// Completed steps are not repeated.
+	if len(steps) != 4 {
+		t.Errorf("Expected 4 steps, received %v", steps)
+	}
+	if err := db.View(func(tx *bolt.Tx) error {
+		if v := Version(BoltTx(tx), metadataBucket); v != 1 {
+			t.Errorf("Expected version 1, received %d", v)
+		}
+		if tx.Bucket(metadataBucket).Get(progressKey) != nil {
+			t.Error("Expected progress to be removed after the migration completed")
+		}
+		if n := tx.Bucket(dataBucket).Stats().KeyN; n != 4 {
+			t.Errorf("Expected 4 keys, received %d", n)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun_NewerSchema(t *testing.T) {
+	db := setupDB(t)
+	if err := db.Update(func(tx *bolt.Tx) error {
+		bkt, err := tx.CreateBucket(metadataBucket)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(schemaVersionKey, bytesutil.Bytes8(5))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	err := Run(Bolt(db), metadataBucket, []*Migration{{Version: 1, Up: createDataBucket}})
+	if err == nil || !strings.Contains(err.Error(), "newer than the latest version") {
+		t.Errorf("Expected error for newer schema, received %v", err)
+	}
+}
@@ -26,6 +26,7 @@ type NetworkConfig struct {
 	// Chain Network Config
 	ContractDeploymentBlock uint64   // ContractDeploymentBlock is the eth1 block in which the deposit contract is deployed.
 	DepositContractAddress  string   // DepositContractAddress is the address of the deposit contract.
+	DepositChainID          uint64   // DepositChainID is the chain ID of the eth1 network the deposit contract is deployed on.
 	BootstrapNodes          []string // BootstrapNodes are the addresses of the bootnodes.
 }
 
@@ -44,6 +45,7 @@ var defaultNetworkConfig = &NetworkConfig{
 	AttSubnetKey:                      "attnets",
 	ContractDeploymentBlock:           2844925,
 	DepositContractAddress:            "0x0F0F0fc0530007361933EaB5DB97d09aCDD6C1c8",
+	DepositChainID:                    5, // Goerli
 	BootstrapNodes:                    []string{"enr:-Ku4QMKVC_MowDsmEa20d5uGjrChI0h8_KsKXDmgVQbIbngZV0idV6_RL7fEtZGo-kTNZ5o7_EJI_vCPJ6scrhwX0Z4Bh2F0dG5ldHOIAAAAAAAAAACEZXRoMpD1pf1CAAAAAP__________gmlkgnY0gmlwhBLf22SJc2VjcDI1NmsxoQJxCnE6v_x2ekgY_uoE1rtwzvGy40mq9eD66XfHPBWgIIN1ZHCCD6A"},
 }
 
@@ -129,6 +129,9 @@ func NewKVStore(dirPath string, cfg *Config) (*Store, error) {
 	}); err != nil {
 		return nil, err
 	}
+	if err := kv.migrate(); err != nil {
+		return nil, errors.Wrap(err, "could not migrate database schema")
+	}
 
 	return kv, err
 }
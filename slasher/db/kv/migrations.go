@@ -0,0 +1,32 @@
+package kv
+
+import (
+	"path"
+
+	"github.com/prysmaticlabs/prysm/shared/migration"
+)
+
+// migrations upgrade the schema of the slasher database, ordered by version. The schema
+// version is recorded in the chain data bucket. Released migrations must never be
+// modified, schema changes are appended as a new migration instead.
+var migrations = []*migration.Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// Databases created before schema versioning already have the initial schema.
+		Up: func(migration.Tx, []byte) ([]byte, error) {
+			return nil, nil
+		},
+	},
+}
+
+// PendingMigrations returns the schema version of the slasher database in the given
+// directory and the migrations which have not been applied to it, without modifying it.
+func PendingMigrations(dirPath string) (uint64, []*migration.Migration, error) {
+	return migration.PendingInFile(path.Join(dirPath, databaseFileName), chainDataBucket, migrations)
+}
+
+// migrate applies the pending schema migrations to the database.
+func (db *Store) migrate() error {
+	return migration.Run(migration.Bolt(db.db), chainDataBucket, migrations)
+}
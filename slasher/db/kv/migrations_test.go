@@ -0,0 +1,23 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/migration"
+	"github.com/urfave/cli/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_MigratesSchemaOnOpen(t *testing.T) {
+	app := cli.App{}
+	db := setupDB(t, cli.NewContext(&app, nil, nil))
+	latest := migration.Latest(migrations)
+	if err := db.view(func(tx *bolt.Tx) error {
+		if v := migration.Version(migration.BoltTx(tx), chainDataBucket); v != latest {
+			t.Errorf("Expected schema version %d, received %d", latest, v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/prysmaticlabs/prysm/shared/cmd"
+	"github.com/prysmaticlabs/prysm/shared/migration"
+	"github.com/prysmaticlabs/prysm/slasher/db"
+	"github.com/prysmaticlabs/prysm/slasher/db/kv"
+	"github.com/prysmaticlabs/prysm/slasher/node"
+	"github.com/urfave/cli/v2"
+)
+
+var dbCommands = &cli.Command{
+	Name:     "db",
+	Category: "db",
+	Usage:    "defines commands to maintain the slasher database",
+	Subcommands: []*cli.Command{
+		migration.Command(
+			[]cli.Flag{cmd.DataDirFlag},
+			func(cliCtx *cli.Context) (uint64, []*migration.Migration, error) {
+				return kv.PendingMigrations(dbPath(cliCtx))
+			},
+			func(cliCtx *cli.Context) error {
+				// Opening the database applies the pending migrations.
+				d, err := db.NewDB(dbPath(cliCtx), &kv.Config{})
+				if err != nil {
+					return err
+				}
+				return d.Close()
+			},
+		),
+	},
+}
+
+// dbPath returns the path of the slasher database in the data directory.
+func dbPath(cliCtx *cli.Context) string {
+	return filepath.Join(cliCtx.String(cmd.DataDirFlag.Name), node.SlasherDBName)
+}
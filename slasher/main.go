@@ -80,6 +80,7 @@ func main() {
 	app.Usage = `launches an Ethereum Serenity slasher server that interacts with a beacon chain.`
 	app.Version = version.GetVersion()
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{dbCommands}
 	app.Action = startSlasher
 	app.Before = func(ctx *cli.Context) error {
 		// Load any flags from file, if specified.
@@ -33,7 +33,8 @@ import (
 
 var log = logrus.WithField("prefix", "node")
 
-const slasherDBName = "slasherdata"
+// SlasherDBName is the directory of the slasher database within the data directory.
+const SlasherDBName = "slasherdata"
 
 // SlasherNode defines a struct that handles the services running a slashing detector
 // for eth2. It handles the lifecycle of the entire system and registers
@@ -159,7 +160,7 @@ func (s *SlasherNode) startDB() error {
 	baseDir := s.cliCtx.String(cmd.DataDirFlag.Name)
 	clearDB := s.cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearDB := s.cliCtx.Bool(cmd.ForceClearDB.Name)
-	dbPath := path.Join(baseDir, slasherDBName)
+	dbPath := path.Join(baseDir, SlasherDBName)
 	cfg := &kv.Config{}
 	d, err := db.NewDB(dbPath, cfg)
 	if err != nil {
@@ -24,7 +24,7 @@ func main() {
 	defer resetCfg()
 	flag.Parse()
 	fmt.Println("Starting process...")
-	d, err := db.NewDB(*datadir, cache.NewStateSummaryCache())
+	d, err := db.NewDB(*datadir, cache.NewStateSummaryCache(), nil)
 	if err != nil {
 		panic(err)
 	}
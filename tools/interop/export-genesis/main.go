@@ -21,7 +21,7 @@ func main() {
 
 	fmt.Printf("Reading db at %s and writing ssz output to %s.\n", os.Args[1], os.Args[2])
 
-	d, err := db.NewDB(os.Args[1], cache.NewStateSummaryCache())
+	d, err := db.NewDB(os.Args[1], cache.NewStateSummaryCache(), nil)
 	if err != nil {
 		panic(err)
 	}
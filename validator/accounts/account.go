@@ -99,32 +99,46 @@ func NewValidatorAccount(directory string, password string) error {
 	).Info("Keystore generated for validator signatures at path")
 
 	log.Info(`Generating deposit data now, please wait...`)
-	data, depositRoot, err := keystore.DepositInput(validatorKey, shardWithdrawalKey, params.BeaconConfig().MaxEffectiveBalance)
+	txData, err := depositTransactionData(validatorKey, shardWithdrawalKey)
 	if err != nil {
-		return errors.Wrap(err, "unable to generate deposit data")
+		return err
+	}
+	log.Info(`Account creation complete! Copy and paste the raw deposit data shown below when issuing a transaction into the ETH1.0 deposit contract to activate your validator client`)
+	printDepositData(txData)
+	publicKey := validatorKey.PublicKey.Marshal()[:]
+	log.Infof("Public key: %#x", publicKey)
+	return nil
+}
+
+// depositTransactionData returns the data of a transaction to the deposit contract, depositing
+// the max effective balance for the validator key with the given withdrawal key.
+func depositTransactionData(validatorKey *keystore.Key, withdrawalKey *keystore.Key) ([]byte, error) {
+	data, depositRoot, err := keystore.DepositInput(validatorKey, withdrawalKey, params.BeaconConfig().MaxEffectiveBalance)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate deposit data")
 	}
 	testAcc, err := contract.Setup()
 	if err != nil {
-		return errors.Wrap(err, "unable to create simulated backend")
+		return nil, errors.Wrap(err, "unable to create simulated backend")
 	}
 	testAcc.TxOpts.GasLimit = 1000000
 
 	tx, err := testAcc.Contract.Deposit(testAcc.TxOpts, data.PublicKey, data.WithdrawalCredentials, data.Signature, depositRoot)
 	if err != nil {
-		return errors.Wrap(err, "unable to create deposit transaction")
+		return nil, errors.Wrap(err, "unable to create deposit transaction")
 	}
-	log.Info(`Account creation complete! Copy and paste the raw deposit data shown below when issuing a transaction into the ETH1.0 deposit contract to activate your validator client`)
+	return tx.Data(), nil
+}
+
+func printDepositData(txData []byte) {
 	fmt.Printf(`
 ========================Deposit Data=======================
 
 %#x
 
 ===================================================================
-`, tx.Data())
+`, txData)
 	fmt.Println("***Enter the above deposit data into step 3 on https://prylabs.net/participate***")
-	publicKey := validatorKey.PublicKey.Marshal()[:]
-	log.Infof("Public key: %#x", publicKey)
-	return nil
 }
 
 // Exists checks if a validator account at a given keystore path exists.
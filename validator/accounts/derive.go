@@ -0,0 +1,70 @@
+package accounts
+
+import (
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/keystore"
+	"github.com/sirupsen/logrus"
+)
+
+// DerivedAccount holds the keys of a validator derived from a mnemonic along the EIP-2334 paths.
+type DerivedAccount struct {
+	Index         uint64
+	SigningKey    bls.SecretKey
+	WithdrawalKey bls.SecretKey
+}
+
+// DeriveAccounts derives the signing and withdrawal keys of count validators from a mnemonic,
+// starting with the validator at startIndex. The signing key of validator i is derived at
+// m/12381/3600/i/0/0 and its withdrawal key at m/12381/3600/i/0.
+func DeriveAccounts(mnemonic string, mnemonicPassphrase string, startIndex uint64, count uint64) ([]*DerivedAccount, error) {
+	seed, err := bls.SeedFromMnemonic(mnemonic, mnemonicPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]*DerivedAccount, 0, count)
+	for i := startIndex; i < startIndex+count; i++ {
+		signingKey, err := bls.DeriveSecretKeyFromPath(seed, bls.SigningKeyPath(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not derive signing key of validator %d", i)
+		}
+		withdrawalKey, err := bls.DeriveSecretKeyFromPath(seed, bls.WithdrawalKeyPath(i))
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not derive withdrawal key of validator %d", i)
+		}
+		accounts = append(accounts, &DerivedAccount{
+			Index:         i,
+			SigningKey:    signingKey,
+			WithdrawalKey: withdrawalKey,
+		})
+	}
+	return accounts, nil
+}
+
+// PrintDerivedAccounts logs the public keys of derived accounts along with the deposit data
+// needed to activate them.
+func PrintDerivedAccounts(accounts []*DerivedAccount) error {
+	for _, account := range accounts {
+		txData, err := depositTransactionData(
+			&keystore.Key{PublicKey: account.SigningKey.PublicKey(), SecretKey: account.SigningKey},
+			&keystore.Key{PublicKey: account.WithdrawalKey.PublicKey(), SecretKey: account.WithdrawalKey},
+		)
+		if err != nil {
+			return err
+		}
+		log.WithFields(logrus.Fields{
+			"index":            account.Index,
+			"signingKeyPath":   bls.SigningKeyPath(account.Index),
+			"publicKey":        formatPublicKey(account.SigningKey),
+			"withdrawalPubKey": formatPublicKey(account.WithdrawalKey),
+		}).Info("Derived validator account")
+		printDepositData(txData)
+	}
+	return nil
+}
+
+func formatPublicKey(sk bls.SecretKey) string {
+	return "0x" + hex.EncodeToString(sk.PublicKey().Marshal())
+}
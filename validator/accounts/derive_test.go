@@ -0,0 +1,50 @@
+package accounts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+func TestDeriveAccounts(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	derived, err := DeriveAccounts(mnemonic, "", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(derived) != 3 {
+		t.Fatalf("Expected 3 derived accounts, received %d", len(derived))
+	}
+
+	seed, err := bls.SeedFromMnemonic(mnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, account := range derived {
+		if account.Index != uint64(i+2) {
+			t.Errorf("Wanted index %d, received %d", i+2, account.Index)
+		}
+		signingKey, err := bls.DeriveSecretKeyFromPath(seed, bls.SigningKeyPath(account.Index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(account.SigningKey.Marshal(), signingKey.Marshal()) {
+			t.Errorf("Unexpected signing key for account %d", account.Index)
+		}
+		withdrawalKey, err := bls.DeriveSecretKeyFromPath(seed, bls.WithdrawalKeyPath(account.Index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(account.WithdrawalKey.Marshal(), withdrawalKey.Marshal()) {
+			t.Errorf("Unexpected withdrawal key for account %d", account.Index)
+		}
+		if bytes.Equal(account.SigningKey.Marshal(), account.WithdrawalKey.Marshal()) {
+			t.Error("Expected signing and withdrawal keys to differ")
+		}
+	}
+
+	if _, err := DeriveAccounts("abandon about", "", 0, 1); err == nil {
+		t.Error("Expected derivation from an invalid mnemonic to fail")
+	}
+}
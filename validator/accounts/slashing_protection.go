@@ -0,0 +1,102 @@
+package accounts
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/slashing-protection/interchange"
+)
+
+// ExportSlashingProtection writes the slashing protection history of the validator database in
+// dataDir to outputPath, in the EIP-3076 interchange format.
+func ExportSlashingProtection(ctx context.Context, dataDir string, outputPath string, genesisValidatorsRoot string) (err error) {
+	root, err := decodeGenesisValidatorsRoot(genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	store, err := db.GetKVStore(dataDir)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the validator database")
+	}
+	if store == nil {
+		return errors.Errorf("no validator database found in %s", dataDir)
+	}
+	defer func() {
+		if deferErr := store.Close(); deferErr != nil {
+			if err != nil {
+				err = errors.Wrap(err, errFailedToCloseDb.Error())
+			} else {
+				err = errors.Wrap(deferErr, errFailedToCloseDb.Error())
+			}
+		}
+	}()
+
+	f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "could not create %s", outputPath)
+	}
+	if err := interchange.ExportJSON(ctx, store, root, f); err != nil {
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close interchange file")
+		}
+		return errors.Wrap(err, "could not export slashing protection history")
+	}
+	return f.Close()
+}
+
+// ImportSlashingProtection merges the EIP-3076 interchange file at inputPath into the validator
+// database in dataDir, creating the database if it does not exist yet.
+func ImportSlashingProtection(ctx context.Context, dataDir string, inputPath string, genesisValidatorsRoot string) (err error) {
+	root, err := decodeGenesisValidatorsRoot(genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(inputPath)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", inputPath)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil {
+			log.WithError(closeErr).Error("Could not close interchange file")
+		}
+	}()
+	data, err := interchange.Decode(f)
+	if err != nil {
+		return err
+	}
+	pubKeys, err := data.PublicKeys()
+	if err != nil {
+		return err
+	}
+
+	// Opening the database with the public keys of the interchange file initializes their proposal history.
+	store, err := db.NewKVStore(dataDir, pubKeys)
+	if err != nil {
+		return errors.Wrap(err, "failed to open the validator database")
+	}
+	defer func() {
+		if deferErr := store.Close(); deferErr != nil {
+			if err != nil {
+				err = errors.Wrap(err, errFailedToCloseDb.Error())
+			} else {
+				err = errors.Wrap(deferErr, errFailedToCloseDb.Error())
+			}
+		}
+	}()
+	return interchange.Import(ctx, store, root, data)
+}
+
+func decodeGenesisValidatorsRoot(genesisValidatorsRoot string) ([]byte, error) {
+	root, err := hex.DecodeString(strings.TrimPrefix(genesisValidatorsRoot, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not decode genesis validators root")
+	}
+	if len(root) != 32 {
+		return nil, errors.New("genesis validators root is not 32 bytes long")
+	}
+	return root, nil
+}
@@ -0,0 +1,76 @@
+package accounts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+func TestExportImportSlashingProtection(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	genesisValidatorsRoot := fmt.Sprintf("%#x", make([]byte, 32))
+	sourceStore := db.SetupDB(t, [][48]byte{pubKey})
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(1, true)
+	if err := sourceStore.SaveProposalHistoryForEpoch(ctx, pubKey[:], 0, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	if err := sourceStore.Close(); err != nil {
+		t.Fatalf("Closing source store failed: %v", err)
+	}
+
+	dir := filepath.Join(testutil.TempDir(), "slashing-protection")
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Errorf("Could not remove directory: %v", err)
+		}
+	})
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "interchange.json")
+	if err := ExportSlashingProtection(ctx, sourceStore.DatabasePath(), file, genesisValidatorsRoot); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if err := ExportSlashingProtection(ctx, sourceStore.DatabasePath(), file, genesisValidatorsRoot); err == nil {
+		t.Error("Expected export to not overwrite an existing file")
+	}
+
+	targetDirectory := filepath.Join(dir, "target")
+	if err := ImportSlashingProtection(ctx, targetDirectory, file, genesisValidatorsRoot); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	targetStore, err := db.GetKVStore(targetDirectory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := targetStore.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	importedBits, err := targetStore.ProposalHistoryForEpoch(ctx, pubKey[:], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !importedBits.BitAt(1) {
+		t.Error("Expected imported proposal to be marked")
+	}
+}
+
+func TestImportSlashingProtection_InvalidGenesisValidatorsRoot(t *testing.T) {
+	want := "not 32 bytes long"
+	err := ImportSlashingProtection(context.Background(), testutil.TempDir(), "interchange.json", "0x01")
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error %q, received %v", want, err)
+	}
+}
@@ -1,4 +1,4 @@
-package streaming
+package client
 
 import (
 	"context"
@@ -7,13 +7,12 @@ import (
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
-	"go.opencensus.io/trace"
-
 	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
 	"github.com/prysmaticlabs/prysm/validator/client/metrics"
+	"go.opencensus.io/trace"
 )
 
 // SubmitAggregateAndProof submits the validator's signed slot signature to the beacon node
@@ -0,0 +1,121 @@
+// Package doppelganger protects validators from being slashed because another validator client,
+// their doppelganger, is already signing with the same keys. Before performing any duty, the
+// validator client watches the attestations and blocks of its keys for a number of epochs, and
+// refuses to start if any of its keys is live on the network.
+package doppelganger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "doppelganger")
+
+// farFutureIndex is the validator index of the votes of public keys unknown to the beacon node.
+const farFutureIndex = ^uint64(0)
+
+// ErrDoppelganger is returned when a validating key is live on the network.
+var ErrDoppelganger = errors.New("validating key is live on the network, another validator client is likely signing with it")
+
+// Check watches the attestations and blocks of the public keys through the beacon node, from the
+// epoch after the first slot received from next slot, as duties of the current epoch may have been
+// performed by this validator client before it restarted. It returns once the keys were not live
+// for the given number of epochs, or ErrDoppelganger as soon as one of them is.
+func Check(ctx context.Context, beaconClient ethpb.BeaconChainClient, pubKeys [][48]byte, nextSlot <-chan uint64, epochs uint64) error {
+	if epochs == 0 || len(pubKeys) == 0 {
+		return nil
+	}
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	var lastEpoch, next uint64
+	started := false
+	for {
+		var slot uint64
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case slot = <-nextSlot:
+		}
+		epoch := slot / slotsPerEpoch
+		if !started {
+			started = true
+			next, lastEpoch = epoch+1, epoch+epochs
+			log.WithFields(logrus.Fields{
+				"epochs":     epochs,
+				"validators": len(pubKeys),
+			}).Info("Waiting for the validating keys not to be live on the network before performing duties")
+			continue
+		}
+		// An epoch is complete once the next one starts. It is checked one slot later, so that the
+		// beacon node agrees that the next epoch started.
+		for next+1 < epoch || (next+1 == epoch && slot%slotsPerEpoch != 0) {
+			if err := checkEpoch(ctx, beaconClient, pubKeys, next); err != nil {
+				return err
+			}
+			if next >= lastEpoch {
+				log.WithField("epochs", epochs).Info("Validating keys were not live on the network, performing duties")
+				return nil
+			}
+			next++
+		}
+	}
+}
+
+// checkEpoch returns ErrDoppelganger if one of the public keys attested or proposed a block in the
+// epoch, which must be the previous epoch.
+func checkEpoch(ctx context.Context, beaconClient ethpb.BeaconChainClient, pubKeys [][48]byte, epoch uint64) error {
+	// The votes of an epoch are those of the previous epoch of the state at the start of the next one.
+	votes, err := beaconClient.GetIndividualVotes(ctx, &ethpb.IndividualVotesRequest{
+		Epoch:      epoch + 1,
+		PublicKeys: bytesutil.FromBytes48Array(pubKeys),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "could not get votes of epoch %d", epoch)
+	}
+	indices := make(map[uint64][]byte, len(votes.IndividualVotes))
+	for _, vote := range votes.IndividualVotes {
+		if vote.ValidatorIndex == farFutureIndex {
+			continue
+		}
+		indices[vote.ValidatorIndex] = vote.PublicKey
+		if vote.IsPreviousEpochAttester {
+			return live(vote.PublicKey, epoch, "attested")
+		}
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+
+	req := &ethpb.ListBlocksRequest{QueryFilter: &ethpb.ListBlocksRequest_Epoch{Epoch: epoch}}
+	for {
+		blocks, err := beaconClient.ListBlocks(ctx, req)
+		if err != nil {
+			return errors.Wrapf(err, "could not list blocks of epoch %d", epoch)
+		}
+		for _, container := range blocks.BlockContainers {
+			if container.Block == nil || container.Block.Block == nil {
+				continue
+			}
+			if pubKey, ok := indices[container.Block.Block.ProposerIndex]; ok {
+				return live(pubKey, epoch, "proposed a block")
+			}
+		}
+		if blocks.NextPageToken == "" || len(blocks.BlockContainers) == 0 {
+			return nil
+		}
+		req.PageToken = blocks.NextPageToken
+	}
+}
+
+func live(pubKey []byte, epoch uint64, action string) error {
+	log.WithFields(logrus.Fields{
+		"pubKey": fmt.Sprintf("%#x", pubKey),
+		"epoch":  epoch,
+	}).Errorf("Validating key %s while this validator client was not performing duties", action)
+	return errors.Wrapf(ErrDoppelganger, "public key %#x %s in epoch %d", pubKey, action, epoch)
+}
@@ -0,0 +1,113 @@
+package doppelganger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"google.golang.org/grpc"
+)
+
+// beaconChainClient serves the votes and blocks of the epochs in which a validator was live.
+type beaconChainClient struct {
+	ethpb.BeaconChainClient
+	attested      map[uint64]bool
+	proposed      map[uint64]bool
+	checkedEpochs []uint64
+}
+
+func (c *beaconChainClient) GetIndividualVotes(_ context.Context, req *ethpb.IndividualVotesRequest, _ ...grpc.CallOption) (*ethpb.IndividualVotesRespond, error) {
+	epoch := req.Epoch - 1
+	c.checkedEpochs = append(c.checkedEpochs, epoch)
+	return &ethpb.IndividualVotesRespond{
+		IndividualVotes: []*ethpb.IndividualVotesRespond_IndividualVote{
+			{PublicKey: req.PublicKeys[0], ValidatorIndex: 7, IsPreviousEpochAttester: c.attested[epoch]},
+			{PublicKey: []byte("unknown"), ValidatorIndex: farFutureIndex},
+		},
+	}, nil
+}
+
+func (c *beaconChainClient) ListBlocks(_ context.Context, req *ethpb.ListBlocksRequest, _ ...grpc.CallOption) (*ethpb.ListBlocksResponse, error) {
+	epoch := req.QueryFilter.(*ethpb.ListBlocksRequest_Epoch).Epoch
+	proposer := uint64(1)
+	if c.proposed[epoch] {
+		proposer = 7
+	}
+	return &ethpb.ListBlocksResponse{
+		BlockContainers: []*ethpb.BeaconBlockContainer{
+			{Block: &ethpb.SignedBeaconBlock{Block: &ethpb.BeaconBlock{ProposerIndex: proposer}}},
+		},
+	}, nil
+}
+
+func slots(values ...uint64) <-chan uint64 {
+	ch := make(chan uint64, len(values))
+	for _, slot := range values {
+		ch <- slot
+	}
+	return ch
+}
+
+func TestCheck(t *testing.T) {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	pubKeys := [][48]byte{{1}}
+	// The validator client starts in epoch 2, and misses the slots of epoch 3 along with the first
+	// slots of epoch 4, so that epoch 3 is checked late.
+	nextSlot := func() <-chan uint64 {
+		return slots(2*slotsPerEpoch+5, 4*slotsPerEpoch+2, 5*slotsPerEpoch+1)
+	}
+
+	tests := []struct {
+		name    string
+		client  *beaconChainClient
+		err     error
+		checked []uint64
+	}{
+		{
+			name:    "NotLive",
+			client:  &beaconChainClient{},
+			checked: []uint64{3, 4},
+		},
+		{
+			name:    "AttestedInCurrentEpoch",
+			client:  &beaconChainClient{attested: map[uint64]bool{2: true}},
+			checked: []uint64{3, 4},
+		},
+		{
+			name:    "Attested",
+			client:  &beaconChainClient{attested: map[uint64]bool{4: true}},
+			err:     ErrDoppelganger,
+			checked: []uint64{3, 4},
+		},
+		{
+			name:    "Proposed",
+			client:  &beaconChainClient{proposed: map[uint64]bool{3: true}},
+			err:     ErrDoppelganger,
+			checked: []uint64{3},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := Check(context.Background(), test.client, pubKeys, nextSlot(), 2)
+			if errors.Cause(err) != test.err {
+				t.Errorf("Expected error %v, received %v", test.err, err)
+			}
+			if len(test.client.checkedEpochs) != len(test.checked) {
+				t.Fatalf("Expected epochs %v to be checked, checked %v", test.checked, test.client.checkedEpochs)
+			}
+			for i, epoch := range test.checked {
+				if test.client.checkedEpochs[i] != epoch {
+					t.Errorf("Expected epochs %v to be checked, checked %v", test.checked, test.client.checkedEpochs)
+				}
+			}
+		})
+	}
+}
+
+func TestCheck_Disabled(t *testing.T) {
+	if err := Check(context.Background(), &beaconChainClient{}, [][48]byte{{1}}, nil, 0); err != nil {
+		t.Errorf("Expected no check without doppelganger epochs, received %v", err)
+	}
+}
@@ -1,23 +1,113 @@
-package streaming
+package client
 
 import (
 	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/beacon-chain/core/helpers"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"go.opencensus.io/trace"
 )
 
-// StreamDuties consumes a server-side stream of validator duties from a beacon node
-// for a set of validating keys passed in as a request type. New duties will be
-// sent over the stream upon a new epoch being reached or from a a chain reorg happening
-// across epochs in the beacon node.
-func (v *validator) StreamDuties(ctx context.Context) error {
+// dutiesSource keeps the duties of the validating keys of a validator up to date
+// by requesting them from the beacon node.
+type dutiesSource interface {
+	// updateDuties is called at the start of every slot. It returns an error if the
+	// duties of the epoch of the slot are unknown and could not be requested.
+	updateDuties(ctx context.Context, v *validator, slot uint64) error
+}
+
+// newDutiesSource returns the source of the validator duties, which streams them
+// with --enable-stream-duties and polls them otherwise.
+func newDutiesSource() dutiesSource {
+	if featureconfig.Get().EnableStreamDuties {
+		return &streamingDuties{}
+	}
+	return &pollingDuties{}
+}
+
+// UpdateDuties updates the validator's list of upcoming assignments from its
+// duties source.
+func (v *validator) UpdateDuties(ctx context.Context, slot uint64) error {
+	return v.dutiesSource.updateDuties(ctx, v, slot)
+}
+
+// pollingDuties requests the validator duties from the beacon node at the start
+// of every epoch.
+type pollingDuties struct{}
+
+// updateDuties checks the slot number to determine if the validator's
+// list of upcoming assignments needs to be updated. For example, at the
+// beginning of a new epoch.
+func (*pollingDuties) updateDuties(ctx context.Context, v *validator, slot uint64) error {
+	epoch := slot / params.BeaconConfig().SlotsPerEpoch
+	if slot%params.BeaconConfig().SlotsPerEpoch != 0 && v.hasDuties(epoch) {
+		// Do nothing if not epoch start AND assignments already exist.
+		return nil
+	}
+	// Set deadline to end of epoch.
+	ctx, cancel := context.WithDeadline(ctx, v.SlotDeadline(helpers.StartSlot(epoch+1)))
+	defer cancel()
+	ctx, span := trace.StartSpan(ctx, "validator.UpdateAssignments")
+	defer span.End()
+
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return err
+	}
+	req := &ethpb.DutiesRequest{
+		Epoch:      epoch,
+		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
+	}
+	resp, err := v.validatorClient.GetDuties(ctx, req)
+	if err != nil {
+		v.setDuties(epoch, nil) // Clear assignments so we know to retry the request.
+		log.Error(err)
+		return err
+	}
+	v.setDuties(epoch, resp)
+	return v.requestSubnetSubscriptions(ctx, resp, len(validatingKeys))
+}
+
+// streamingDuties consumes a server-side stream of validator duties from the
+// beacon node in the background. New duties are sent over the stream upon a new
+// epoch being reached or from a chain reorg happening across epochs in the beacon
+// node.
+type streamingDuties struct {
+	lock      sync.Mutex
+	streaming bool
+}
+
+// updateDuties starts streaming the validator duties, unless they are already
+// being streamed. A stream which ended is restarted at the next slot.
+func (s *streamingDuties) updateDuties(ctx context.Context, v *validator, _ uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.streaming {
+		return nil
+	}
+	s.streaming = true
+	go func() {
+		if err := s.streamDuties(ctx, v); err != nil && ctx.Err() == nil {
+			handleAssignmentError(err, v.CurrentSlot())
+		}
+		s.lock.Lock()
+		s.streaming = false
+		s.lock.Unlock()
+	}()
+	return nil
+}
+
+// streamDuties receives the duties of the validating keys until the stream is
+// closed.
+func (*streamingDuties) streamDuties(ctx context.Context, v *validator) error {
 	ctx, span := trace.StartSpan(ctx, "validator.StreamDuties")
 	defer span.End()
 
@@ -25,7 +115,6 @@ func (v *validator) StreamDuties(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
-	numValidatingKeys := len(validatingKeys)
 	req := &ethpb.DutiesRequest{
 		PublicKeys: bytesutil.FromBytes48Array(validatingKeys),
 	}
@@ -48,14 +137,37 @@ func (v *validator) StreamDuties(ctx context.Context) error {
 		}
 		// Updates validator duties and requests the beacon node to subscribe
 		// to attestation subnets in advance.
-		v.updateDuties(ctx, res, numValidatingKeys)
-		if err := v.requestSubnetSubscriptions(ctx, res, numValidatingKeys); err != nil {
+		v.setDuties(v.CurrentSlot()/params.BeaconConfig().SlotsPerEpoch, res)
+		if err := v.requestSubnetSubscriptions(ctx, res, len(validatingKeys)); err != nil {
 			log.WithError(err).Error("Could not request beacon node to subscribe to subnets")
 		}
 	}
 	return nil
 }
 
+// setDuties sets the received validator duties of the epoch and of the next one
+// in-memory for the validator client. Nil duties clear the assignments.
+func (v *validator) setDuties(epoch uint64, dutiesResp *ethpb.DutiesResponse) {
+	v.dutiesLock.Lock()
+	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty, 2)
+	if dutiesResp != nil {
+		v.dutiesByEpoch[epoch] = dutiesResp.CurrentEpochDuties
+		v.dutiesByEpoch[epoch+1] = dutiesResp.NextEpochDuties
+	}
+	v.dutiesLock.Unlock()
+	if dutiesResp != nil {
+		v.logDuties(helpers.StartSlot(epoch), dutiesResp.CurrentEpochDuties)
+	}
+}
+
+// hasDuties returns whether the validator duties of the epoch are known.
+func (v *validator) hasDuties(epoch uint64) bool {
+	v.dutiesLock.RLock()
+	defer v.dutiesLock.RUnlock()
+	_, ok := v.dutiesByEpoch[epoch]
+	return ok
+}
+
 // RolesAt slot returns the validator roles at the given slot. Returns nil if the
 // validator is known to not have a roles at the at slot. Returns UNKNOWN if the
 // validator assignments are unknown. Otherwise returns a valid validatorRole map.
@@ -107,26 +219,6 @@ func (v *validator) RolesAt(ctx context.Context, slot uint64) (map[[48]byte][]va
 	return rolesAt, nil
 }
 
-// Update duties sets the received validator duties in-memory for the validator client
-// and determines which validating keys were selected as attestation aggregators
-// for the epoch. Additionally, this function uses that information to notify
-// the beacon node it should subscribe the assigned attestation p2p subnets.
-func (v *validator) updateDuties(ctx context.Context, dutiesResp *ethpb.DutiesResponse, numKeys int) {
-	ctx, span := trace.StartSpan(ctx, "validator.updateDuties")
-	defer span.End()
-	currentSlot := v.CurrentSlot()
-	currentEpoch := currentSlot / params.BeaconConfig().SlotsPerEpoch
-
-	v.dutiesLock.Lock()
-	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty, 2)
-	v.dutiesByEpoch[currentEpoch] = dutiesResp.CurrentEpochDuties
-	v.dutiesByEpoch[currentEpoch+1] = dutiesResp.NextEpochDuties
-	v.dutiesLock.Unlock()
-
-	v.logDuties(currentSlot, dutiesResp.CurrentEpochDuties)
-	v.logDuties(currentSlot+params.BeaconConfig().SlotsPerEpoch, dutiesResp.NextEpochDuties)
-}
-
 // Given the validator public key and an epoch, this gets the validator assignment.
 func (v *validator) duty(pubKey [48]byte, epoch uint64) (*ethpb.DutiesResponse_Duty, error) {
 	v.dutiesLock.RLock()
@@ -143,37 +235,19 @@ func (v *validator) duty(pubKey [48]byte, epoch uint64) (*ethpb.DutiesResponse_D
 	return nil, fmt.Errorf("pubkey %#x not in duties", bytesutil.Trunc(pubKey[:]))
 }
 
+// requestSubnetSubscriptions determines which validating keys were selected as
+// attestation aggregators in the current and next epochs, and notifies the beacon
+// node it should subscribe to the assigned attestation p2p subnets in advance.
 func (v *validator) requestSubnetSubscriptions(ctx context.Context, dutiesResp *ethpb.DutiesResponse, numKeys int) error {
 	subscribeSlots := make([]uint64, 0, numKeys)
 	subscribeCommitteeIDs := make([]uint64, 0, numKeys)
 	subscribeIsAggregator := make([]bool, 0, numKeys)
 	alreadySubscribed := make(map[[64]byte]bool)
-	for _, duty := range dutiesResp.CurrentEpochDuties {
-		if duty.Status == ethpb.ValidatorStatus_ACTIVE || duty.Status == ethpb.ValidatorStatus_EXITING {
-			attesterSlot := duty.AttesterSlot
-			committeeIndex := duty.CommitteeIndex
-
-			alreadySubscribedKey := validatorSubscribeKey(attesterSlot, committeeIndex)
-			if _, ok := alreadySubscribed[alreadySubscribedKey]; ok {
+	for _, duties := range [][]*ethpb.DutiesResponse_Duty{dutiesResp.CurrentEpochDuties, dutiesResp.NextEpochDuties} {
+		for _, duty := range duties {
+			if duty.Status != ethpb.ValidatorStatus_ACTIVE && duty.Status != ethpb.ValidatorStatus_EXITING {
 				continue
 			}
-
-			aggregator, err := v.isAggregator(ctx, duty.Committee, attesterSlot, bytesutil.ToBytes48(duty.PublicKey))
-			if err != nil {
-				return errors.Wrap(err, "could not check if a validator is an aggregator")
-			}
-			if aggregator {
-				alreadySubscribed[alreadySubscribedKey] = true
-			}
-
-			subscribeSlots = append(subscribeSlots, attesterSlot)
-			subscribeCommitteeIDs = append(subscribeCommitteeIDs, committeeIndex)
-			subscribeIsAggregator = append(subscribeIsAggregator, aggregator)
-		}
-	}
-
-	for _, duty := range dutiesResp.NextEpochDuties {
-		if duty.Status == ethpb.ValidatorStatus_ACTIVE || duty.Status == ethpb.ValidatorStatus_EXITING {
 			attesterSlot := duty.AttesterSlot
 			committeeIndex := duty.CommitteeIndex
 
@@ -0,0 +1,312 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/featureconfig"
+	"github.com/prysmaticlabs/prysm/shared/mock"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+func TestNewDutiesSource(t *testing.T) {
+	if _, ok := newDutiesSource().(*pollingDuties); !ok {
+		t.Error("Expected duties to be polled by default")
+	}
+	reset := featureconfig.InitWithReset(&featureconfig.Flags{EnableStreamDuties: true})
+	defer reset()
+	if _, ok := newDutiesSource().(*streamingDuties); !ok {
+		t.Error("Expected duties to be streamed with --enable-stream-duties")
+	}
+}
+
+func TestPollingDuties_DoesNothingWhenNotEpochStart_AlreadyExistingAssignments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	slot := uint64(1)
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+		dutiesSource:    &pollingDuties{},
+	}
+	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty)
+	v.dutiesByEpoch[0] = []*ethpb.DutiesResponse_Duty{
+		{
+			Committee:      []uint64{},
+			AttesterSlot:   10,
+			CommitteeIndex: 20,
+		},
+	}
+	client.EXPECT().GetDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Times(0)
+
+	if err := v.UpdateDuties(context.Background(), slot); err != nil {
+		t.Errorf("Could not update assignments: %v", err)
+	}
+}
+
+func TestPollingDuties_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+		dutiesSource:    &pollingDuties{},
+	}
+	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty)
+	v.dutiesByEpoch[1] = []*ethpb.DutiesResponse_Duty{
+		{
+			CommitteeIndex: 1,
+		},
+	}
+
+	expected := errors.New("bad")
+
+	client.EXPECT().GetDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, expected)
+
+	if err := v.UpdateDuties(context.Background(), params.BeaconConfig().SlotsPerEpoch); err != expected {
+		t.Errorf("Bad error; want=%v got=%v", expected, err)
+	}
+	if len(v.dutiesByEpoch) != 0 {
+		t.Error("Assignments should have been cleared on failure")
+	}
+}
+
+func TestPollingDuties_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	slot := params.BeaconConfig().SlotsPerEpoch
+	resp := &ethpb.DutiesResponse{
+		CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{
+				AttesterSlot:   params.BeaconConfig().SlotsPerEpoch,
+				ValidatorIndex: 200,
+				CommitteeIndex: 100,
+				Committee:      []uint64{0, 1, 2, 3},
+				PublicKey:      []byte("testPubKey_1"),
+				ProposerSlots:  []uint64{params.BeaconConfig().SlotsPerEpoch + 1},
+			},
+		},
+		NextEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{
+				AttesterSlot:   2 * params.BeaconConfig().SlotsPerEpoch,
+				ValidatorIndex: 200,
+				CommitteeIndex: 101,
+				Committee:      []uint64{0, 1, 2, 3},
+				PublicKey:      []byte("testPubKey_1"),
+			},
+		},
+	}
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+		dutiesSource:    &pollingDuties{},
+	}
+	client.EXPECT().GetDuties(
+		gomock.Any(),
+		&ethpb.DutiesRequest{
+			Epoch:      1,
+			PublicKeys: [][]byte{validatorPubKey[:]},
+		},
+	).Return(resp, nil)
+
+	client.EXPECT().SubscribeCommitteeSubnets(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, nil)
+
+	if err := v.UpdateDuties(context.Background(), slot); err != nil {
+		t.Fatalf("Could not update assignments: %v", err)
+	}
+	if v.dutiesByEpoch[1][0].ProposerSlots[0] != params.BeaconConfig().SlotsPerEpoch+1 {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			params.BeaconConfig().SlotsPerEpoch+1,
+			v.dutiesByEpoch[1][0].ProposerSlots[0],
+		)
+	}
+	if v.dutiesByEpoch[1][0].CommitteeIndex != resp.CurrentEpochDuties[0].CommitteeIndex {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			resp.CurrentEpochDuties[0].CommitteeIndex,
+			v.dutiesByEpoch[1][0].CommitteeIndex,
+		)
+	}
+	if v.dutiesByEpoch[2][0].CommitteeIndex != resp.NextEpochDuties[0].CommitteeIndex {
+		t.Errorf(
+			"Unexpected next epoch assignments. want=%v got=%v",
+			resp.NextEpochDuties[0].CommitteeIndex,
+			v.dutiesByEpoch[2][0].CommitteeIndex,
+		)
+	}
+}
+
+func TestStreamingDuties_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+	}
+	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty)
+	v.dutiesByEpoch[0] = []*ethpb.DutiesResponse_Duty{
+		{
+			CommitteeIndex: 1,
+		},
+	}
+
+	expected := errors.New("bad")
+
+	client.EXPECT().StreamDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, expected)
+
+	if err := (&streamingDuties{}).streamDuties(context.Background(), &v); !strings.Contains(err.Error(), "bad") {
+		t.Errorf("Bad error; want=%v got=%v", expected, err)
+	}
+}
+
+func TestStreamingDuties_OK(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	resp := &ethpb.DutiesResponse{
+		CurrentEpochDuties: []*ethpb.DutiesResponse_Duty{
+			{
+				AttesterSlot:   params.BeaconConfig().SlotsPerEpoch,
+				ValidatorIndex: 200,
+				CommitteeIndex: 100,
+				Committee:      []uint64{0, 1, 2, 3},
+				PublicKey:      []byte("testPubKey_1"),
+				ProposerSlots:  []uint64{params.BeaconConfig().SlotsPerEpoch + 1},
+			},
+			{
+				AttesterSlot:   params.BeaconConfig().SlotsPerEpoch,
+				ValidatorIndex: 201,
+				CommitteeIndex: 101,
+				Committee:      []uint64{0, 1, 2, 3},
+				PublicKey:      []byte("testPubKey_2"),
+				ProposerSlots:  []uint64{params.BeaconConfig().SlotsPerEpoch + 2},
+			},
+		},
+	}
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+	}
+	v.genesisTime = uint64(time.Now().Unix()) + 500
+	v.dutiesByEpoch = make(map[uint64][]*ethpb.DutiesResponse_Duty)
+	stream := mock.NewMockBeaconNodeValidator_StreamDutiesClient(ctrl)
+	client.EXPECT().StreamDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(stream, nil)
+	ctx := context.Background()
+	stream.EXPECT().Context().Return(ctx).AnyTimes()
+	stream.EXPECT().Recv().Return(
+		resp,
+		nil,
+	)
+
+	client.EXPECT().SubscribeCommitteeSubnets(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(nil, nil)
+
+	stream.EXPECT().Recv().Return(
+		nil,
+		io.EOF,
+	)
+
+	if err := (&streamingDuties{}).streamDuties(ctx, &v); err != nil {
+		t.Fatalf("Could not update assignments: %v", err)
+	}
+	if v.dutiesByEpoch[0][0].ProposerSlots[0] != params.BeaconConfig().SlotsPerEpoch+1 {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			params.BeaconConfig().SlotsPerEpoch+1,
+			v.dutiesByEpoch[0][0].ProposerSlots[0],
+		)
+	}
+	if v.dutiesByEpoch[0][0].AttesterSlot != params.BeaconConfig().SlotsPerEpoch {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			params.BeaconConfig().SlotsPerEpoch,
+			v.dutiesByEpoch[0][0].AttesterSlot,
+		)
+	}
+	if v.dutiesByEpoch[0][0].CommitteeIndex != resp.CurrentEpochDuties[0].CommitteeIndex {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			resp.CurrentEpochDuties[0].CommitteeIndex,
+			v.dutiesByEpoch[0][0].CommitteeIndex,
+		)
+	}
+	if v.dutiesByEpoch[0][0].ValidatorIndex != resp.CurrentEpochDuties[0].ValidatorIndex {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			resp.CurrentEpochDuties[0].ValidatorIndex,
+			v.dutiesByEpoch[0][0].ValidatorIndex,
+		)
+	}
+	if v.dutiesByEpoch[0][1].ValidatorIndex != resp.CurrentEpochDuties[1].ValidatorIndex {
+		t.Errorf(
+			"Unexpected validator assignments. want=%v got=%v",
+			resp.CurrentEpochDuties[1].ValidatorIndex,
+			v.dutiesByEpoch[0][1].ValidatorIndex,
+		)
+	}
+}
+
+func TestStreamingDuties_StreamsOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	client := mock.NewMockBeaconNodeValidatorClient(ctrl)
+
+	v := validator{
+		keyManager:      testKeyManager,
+		validatorClient: client,
+		dutiesSource:    &streamingDuties{},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := mock.NewMockBeaconNodeValidator_StreamDutiesClient(ctrl)
+	client.EXPECT().StreamDuties(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(stream, nil).Times(1)
+	receiving := make(chan struct{})
+	stream.EXPECT().Recv().DoAndReturn(func() (*ethpb.DutiesResponse, error) {
+		close(receiving)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	for slot := uint64(0); slot < 3; slot++ {
+		if err := v.UpdateDuties(ctx, slot); err != nil {
+			t.Fatalf("Could not update assignments: %v", err)
+		}
+	}
+	<-receiving
+}
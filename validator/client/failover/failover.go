@@ -0,0 +1,371 @@
+// Package failover connects the validator client to several beacon nodes, and sends its
+// requests to a healthy one among them. The beacon nodes are health checked periodically, and
+// the requests fail over to another beacon node whenever the current one goes down, is syncing
+// or falls behind the others.
+package failover
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ptypes "github.com/gogo/protobuf/types"
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logrus.WithField("prefix", "failover")
+
+var (
+	// healthCheckTimeout is the timeout of the health check of a beacon node.
+	healthCheckTimeout = 5 * time.Second
+	// maxHeadSlotLag is the number of slots a beacon node may be behind the highest head slot of
+	// the beacon nodes, and still be healthy.
+	maxHeadSlotLag uint64 = 2
+	// probe returns the sync status and the head slot of the beacon node of the connection.
+	probe = probeBeaconNode
+	// Metrics
+	beaconNodeHealthy = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_healthy",
+			Help:      "Whether the beacon node is healthy: 1 if it is, 0 if it is not.",
+		},
+		[]string{
+			// Beacon node endpoint.
+			"endpoint",
+		},
+	)
+	beaconNodeFailovers = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "validator",
+			Name:      "beacon_node_failovers",
+			Help:      "The number of times the requests failed over to another beacon node.",
+		},
+	)
+)
+
+// node is a beacon node of the pool.
+type node struct {
+	endpoint string
+	conn     *grpc.ClientConn
+	// err is the error of the last health check or request of the beacon node, if it failed.
+	err      error
+	syncing  bool
+	headSlot uint64
+	latency  time.Duration
+	healthy  bool
+}
+
+// reason returns why the beacon node is not healthy.
+func (n *node) reason() string {
+	switch {
+	case n.err != nil:
+		return n.err.Error()
+	case n.syncing:
+		return "syncing"
+	default:
+		return "behind the other beacon nodes"
+	}
+}
+
+// Pool is a connection to several beacon nodes, which sends the requests to the current
+// healthy beacon node. The order of the endpoints is the order of preference of the beacon
+// nodes, until they are health checked.
+type Pool struct {
+	conn    *grpc.ClientConn
+	nodes   []*node
+	current *node
+	lock    sync.RWMutex
+	cancel  context.CancelFunc
+}
+
+// ParseEndpoints returns the endpoints of a comma separated list of beacon node endpoints.
+func ParseEndpoints(endpoints string) []string {
+	var parsed []string
+	for _, endpoint := range strings.Split(endpoints, ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			parsed = append(parsed, endpoint)
+		}
+	}
+	return parsed
+}
+
+// Dial the beacon nodes of the endpoints with the dial options. With a single endpoint, the
+// connection of the pool is the connection to its beacon node, and it is not health checked.
+func Dial(ctx context.Context, endpoints []string, dialOpts ...grpc.DialOption) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("no beacon node endpoint")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{cancel: cancel}
+	for _, endpoint := range endpoints {
+		conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
+		if err != nil {
+			p.closeAfterFailure()
+			return nil, errors.Wrapf(err, "could not dial endpoint %s", endpoint)
+		}
+		p.nodes = append(p.nodes, &node{endpoint: endpoint, conn: conn, healthy: true})
+	}
+	p.current = p.nodes[0]
+	if len(p.nodes) == 1 {
+		p.conn = p.current.conn
+		return p, nil
+	}
+
+	// The requests of the connection of the pool are intercepted, and sent on the connection of
+	// the current beacon node instead, with its own interceptors. So the failover interceptors
+	// replace the interceptors of the dial options.
+	opts := make([]grpc.DialOption, 0, len(dialOpts)+2)
+	opts = append(opts, dialOpts...)
+	opts = append(opts, grpc.WithUnaryInterceptor(p.unaryInterceptor), grpc.WithStreamInterceptor(p.streamInterceptor))
+	conn, err := grpc.DialContext(ctx, endpoints[0], opts...)
+	if err != nil {
+		p.closeAfterFailure()
+		return nil, errors.Wrap(err, "could not dial beacon nodes")
+	}
+	p.conn = conn
+	go p.run(ctx)
+	return p, nil
+}
+
+// Conn returns the connection to the beacon nodes.
+func (p *Pool) Conn() *grpc.ClientConn {
+	return p.conn
+}
+
+// Endpoint returns the endpoint of the current beacon node.
+func (p *Pool) Endpoint() string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.current.endpoint
+}
+
+// Close stops the health checks, and closes the connections to the beacon nodes.
+func (p *Pool) Close() error {
+	p.cancel()
+	var firstErr error
+	if p.conn != nil && len(p.nodes) > 1 {
+		firstErr = p.conn.Close()
+	}
+	for _, n := range p.nodes {
+		if err := n.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (p *Pool) closeAfterFailure() {
+	if err := p.Close(); err != nil {
+		log.WithError(err).Error("Could not close beacon node connections")
+	}
+}
+
+// run health checks the beacon nodes twice per slot, until the context is canceled.
+func (p *Pool) run(ctx context.Context) {
+	ticker := time.NewTicker(slotutil.DivideSlotBy(2 /* twice per slot */))
+	defer ticker.Stop()
+	for {
+		p.checkHealth(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// checkHealth health checks the beacon nodes concurrently, and fails over to a healthy beacon
+// node if the current one is not.
+func (p *Pool) checkHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, n := range p.nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			syncing, headSlot, err := probe(ctx, n.conn)
+			latency := time.Since(start)
+
+			p.lock.Lock()
+			defer p.lock.Unlock()
+			n.err, n.syncing, n.headSlot, n.latency = err, syncing, headSlot, latency
+		}(n)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.selectNode()
+}
+
+// selectNode updates the health of the beacon nodes, and switches to the healthy beacon node
+// with the lowest latency if the current one is not healthy. A beacon node is healthy if it
+// is reachable, synced, and its head is at most max head slot lag slots behind the highest
+// head of the beacon nodes. The caller must hold the lock.
+func (p *Pool) selectNode() {
+	var highestSlot uint64
+	for _, n := range p.nodes {
+		if n.err == nil && !n.syncing && n.headSlot > highestSlot {
+			highestSlot = n.headSlot
+		}
+	}
+	var next *node
+	for _, n := range p.nodes {
+		n.healthy = n.err == nil && !n.syncing && n.headSlot+maxHeadSlotLag >= highestSlot
+		if n.healthy {
+			beaconNodeHealthy.WithLabelValues(n.endpoint).Set(1)
+			if next == nil || n.latency < next.latency {
+				next = n
+			}
+		} else {
+			beaconNodeHealthy.WithLabelValues(n.endpoint).Set(0)
+		}
+	}
+	if p.current.healthy {
+		return
+	}
+	if next == nil {
+		log.WithField("endpoint", p.current.endpoint).Warn("No healthy beacon node to fail over to")
+		return
+	}
+	p.switchTo(next)
+}
+
+// switchTo makes the beacon node the current one. The caller must hold the lock.
+func (p *Pool) switchTo(n *node) {
+	log.WithFields(logrus.Fields{
+		"from":   p.current.endpoint,
+		"to":     n.endpoint,
+		"reason": p.current.reason(),
+	}).Warn("Failing over to another beacon node")
+	beaconNodeFailovers.Inc()
+	p.current = n
+}
+
+// candidates returns the beacon nodes to send a request to, in order: the current beacon node,
+// then the other healthy beacon nodes by latency, then the beacon nodes which are not healthy.
+func (p *Pool) candidates() []*node {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	candidates := make([]*node, 0, len(p.nodes))
+	for _, n := range p.nodes {
+		if n != p.current {
+			candidates = append(candidates, n)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].healthy != candidates[j].healthy {
+			return candidates[i].healthy
+		}
+		return candidates[i].healthy && candidates[i].latency < candidates[j].latency
+	})
+	return append([]*node{p.current}, candidates...)
+}
+
+// failed marks the beacon node as not healthy if the request failed because it is unavailable,
+// and returns true if the request should fail over to another beacon node.
+func (p *Pool) failed(ctx context.Context, n *node, err error) bool {
+	if err == nil || ctx.Err() != nil || status.Code(err) != codes.Unavailable {
+		return false
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	n.err = err
+	n.healthy = false
+	beaconNodeHealthy.WithLabelValues(n.endpoint).Set(0)
+	return true
+}
+
+// succeeded makes the beacon node the current one, once a request failed over to it.
+func (p *Pool) succeeded(n *node) {
+	p.lock.RLock()
+	current := p.current
+	p.lock.RUnlock()
+	if n == current {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if n != p.current {
+		p.switchTo(n)
+	}
+}
+
+// unaryInterceptor sends the request to the current beacon node, and fails over to the other
+// beacon nodes in turn while they are unavailable.
+func (p *Pool) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req, reply interface{},
+	_ *grpc.ClientConn,
+	_ grpc.UnaryInvoker,
+	opts ...grpc.CallOption,
+) error {
+	var err error
+	for _, n := range p.candidates() {
+		err = n.conn.Invoke(ctx, method, req, reply, opts...)
+		if !p.failed(ctx, n, err) {
+			if err == nil {
+				p.succeeded(n)
+			}
+			return err
+		}
+	}
+	return err
+}
+
+// streamInterceptor opens the stream with the current beacon node, and fails over to the other
+// beacon nodes in turn while they are unavailable. Streams which are already open do not fail
+// over.
+func (p *Pool) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	_ *grpc.ClientConn,
+	method string,
+	_ grpc.Streamer,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	var err error
+	for _, n := range p.candidates() {
+		var stream grpc.ClientStream
+		stream, err = n.conn.NewStream(ctx, desc, method, opts...)
+		if !p.failed(ctx, n, err) {
+			if err == nil {
+				p.succeeded(n)
+			}
+			return stream, err
+		}
+	}
+	return nil, err
+}
+
+// probeBeaconNode returns the sync status and the head slot of the beacon node. The requests
+// are not retried, so that an unavailable beacon node fails its health check right away.
+func probeBeaconNode(ctx context.Context, conn *grpc.ClientConn) (bool, uint64, error) {
+	syncStatus, err := ethpb.NewNodeClient(conn).GetSyncStatus(ctx, &ptypes.Empty{}, grpc_retry.WithMax(0))
+	if err != nil {
+		return false, 0, errors.Wrap(err, "could not get sync status")
+	}
+	head, err := ethpb.NewBeaconChainClient(conn).GetChainHead(ctx, &ptypes.Empty{}, grpc_retry.WithMax(0))
+	if err != nil {
+		return false, 0, errors.Wrap(err, "could not get chain head")
+	}
+	return syncStatus.Syncing, head.HeadSlot, nil
+}
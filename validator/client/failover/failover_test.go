@@ -0,0 +1,113 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints := ParseEndpoints("127.0.0.1:4000, 10.0.0.1:4000,,")
+	if len(endpoints) != 2 || endpoints[0] != "127.0.0.1:4000" || endpoints[1] != "10.0.0.1:4000" {
+		t.Errorf("Unexpected endpoints %v", endpoints)
+	}
+}
+
+func TestPool_SelectNode(t *testing.T) {
+	a := &node{endpoint: "a", headSlot: 100, latency: 30 * time.Millisecond}
+	b := &node{endpoint: "b", headSlot: 100, latency: 20 * time.Millisecond}
+	c := &node{endpoint: "c", headSlot: 101, latency: 10 * time.Millisecond}
+	p := &Pool{nodes: []*node{a, b, c}, current: a}
+
+	// The current beacon node is kept while it is healthy, even if another one is faster.
+	p.selectNode()
+	if p.current != a || !a.healthy || !b.healthy || !c.healthy {
+		t.Fatalf("Expected all beacon nodes to be healthy and a to be kept, current is %s", p.current.endpoint)
+	}
+
+	// A syncing beacon node is not healthy, the fastest healthy one is selected.
+	a.syncing = true
+	p.selectNode()
+	if a.healthy || p.current != c {
+		t.Fatalf("Expected to fail over from the syncing beacon node to c, current is %s", p.current.endpoint)
+	}
+
+	// Beacon nodes too far behind the highest head are not healthy.
+	a.syncing = false
+	c.headSlot = 100 + maxHeadSlotLag + 1
+	c.err = nil
+	b.err = errors.New("unavailable")
+	p.current = a
+	p.selectNode()
+	if a.healthy || b.healthy || !c.healthy || p.current != c {
+		t.Errorf("Expected to fail over from the lagging beacon node to c, current is %s", p.current.endpoint)
+	}
+
+	// Without healthy beacon nodes, the current one is kept.
+	c.err = errors.New("unavailable")
+	p.selectNode()
+	if p.current != c {
+		t.Errorf("Expected to keep c without a healthy beacon node, current is %s", p.current.endpoint)
+	}
+}
+
+func TestPool_FailsOverUnavailableBeaconNode(t *testing.T) {
+	// The first beacon node is down.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := lis.Addr().String()
+	if err := lis.Close(); err != nil {
+		t.Fatal(err)
+	}
+	lis, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go func() {
+		if err := server.Serve(lis); err != nil {
+			t.Error(err)
+		}
+	}()
+	defer server.Stop()
+	up := lis.Addr().String()
+
+	// Health check the beacon nodes as healthy, so that only the request fails over.
+	defer func(probeFn func(context.Context, *grpc.ClientConn) (bool, uint64, error)) {
+		probe = probeFn
+	}(probe)
+	probe = func(context.Context, *grpc.ClientConn) (bool, uint64, error) {
+		return false, 0, nil
+	}
+
+	p, err := Dial(context.Background(), []string{down, up}, grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := p.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(p.Conn()).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("Expected the request to fail over, received %v", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Unexpected status %v", resp.Status)
+	}
+	if p.Endpoint() != up {
+		t.Errorf("Expected the current beacon node to be %s, received %s", up, p.Endpoint())
+	}
+}
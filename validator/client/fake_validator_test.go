@@ -1,4 +1,4 @@
-package polling
+package client
 
 import (
 	"context"
@@ -10,6 +10,7 @@ var _ = Validator(&fakeValidator{})
 type fakeValidator struct {
 	DoneCalled                       bool
 	WaitForActivationCalled          bool
+	CheckDoppelgangerCalled          bool
 	WaitForChainStartCalled          bool
 	WaitForSyncCalled                bool
 	WaitForSyncedCalled              bool
@@ -47,6 +48,11 @@ func (fv *fakeValidator) WaitForActivation(_ context.Context) error {
 	return nil
 }
 
+func (fv *fakeValidator) CheckDoppelganger(_ context.Context) error {
+	fv.CheckDoppelgangerCalled = true
+	return nil
+}
+
 func (fv *fakeValidator) WaitForSync(_ context.Context) error {
 	fv.WaitForSyncCalled = true
 	return nil
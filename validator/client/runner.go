@@ -1,4 +1,4 @@
-package polling
+package client
 
 import (
 	"context"
@@ -22,6 +22,7 @@ type Validator interface {
 	WaitForSync(ctx context.Context) error
 	WaitForSynced(ctx context.Context) error
 	WaitForActivation(ctx context.Context) error
+	CheckDoppelganger(ctx context.Context) error
 	CanonicalHeadSlot(ctx context.Context) (uint64, error)
 	NextSlot() <-chan uint64
 	SlotDeadline(slot uint64) time.Time
@@ -44,7 +45,7 @@ type Validator interface {
 // 1 - Initialize validator data
 // 2 - Wait for validator activation
 // 3 - Wait for the next slot start
-// 4 - Update assignments
+// 4 - Update assignments from the duties source
 // 5 - Determine role at current slot
 // 6 - Perform assigned role, if any
 func run(ctx context.Context, v Validator) {
@@ -64,6 +65,13 @@ func run(ctx context.Context, v Validator) {
 	if err := v.WaitForActivation(ctx); err != nil {
 		log.Fatalf("Could not wait for validator activation: %v", err)
 	}
+	if err := v.CheckDoppelganger(ctx); err != nil {
+		if ctx.Err() != nil {
+			log.Info("Context canceled, stopping validator")
+			return
+		}
+		log.Fatalf("Refusing to perform duties: %v", err)
+	}
 	headSlot, err := v.CanonicalHeadSlot(ctx)
 	if err != nil {
 		log.Fatalf("Could not get current canonical head slot: %v", err)
@@ -1,4 +1,4 @@
-package polling
+package client
 
 import (
 	"context"
@@ -54,6 +54,14 @@ func TestCancelledContext_WaitsForActivation(t *testing.T) {
 	}
 }
 
+func TestCancelledContext_ChecksDoppelganger(t *testing.T) {
+	v := &fakeValidator{}
+	run(cancelledContext(), v)
+	if !v.CheckDoppelgangerCalled {
+		t.Error("Expected CheckDoppelganger() to be called")
+	}
+}
+
 func TestUpdateDuties_NextSlot(t *testing.T) {
 	v := &fakeValidator{}
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1,4 +1,4 @@
-package polling
+package client
 
 import (
 	"context"
@@ -18,6 +18,7 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/grpcutils"
 	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
@@ -38,9 +39,10 @@ type ValidatorService struct {
 	validator            Validator
 	graffiti             []byte
 	conn                 *grpc.ClientConn
-	endpoint             string
+	pool                 *failover.Pool
+	endpoints            []string
 	withCert             string
-	dataDir              string
+	db                   *db.Store
 	keyManager           keymanager.KeyManager
 	logValidatorBalances bool
 	emitAccountMetrics   bool
@@ -48,12 +50,13 @@ type ValidatorService struct {
 	grpcRetries          uint
 	grpcHeaders          []string
 	protector            slashingprotection.Protector
+	doppelgangerEpochs   uint64
 }
 
 // Config for the validator service.
 type Config struct {
-	Endpoint                   string
-	DataDir                    string
+	Endpoints                  []string
+	ValidatorDB                *db.Store
 	CertFlag                   string
 	GraffitiFlag               string
 	KeyManager                 keymanager.KeyManager
@@ -63,6 +66,7 @@ type Config struct {
 	GrpcRetriesFlag            uint
 	GrpcHeadersFlag            string
 	Protector                  slashingprotection.Protector
+	DoppelgangerEpochs         uint64
 }
 
 // NewValidatorService creates a new validator service for the service
@@ -72,9 +76,9 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 	return &ValidatorService{
 		ctx:                  ctx,
 		cancel:               cancel,
-		endpoint:             cfg.Endpoint,
+		endpoints:            cfg.Endpoints,
 		withCert:             cfg.CertFlag,
-		dataDir:              cfg.DataDir,
+		db:                   cfg.ValidatorDB,
 		graffiti:             []byte(cfg.GraffitiFlag),
 		keyManager:           cfg.KeyManager,
 		logValidatorBalances: cfg.LogValidatorBalances,
@@ -83,6 +87,7 @@ func NewValidatorService(ctx context.Context, cfg *Config) (*ValidatorService, e
 		grpcRetries:          cfg.GrpcRetriesFlag,
 		grpcHeaders:          strings.Split(cfg.GrpcHeadersFlag, ","),
 		protector:            cfg.Protector,
+		doppelgangerEpochs:   cfg.DoppelgangerEpochs,
 	}, nil
 }
 
@@ -104,28 +109,17 @@ func (v *ValidatorService) Start() {
 	if dialOpts == nil {
 		return
 	}
-	conn, err := grpc.DialContext(v.ctx, v.endpoint, dialOpts...)
+	pool, err := failover.Dial(v.ctx, v.endpoints, dialOpts...)
 	if err != nil {
-		log.Errorf("Could not dial endpoint: %s, %v", v.endpoint, err)
+		log.Errorf("Could not dial endpoints: %v, %v", v.endpoints, err)
 		return
 	}
 	if v.withCert != "" {
 		log.Info("Established secure gRPC connection")
 	}
 
-	pubkeys, err := v.keyManager.FetchValidatingKeys()
-	if err != nil {
-		log.Errorf("Could not get validating keys: %v", err)
-		return
-	}
-
-	valDB, err := db.NewKVStore(v.dataDir, pubkeys)
-	if err != nil {
-		log.Errorf("Could not initialize db: %v", err)
-		return
-	}
-
-	v.conn = conn
+	v.pool = pool
+	v.conn = pool.Conn()
 	cache, err := ristretto.NewCache(&ristretto.Config{
 		NumCounters: 1920, // number of keys to track.
 		MaxCost:     192,  // maximum cost of cache, 1 item = 1 cost.
@@ -142,7 +136,9 @@ func (v *ValidatorService) Start() {
 	}
 
 	v.validator = &validator{
-		db:                             valDB,
+		db:                             v.db,
+		dutiesSource:                   newDutiesSource(),
+		dutiesByEpoch:                  make(map[uint64][]*ethpb.DutiesResponse_Duty, 2), // 2 epochs worth of duties.
 		validatorClient:                ethpb.NewBeaconNodeValidatorClient(v.conn),
 		beaconClient:                   ethpb.NewBeaconChainClient(v.conn),
 		node:                           ethpb.NewNodeClient(v.conn),
@@ -155,6 +151,7 @@ func (v *ValidatorService) Start() {
 		domainDataCache:                cache,
 		aggregatedSlotCommitteeIDCache: aggregatedSlotCommitteeIDCache,
 		protector:                      v.protector,
+		doppelgangerEpochs:             v.doppelgangerEpochs,
 	}
 	go run(v.ctx, v.validator)
 }
@@ -163,8 +160,8 @@ func (v *ValidatorService) Start() {
 func (v *ValidatorService) Stop() error {
 	v.cancel()
 	log.Info("Stopping service")
-	if v.conn != nil {
-		return v.conn.Close()
+	if v.pool != nil {
+		return v.pool.Close()
 	}
 	return nil
 }
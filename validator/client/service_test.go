@@ -1,4 +1,4 @@
-package streaming
+package client
 
 import (
 	"context"
@@ -94,7 +94,7 @@ func TestLifecycle(t *testing.T) {
 	validatorService := &ValidatorService{
 		ctx:        ctx,
 		cancel:     cancel,
-		endpoint:   "merkle tries",
+		endpoints:  []string{"merkle tries"},
 		withCert:   "alice.crt",
 		keyManager: keymanager.NewDirect(nil),
 	}
@@ -113,7 +113,7 @@ func TestLifecycle_Insecure(t *testing.T) {
 	validatorService := &ValidatorService{
 		ctx:        ctx,
 		cancel:     cancel,
-		endpoint:   "merkle tries",
+		endpoints:  []string{"merkle tries"},
 		keyManager: keymanager.NewDirect(nil),
 	}
 	validatorService.Start()
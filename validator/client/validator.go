@@ -1,6 +1,7 @@
-// Package streaming represents a gRPC stream-based implementation
-// of an eth2 validator client.
-package streaming
+// Package client represents a gRPC-based implementation of an eth2 validator
+// client, receiving the duties of its validating keys from a beacon node either
+// by polling or by streaming them.
+package client
 
 import (
 	"context"
@@ -27,8 +28,10 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/roughtime"
 	"github.com/prysmaticlabs/prysm/shared/slotutil"
+	"github.com/prysmaticlabs/prysm/validator/client/doppelganger"
 	"github.com/prysmaticlabs/prysm/validator/client/metrics"
 	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	slashingprotection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
@@ -48,6 +51,7 @@ type validator struct {
 	genesisTime                        uint64
 	ticker                             *slotutil.SlotTicker
 	db                                 *db.Store
+	dutiesSource                       dutiesSource
 	dutiesLock                         sync.RWMutex
 	dutiesByEpoch                      map[uint64][]*ethpb.DutiesResponse_Duty
 	validatorClient                    ethpb.BeaconNodeValidatorClient
@@ -66,7 +70,9 @@ type validator struct {
 	aggregatedSlotCommitteeIDCacheLock sync.Mutex
 	attesterHistoryByPubKey            map[[48]byte]*slashpb.AttestationHistory
 	attesterHistoryByPubKeyLock        sync.RWMutex
+	attesterLowWatermarkByPubKey       map[[48]byte]*iface.LowWatermark
 	protector                          slashingprotection.Protector
+	doppelgangerEpochs                 uint64
 }
 
 // Done cleans up the validator.
@@ -106,7 +112,7 @@ func (v *validator) WaitForChainStart(ctx context.Context) error {
 	// Once the ChainStart log is received, we update the genesis time of the validator client
 	// and begin a slot ticker used to track the current slot the beacon node is in.
 	v.ticker = slotutil.GetSlotTicker(time.Unix(int64(v.genesisTime), 0), params.BeaconConfig().SecondsPerSlot)
-	log.WithField("genesisTime", time.Unix(int64(v.genesisTime), 0)).Info("Beacon chain genesis")
+	log.WithField("genesisTime", time.Unix(int64(v.genesisTime), 0)).Info("Beacon chain started")
 	return nil
 }
 
@@ -175,6 +181,19 @@ func (v *validator) WaitForSynced(ctx context.Context) error {
 	return nil
 }
 
+// CheckDoppelganger blocks for the doppelganger epochs, and returns an error if any of the
+// validating keys attests or proposes a block meanwhile, as another validator client is then
+// signing with it.
+func (v *validator) CheckDoppelganger(ctx context.Context) error {
+	ctx, span := trace.StartSpan(ctx, "validator.CheckDoppelganger")
+	defer span.End()
+	validatingKeys, err := v.keyManager.FetchValidatingKeys()
+	if err != nil {
+		return errors.Wrap(err, "could not fetch validating keys")
+	}
+	return doppelganger.Check(ctx, v.beaconClient, validatingKeys, v.NextSlot(), v.doppelgangerEpochs)
+}
+
 // WaitForActivation checks whether the validator pubkey is in the active
 // validator set. If not, this operation will block until an activation message is
 // received.
@@ -278,6 +297,28 @@ func (v *validator) checkAndLogValidatorStatus(validatorStatuses []*ethpb.Valida
 	return validatorActivated
 }
 
+// CanonicalHeadSlot returns the slot of canonical block currently found in the
+// beacon chain via RPC.
+func (v *validator) CanonicalHeadSlot(ctx context.Context) (uint64, error) {
+	ctx, span := trace.StartSpan(ctx, "validator.CanonicalHeadSlot")
+	defer span.End()
+	head, err := v.beaconClient.GetChainHead(ctx, &ptypes.Empty{})
+	if err != nil {
+		return 0, err
+	}
+	return head.HeadSlot, nil
+}
+
+// CurrentSlot based on the chain genesis time.
+func (v *validator) CurrentSlot() uint64 {
+	var currentSlot uint64
+	genesisTime := time.Unix(int64(v.genesisTime), 0)
+	if genesisTime.Before(roughtime.Now()) {
+		currentSlot = slotutil.SlotsSinceGenesis(genesisTime)
+	}
+	return currentSlot
+}
+
 // NextSlot emits the next slot number at the start time of that slot.
 func (v *validator) NextSlot() <-chan uint64 {
 	return v.ticker.C()
@@ -314,7 +355,14 @@ func (v *validator) UpdateProtections(ctx context.Context, slot uint64) error {
 	if err != nil {
 		return errors.Wrap(err, "could not get attester history")
 	}
+	lowWatermarkByPubKey, err := v.db.AttestationLowWatermarks(ctx, attestingPubKeys)
+	if err != nil {
+		return errors.Wrap(err, "could not get attester low watermarks")
+	}
+	v.attesterHistoryByPubKeyLock.Lock()
 	v.attesterHistoryByPubKey = attHistoryByPubKey
+	v.attesterLowWatermarkByPubKey = lowWatermarkByPubKey
+	v.attesterHistoryByPubKeyLock.Unlock()
 	return nil
 }
 
@@ -368,16 +416,6 @@ func (v *validator) UpdateDomainDataCaches(ctx context.Context, slot uint64) {
 	}
 }
 
-// CurrentSlot based on the chain genesis time.
-func (v *validator) CurrentSlot() uint64 {
-	var currentSlot uint64
-	genesisTime := time.Unix(int64(v.genesisTime), 0)
-	if genesisTime.Before(roughtime.Now()) {
-		currentSlot = slotutil.SlotsSinceGenesis(genesisTime)
-	}
-	return currentSlot
-}
-
 func (v *validator) domainData(ctx context.Context, epoch uint64, domain []byte) (*ethpb.DomainResponse, error) {
 	v.domainDataLock.Lock()
 	defer v.domainDataLock.Unlock()
@@ -2,6 +2,7 @@
 package db
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prysmaticlabs/prysm/validator/db/iface"
 	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
 )
 
 var _ = iface.ValidatorDB(&Store{})
@@ -37,6 +39,36 @@ func (store *Store) view(fn func(*bolt.Tx) error) error {
 	return store.db.View(fn)
 }
 
+// PublicKeys returns every validator public key with a proposal or an attestation history in the database.
+func (store *Store) PublicKeys(ctx context.Context) ([][48]byte, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.PublicKeys")
+	defer span.End()
+
+	seen := make(map[[48]byte]bool)
+	var pubKeys [][48]byte
+	addKey := func(k []byte) {
+		var pubKey [48]byte
+		copy(pubKey[:], k)
+		if !seen[pubKey] {
+			seen[pubKey] = true
+			pubKeys = append(pubKeys, pubKey)
+		}
+	}
+	err := store.view(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(historicProposalsBucket).ForEach(func(k, _ []byte) error {
+			addKey(k)
+			return nil
+		}); err != nil {
+			return err
+		}
+		return tx.Bucket(historicAttestationsBucket).ForEach(func(k, _ []byte) error {
+			addKey(k)
+			return nil
+		})
+	})
+	return pubKeys, err
+}
+
 // ClearDB removes any previously stored data at the configured data directory.
 func (store *Store) ClearDB() error {
 	if _, err := os.Stat(store.databasePath); os.IsNotExist(err) {
@@ -82,10 +114,15 @@ func NewKVStore(dirPath string, pubKeys [][48]byte) (*Store, error) {
 			tx,
 			historicProposalsBucket,
 			historicAttestationsBucket,
+			attestationLowWatermarksBucket,
+			metadataBucket,
 		)
 	}); err != nil {
 		return nil, err
 	}
+	if err := kv.migrate(); err != nil {
+		return nil, errors.Wrap(err, "could not migrate database schema")
+	}
 
 	// Initialize the required public keys into the DB to ensure they're not empty.
 	if err := kv.initializeSubBuckets(pubKeys); err != nil {
@@ -109,7 +146,11 @@ func GetKVStore(directory string) (*Store, error) {
 		return nil, err
 	}
 
-	return &Store{db: boltDb, databasePath: directory}, nil
+	kv := &Store{db: boltDb, databasePath: directory}
+	if err := kv.migrate(); err != nil {
+		return nil, errors.Wrap(err, "could not migrate database schema")
+	}
+	return kv, nil
 }
 
 // Size returns the db size in bytes.
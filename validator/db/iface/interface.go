@@ -14,12 +14,25 @@ type ValidatorDB interface {
 	io.Closer
 	DatabasePath() string
 	ClearDB() error
+	PublicKeys(ctx context.Context) ([][48]byte, error)
+	InitializePublicKeys(ctx context.Context, pubKeys [][48]byte) error
 	// Proposer protection related methods.
 	ProposalHistoryForEpoch(ctx context.Context, publicKey []byte, epoch uint64) (bitfield.Bitlist, error)
+	ProposalHistoryForPubKey(ctx context.Context, publicKey []byte) (map[uint64]bitfield.Bitlist, error)
 	SaveProposalHistoryForEpoch(ctx context.Context, publicKey []byte, epoch uint64, history bitfield.Bitlist) error
 	DeleteProposalHistory(ctx context.Context, publicKey []byte) error
 	// Attester protection related methods.
 	AttestationHistoryForPubKeys(ctx context.Context, publicKeys [][48]byte) (map[[48]byte]*slashpb.AttestationHistory, error)
 	SaveAttestationHistoryForPubKeys(ctx context.Context, historyByPubKey map[[48]byte]*slashpb.AttestationHistory) error
 	DeleteAttestationHistory(ctx context.Context, publicKey []byte) error
+	AttestationLowWatermarks(ctx context.Context, publicKeys [][48]byte) (map[[48]byte]*LowWatermark, error)
+	SaveAttestationLowWatermarks(ctx context.Context, watermarks map[[48]byte]*LowWatermark) error
+}
+
+// LowWatermark protects attestations older than the attestation history of a validator can
+// hold, such as those of an imported slashing protection history. The validator must not
+// attest with a source epoch below SourceEpoch, or a target epoch at or below TargetEpoch.
+type LowWatermark struct {
+	SourceEpoch uint64
+	TargetEpoch uint64
 }
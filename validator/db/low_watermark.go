@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	bolt "go.etcd.io/bbolt"
+	"go.opencensus.io/trace"
+)
+
+// AttestationLowWatermarks returns the attestation low watermarks of the requested validator
+// public keys. Public keys without a low watermark are not contained in the returned map.
+func (store *Store) AttestationLowWatermarks(ctx context.Context, publicKeys [][48]byte) (map[[48]byte]*iface.LowWatermark, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.AttestationLowWatermarks")
+	defer span.End()
+
+	watermarks := make(map[[48]byte]*iface.LowWatermark)
+	err := store.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(attestationLowWatermarksBucket)
+		if bucket == nil {
+			return nil
+		}
+		for _, key := range publicKeys {
+			if enc := bucket.Get(key[:]); len(enc) == 16 {
+				watermarks[key] = &iface.LowWatermark{
+					SourceEpoch: binary.LittleEndian.Uint64(enc[:8]),
+					TargetEpoch: binary.LittleEndian.Uint64(enc[8:]),
+				}
+			}
+		}
+		return nil
+	})
+	return watermarks, err
+}
+
+// SaveAttestationLowWatermarks raises the attestation low watermarks of the validator public
+// keys. A stored low watermark is never lowered.
+func (store *Store) SaveAttestationLowWatermarks(ctx context.Context, watermarks map[[48]byte]*iface.LowWatermark) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.SaveAttestationLowWatermarks")
+	defer span.End()
+
+	return store.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(attestationLowWatermarksBucket)
+		for pubKey, watermark := range watermarks {
+			source, target := watermark.SourceEpoch, watermark.TargetEpoch
+			if enc := bucket.Get(pubKey[:]); len(enc) == 16 {
+				if stored := binary.LittleEndian.Uint64(enc[:8]); stored > source {
+					source = stored
+				}
+				if stored := binary.LittleEndian.Uint64(enc[8:]); stored > target {
+					target = stored
+				}
+			}
+			enc := make([]byte, 16)
+			binary.LittleEndian.PutUint64(enc[:8], source)
+			binary.LittleEndian.PutUint64(enc[8:], target)
+			if err := bucket.Put(pubKey[:], enc); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"path/filepath"
+
+	"github.com/prysmaticlabs/prysm/shared/migration"
+)
+
+// migrations upgrade the schema of the validator database, ordered by version. The schema
+// version is recorded in the metadata bucket. Released migrations must never be modified,
+// schema changes are appended as a new migration instead.
+var migrations = []*migration.Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// Databases created before schema versioning already have the initial schema.
+		Up: func(migration.Tx, []byte) ([]byte, error) {
+			return nil, nil
+		},
+	},
+}
+
+// PendingMigrations returns the schema version of the validator database in the given
+// directory and the migrations which have not been applied to it, without modifying it.
+func PendingMigrations(dirPath string) (uint64, []*migration.Migration, error) {
+	return migration.PendingInFile(filepath.Join(dirPath, databaseFileName), metadataBucket, migrations)
+}
+
+// migrate applies the pending schema migrations to the database.
+func (store *Store) migrate() error {
+	return migration.Run(migration.Bolt(store.db), metadataBucket, migrations)
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/migration"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestStore_MigratesSchemaOnOpen(t *testing.T) {
+	db := SetupDB(t, [][48]byte{})
+	latest := migration.Latest(migrations)
+	if err := db.view(func(tx *bolt.Tx) error {
+		if v := migration.Version(migration.BoltTx(tx), metadataBucket); v != latest {
+			t.Errorf("Expected schema version %d, received %d", latest, v)
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	version, pending, err := PendingMigrations(db.DatabasePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != latest || len(pending) != 0 {
+		t.Errorf("Expected schema version %d without pending migrations, received %d with %d", latest, version, len(pending))
+	}
+}
@@ -39,6 +39,29 @@ func (store *Store) ProposalHistoryForEpoch(ctx context.Context, publicKey []byt
 	return slotBitlist, err
 }
 
+// ProposalHistoryForPubKey returns the proposal history of every epoch stored for a validator
+// public key, keyed by epoch. Returns an empty map if there is no proposal history for the validator.
+func (store *Store) ProposalHistoryForPubKey(ctx context.Context, publicKey []byte) (map[uint64]bitfield.Bitlist, error) {
+	ctx, span := trace.StartSpan(ctx, "Validator.ProposalHistoryForPubKey")
+	defer span.End()
+
+	history := make(map[uint64]bitfield.Bitlist)
+	err := store.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(historicProposalsBucket)
+		valBucket := bucket.Bucket(publicKey)
+		if valBucket == nil {
+			return nil
+		}
+		return valBucket.ForEach(func(k, v []byte) error {
+			slotBits := make(bitfield.Bitlist, len(v))
+			copy(slotBits, v)
+			history[binary.LittleEndian.Uint64(k)] = slotBits
+			return nil
+		})
+	})
+	return history, err
+}
+
 // SaveProposalHistoryForEpoch saves the proposal history for the requested validator public key.
 func (store *Store) SaveProposalHistoryForEpoch(ctx context.Context, pubKey []byte, epoch uint64, slotBits bitfield.Bitlist) error {
 	ctx, span := trace.StartSpan(ctx, "Validator.SaveProposalHistoryForEpoch")
@@ -92,6 +115,15 @@ func pruneProposalHistory(valBucket *bolt.Bucket, newestEpoch uint64) error {
 	return nil
 }
 
+// InitializePublicKeys initializes the proposal history of validator public keys which do not have
+// one yet, such as the ones imported while the validator client is running.
+func (store *Store) InitializePublicKeys(ctx context.Context, pubKeys [][48]byte) error {
+	ctx, span := trace.StartSpan(ctx, "Validator.InitializePublicKeys")
+	defer span.End()
+
+	return store.initializeSubBuckets(pubKeys)
+}
+
 func (store *Store) initializeSubBuckets(pubKeys [][48]byte) error {
 	return store.update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(historicProposalsBucket)
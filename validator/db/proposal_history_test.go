@@ -268,3 +268,33 @@ func TestDeleteProposalHistory_OK(t *testing.T) {
 		t.Fatalf("Unexpected error, received %v", err)
 	}
 }
+
+func TestProposalHistoryForPubKey_OK(t *testing.T) {
+	pubkey := [48]byte{4}
+	db := SetupDB(t, [][48]byte{pubkey})
+
+	history, err := db.ProposalHistoryForPubKey(context.Background(), pubkey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("Expected empty proposal history, received %v", history)
+	}
+
+	saved := map[uint64]bitfield.Bitlist{
+		1: {0x04, 0x00, 0x00, 0x00, 0x04},
+		5: {0x00, 0x01, 0x00, 0x00, 0x04},
+	}
+	for epoch, slotBits := range saved {
+		if err := db.SaveProposalHistoryForEpoch(context.Background(), pubkey[:], epoch, slotBits); err != nil {
+			t.Fatal(err)
+		}
+	}
+	history, err = db.ProposalHistoryForPubKey(context.Background(), pubkey[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(history, saved) {
+		t.Fatalf("Expected proposal history %v, received %v", saved, history)
+	}
+}
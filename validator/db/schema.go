@@ -1,8 +1,12 @@
 package db
 
 var (
+	// Metadata of the database, such as its schema version.
+	metadataBucket = []byte("metadata-bucket")
 	// Validator slashing protection from double proposals.
 	historicProposalsBucket = []byte("proposal-history-bucket")
 	// Validator slashing protection from slashable attestations.
 	historicAttestationsBucket = []byte("attestation-history-bucket")
+	// Lowest epochs validators may attest with, for history older than the attestation history holds.
+	attestationLowWatermarksBucket = []byte("attestation-low-watermarks-bucket")
 )
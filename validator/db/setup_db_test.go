@@ -1,12 +1,15 @@
 package db
 
 import (
+	"context"
 	"crypto/rand"
 	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
+
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
 )
 
 func TestClearDB(t *testing.T) {
@@ -31,3 +34,28 @@ func TestClearDB(t *testing.T) {
 		t.Fatalf("DB was not cleared: %v", err)
 	}
 }
+
+func TestStore_PublicKeys(t *testing.T) {
+	proposerKey := [48]byte{1}
+	attesterKey := [48]byte{2}
+	db := SetupDB(t, [][48]byte{proposerKey})
+
+	history := &slashpb.AttestationHistory{TargetToSource: map[uint64]uint64{0: 0}}
+	if err := db.SaveAttestationHistoryForPubKeys(context.Background(), map[[48]byte]*slashpb.AttestationHistory{
+		proposerKey: history,
+		attesterKey: history,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pubKeys, err := db.PublicKeys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pubKeys) != 2 {
+		t.Fatalf("Expected 2 public keys, received %d", len(pubKeys))
+	}
+	if pubKeys[0] != proposerKey || pubKeys[1] != attesterKey {
+		t.Errorf("Unexpected public keys %#x", pubKeys)
+	}
+}
@@ -14,10 +14,11 @@ var (
 			"of validating keys may wish to disable granular prometheus metrics as it increases " +
 			"the data cardinality.",
 	}
-	// BeaconRPCProviderFlag defines a beacon node RPC endpoint.
+	// BeaconRPCProviderFlag defines the beacon node RPC endpoints.
 	BeaconRPCProviderFlag = &cli.StringFlag{
-		Name:  "beacon-rpc-provider",
-		Usage: "Beacon node RPC provider endpoint",
+		Name: "beacon-rpc-provider",
+		Usage: "Beacon node RPC provider endpoint, or a comma separated list of endpoints in order of preference. " +
+			"The validator client fails over to another beacon node when the current one is unavailable, syncing or behind",
 		Value: "127.0.0.1:4000",
 	}
 	// CertFlag defines a flag for the node's TLS certificate.
@@ -46,6 +47,13 @@ var (
 		Name:  "graffiti",
 		Usage: "String to include in proposed blocks",
 	}
+	// DoppelgangerEpochsFlag defines the number of epochs to watch the validating keys for before performing duties.
+	DoppelgangerEpochsFlag = &cli.Uint64Flag{
+		Name: "doppelganger-epochs",
+		Usage: "The number of epochs to watch the validating keys for attestations and blocks before performing " +
+			"duties, refusing to start if another validator client is signing with them. 0 disables doppelganger " +
+			"protection. Requires a beacon node running with --enable-new-state-mgmt",
+	}
 	// GrpcRetriesFlag defines the number of times to retry a failed gRPC request.
 	GrpcRetriesFlag = &cli.UintFlag{
 		Name:  "grpc-retries",
@@ -61,7 +69,7 @@ var (
 	// KeyManager specifies the key manager to use.
 	KeyManager = &cli.StringFlag{
 		Name:  "keymanager",
-		Usage: "The keymanger to use (unencrypted, interop, keystore, wallet)",
+		Usage: "The keymanger to use (unencrypted, interop, keystore, eip2335, derived, wallet, remote, remote-http)",
 		Value: "",
 	}
 	// KeyManagerOpts specifies the key manager options.
@@ -75,6 +83,27 @@ var (
 		Name:  "keystore-path",
 		Usage: "Path to the desired keystore directory",
 	}
+	// KeysDirFlag defines the location of a directory of EIP-2335 keystores to import.
+	KeysDirFlag = &cli.StringFlag{
+		Name:  "keys-dir",
+		Usage: "Path to a directory of EIP-2335 keystores to import, such as the ones generated by the deposit CLI",
+	}
+	// MnemonicPassphraseFlag defines the optional passphrase protecting a mnemonic keys are derived from.
+	MnemonicPassphraseFlag = &cli.StringFlag{
+		Name:  "mnemonic-passphrase",
+		Usage: "The optional passphrase the mnemonic to derive validator keys from is protected with",
+	}
+	// NumAccountsFlag defines the number of validator accounts to derive from a mnemonic.
+	NumAccountsFlag = &cli.Uint64Flag{
+		Name:  "num-accounts",
+		Usage: "The number of validator accounts to derive from the mnemonic",
+		Value: 1,
+	}
+	// AccountStartIndexFlag defines the index of the first validator account to derive from a mnemonic.
+	AccountStartIndexFlag = &cli.Uint64Flag{
+		Name:  "account-start-index",
+		Usage: "The index i of the first validator account to derive from the mnemonic, along the path m/12381/3600/i/0/0",
+	}
 	// MonitoringPortFlag defines the http port used to serve prometheus metrics.
 	MonitoringPortFlag = &cli.Int64Flag{
 		Name:  "monitoring-port",
@@ -101,6 +130,39 @@ var (
 		Name:  "target-dir",
 		Usage: "The directory of the target validator database",
 	}
+	// SlashingProtectionFileFlag defines the path of an EIP-3076 slashing protection interchange file.
+	SlashingProtectionFileFlag = &cli.StringFlag{
+		Name:  "slashing-protection-file",
+		Usage: "The path of the slashing protection interchange JSON file to import from or export to",
+	}
+	// GenesisValidatorsRootFlag defines the genesis validators root of the chain a slashing protection
+	// interchange file belongs to.
+	GenesisValidatorsRootFlag = &cli.StringFlag{
+		Name:  "genesis-validators-root",
+		Usage: "The 0x prefixed hex genesis validators root of the chain the slashing protection history belongs to",
+	}
+	// EnableKeyManagerAPIFlag enables the key management API, to manage the validating keys at runtime.
+	EnableKeyManagerAPIFlag = &cli.BoolFlag{
+		Name:  "enable-keymanager-api",
+		Usage: "Enable the local key management API, to list, import, delete, enable and disable validating keys without restarting the validator client",
+	}
+	// KeyManagerAPIHostFlag defines the host of the key management API.
+	KeyManagerAPIHostFlag = &cli.StringFlag{
+		Name:  "keymanager-api-host",
+		Usage: "The host the key management API listens on",
+		Value: "127.0.0.1",
+	}
+	// KeyManagerAPIPortFlag defines the port of the key management API.
+	KeyManagerAPIPortFlag = &cli.IntFlag{
+		Name:  "keymanager-api-port",
+		Usage: "The port the key management API listens on",
+		Value: 7500,
+	}
+	// KeyManagerAPITokenFileFlag defines the file of the bearer token authenticating key management API requests.
+	KeyManagerAPITokenFileFlag = &cli.StringFlag{
+		Name:  "keymanager-api-token-file",
+		Usage: "The file of the bearer token authenticating key management API requests, generated if it does not exist. Defaults to keymanager-api-token in the data directory",
+	}
 	// UnencryptedKeysFlag specifies a file path of a JSON file of unencrypted validator keys as an
 	// alternative from launching the validator client from decrypting a keystore directory.
 	UnencryptedKeysFlag = &cli.StringFlag{
@@ -1,6 +1,8 @@
 package keymanager
 
 import (
+	"sync"
+
 	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 )
@@ -11,6 +13,8 @@ type Direct struct {
 	publicKeys map[[48]byte]bls.PublicKey
 	// Key to the map is the bytes of the public key.
 	secretKeys map[[48]byte]bls.SecretKey
+	// lock guards the keys, which may be added and removed at runtime.
+	lock sync.RWMutex
 }
 
 // NewDirect creates a new direct key manager from the secret keys provided to it.
@@ -20,16 +24,15 @@ func NewDirect(sks []bls.SecretKey) *Direct {
 		secretKeys: make(map[[48]byte]bls.SecretKey),
 	}
 	for _, sk := range sks {
-		publicKey := sk.PublicKey()
-		pubKey := bytesutil.ToBytes48(publicKey.Marshal())
-		res.publicKeys[pubKey] = publicKey
-		res.secretKeys[pubKey] = sk
+		res.addKey(sk)
 	}
 	return res
 }
 
 // FetchValidatingKeys fetches the list of public keys that should be used to validate with.
 func (km *Direct) FetchValidatingKeys() ([][48]byte, error) {
+	km.lock.RLock()
+	defer km.lock.RUnlock()
 	keys := make([][48]byte, 0, len(km.publicKeys))
 	for key := range km.publicKeys {
 		keys = append(keys, key)
@@ -39,8 +42,41 @@ func (km *Direct) FetchValidatingKeys() ([][48]byte, error) {
 
 // Sign signs a message for the validator to broadcast.
 func (km *Direct) Sign(pubKey [48]byte, root [32]byte) (bls.Signature, error) {
+	km.lock.RLock()
+	defer km.lock.RUnlock()
 	if secretKey, exists := km.secretKeys[pubKey]; exists {
 		return secretKey.Sign(root[:]), nil
 	}
 	return nil, ErrNoSuchKey
 }
+
+// addKey adds the secret key, and returns its public key.
+func (km *Direct) addKey(sk bls.SecretKey) [48]byte {
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	publicKey := sk.PublicKey()
+	pubKey := bytesutil.ToBytes48(publicKey.Marshal())
+	km.publicKeys[pubKey] = publicKey
+	km.secretKeys[pubKey] = sk
+	return pubKey
+}
+
+// hasKey returns true if the key manager holds the secret key of the public key.
+func (km *Direct) hasKey(pubKey [48]byte) bool {
+	km.lock.RLock()
+	defer km.lock.RUnlock()
+	_, exists := km.secretKeys[pubKey]
+	return exists
+}
+
+// removeKey removes the key of the public key, and returns false if there is no such key.
+func (km *Direct) removeKey(pubKey [48]byte) bool {
+	km.lock.Lock()
+	defer km.lock.Unlock()
+	if _, exists := km.secretKeys[pubKey]; !exists {
+		return false
+	}
+	delete(km.publicKeys, pubKey)
+	delete(km.secretKeys, pubKey)
+	return true
+}
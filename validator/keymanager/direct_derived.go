@@ -0,0 +1,74 @@
+package keymanager
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Derived is a key manager that derives the signing keys of validators from a mnemonic,
+// as defined in EIP-2333 and EIP-2334.
+type Derived struct {
+	*Direct
+}
+
+type derivedOpts struct {
+	Mnemonic   string `json:"mnemonic"`
+	Passphrase string `json:"passphrase"`
+	Keys       uint64 `json:"keys"`
+	Offset     uint64 `json:"offset"`
+}
+
+var derivedOptsHelp = `The derived key manager derives keys from a mnemonic, along the path m/12381/3600/i/0/0.  The options are:
+  - mnemonic This is the BIP-39 mnemonic to derive keys from.  Can be the name of an environment variable
+    prefixed with '$'.  Will be asked for if not supplied
+  - passphrase This is the optional passphrase the mnemonic was protected with
+  - keys This is the number of keys to derive
+  - offset This is the index of the first key to derive
+A sample set of options are:
+  {
+    "mnemonic": "$MNEMONIC", // Read the mnemonic from the MNEMONIC environment variable
+    "keys":     5,           // Derive 5 keys
+    "offset":   10           // Start with the key at m/12381/3600/10/0/0
+  }`
+
+// NewDerived creates a key manager populated with keys derived from a mnemonic.
+func NewDerived(input string) (*Derived, string, error) {
+	opts := &derivedOpts{}
+	err := json.Unmarshal([]byte(input), opts)
+	if err != nil {
+		return nil, derivedOptsHelp, err
+	}
+
+	if opts.Keys == 0 {
+		return nil, derivedOptsHelp, errors.New("at least one key is required")
+	}
+	if strings.HasPrefix(opts.Mnemonic, "$") {
+		opts.Mnemonic = os.Getenv(strings.TrimPrefix(opts.Mnemonic, "$"))
+	}
+	if opts.Mnemonic == "" {
+		log.Info("Enter your mnemonic:")
+		byteMnemonic, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return nil, derivedOptsHelp, err
+		}
+		opts.Mnemonic = strings.TrimSpace(string(byteMnemonic))
+	}
+
+	seed, err := bls.SeedFromMnemonic(opts.Mnemonic, opts.Passphrase)
+	if err != nil {
+		return nil, derivedOptsHelp, err
+	}
+	sks := make([]bls.SecretKey, opts.Keys)
+	for i := range sks {
+		sks[i], err = bls.DeriveSecretKeyFromPath(seed, bls.SigningKeyPath(opts.Offset+uint64(i)))
+		if err != nil {
+			return nil, derivedOptsHelp, err
+		}
+	}
+	return &Derived{Direct: NewDirect(sks)}, "", nil
+}
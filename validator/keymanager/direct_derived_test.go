@@ -0,0 +1,64 @@
+package keymanager_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestNewDerived(t *testing.T) {
+	km, _, err := keymanager.NewDerived(fmt.Sprintf(`{"mnemonic":%q,"keys":2,"offset":3}`, testMnemonic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Incorrect number of keys returned; expected 2, received %d", len(keys))
+	}
+
+	seed, err := bls.SeedFromMnemonic(testMnemonic, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, index := range []uint64{3, 4} {
+		sk, err := bls.DeriveSecretKeyFromPath(seed, bls.SigningKeyPath(index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := km.Sign(bytesutil.ToBytes48(sk.PublicKey().Marshal()), [32]byte{}); err != nil {
+			t.Errorf("Expected key at index %d to be derived: %v", index, err)
+		}
+	}
+}
+
+func TestNewDerived_MnemonicFromEnvironment(t *testing.T) {
+	if err := os.Setenv("TEST_DERIVED_MNEMONIC", testMnemonic); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.Unsetenv("TEST_DERIVED_MNEMONIC"); err != nil {
+			t.Error(err)
+		}
+	}()
+	if _, _, err := keymanager.NewDerived(`{"mnemonic":"$TEST_DERIVED_MNEMONIC","keys":1}`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewDerived_InvalidOptions(t *testing.T) {
+	if _, _, err := keymanager.NewDerived(fmt.Sprintf(`{"mnemonic":%q}`, testMnemonic)); err == nil {
+		t.Error("Expected key manager without keys to fail")
+	}
+	if _, _, err := keymanager.NewDerived(`{"mnemonic":"abandon about","keys":1}`); err == nil {
+		t.Error("Expected key manager with an invalid mnemonic to fail")
+	}
+}
@@ -0,0 +1,242 @@
+package keymanager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// EIP2335 is a key manager that loads keys from a directory of EIP-2335 JSON keystores,
+// such as the ones generated by the official deposit CLI.
+type EIP2335 struct {
+	*Direct
+	path string
+	// files are the paths of the keystore files, keyed by public key.
+	files     map[[48]byte]string
+	filesLock sync.Mutex
+}
+
+type eip2335Opts struct {
+	Path       string `json:"path"`
+	Passphrase string `json:"passphrase"`
+}
+
+var eip2335OptsHelp = `The eip2335 key manager loads keys from a directory of EIP-2335 keystore files.  The options are:
+  - path This is the filesystem path to the directory containing the keystore files
+  - passphrase This is the passphrase used to decrypt the keystores.  Will be asked for if not supplied
+A sample set of options are:
+  {
+    "path":       "/home/me/validator_keys", // Load the keystores in '/home/me/validator_keys'
+    "passphrase": "secret"                   // Use the passphrase 'secret' to decrypt the keystores
+  }`
+
+// eip2335Keystore is the JSON representation of an EIP-2335 keystore. The crypto module is kept
+// as a generic map, as it is passed as such to the decryptor.
+type eip2335Keystore struct {
+	Crypto  map[string]interface{} `json:"crypto"`
+	PubKey  string                 `json:"pubkey"`
+	Path    string                 `json:"path"`
+	UUID    string                 `json:"uuid"`
+	Version uint                   `json:"version"`
+}
+
+// NewEIP2335 creates a key manager populated with the keys decrypted from the EIP-2335 keystores at the given path.
+func NewEIP2335(input string) (*EIP2335, string, error) {
+	opts := &eip2335Opts{}
+	err := json.Unmarshal([]byte(input), opts)
+	if err != nil {
+		return nil, eip2335OptsHelp, err
+	}
+
+	if opts.Path == "" {
+		return nil, eip2335OptsHelp, errors.New("a path to a directory of keystores is required")
+	}
+	if strings.Contains(opts.Path, "$") || strings.Contains(opts.Path, "~") || strings.Contains(opts.Path, "%") {
+		log.WithField("path", opts.Path).Warn("Keystore path contains unexpanded shell expansion characters")
+	}
+	if opts.Passphrase == "" {
+		log.Info("Enter the password of your validator keystores:")
+		bytePassword, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+		if err != nil {
+			return nil, eip2335OptsHelp, err
+		}
+		opts.Passphrase = strings.Replace(string(bytePassword), "\n", "", -1)
+	}
+
+	files, err := eip2335KeystoreFiles(opts.Path)
+	if err != nil {
+		return nil, eip2335OptsHelp, err
+	}
+	if len(files) == 0 {
+		return nil, "", fmt.Errorf("no keystore files found in %s", opts.Path)
+	}
+	km := &EIP2335{
+		Direct: NewDirect(nil),
+		path:   opts.Path,
+		files:  make(map[[48]byte]string, len(files)),
+	}
+	for _, file := range files {
+		_, sk, err := decryptEIP2335KeystoreFile(file, opts.Passphrase)
+		if err != nil {
+			return nil, "", err
+		}
+		km.files[km.addKey(sk)] = file
+	}
+	log.WithField("keys", len(km.files)).Info("Decrypted EIP-2335 keystores")
+	return km, "", nil
+}
+
+// ImportKeystore decrypts the EIP-2335 keystore with the passphrase, and writes it to the
+// directory of keystores of the key manager before validating with its key.
+func (km *EIP2335) ImportKeystore(enc []byte, passphrase string) ([48]byte, error) {
+	keystore := &eip2335Keystore{}
+	if err := json.Unmarshal(enc, keystore); err != nil {
+		return [48]byte{}, errors.Wrap(err, "could not decode keystore")
+	}
+	sk, err := decryptEIP2335Keystore(keystore, passphrase)
+	if err != nil {
+		return [48]byte{}, errors.Wrap(err, "could not decrypt keystore")
+	}
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+
+	km.filesLock.Lock()
+	defer km.filesLock.Unlock()
+	if km.hasKey(pubKey) {
+		return pubKey, ErrKeyExists
+	}
+	target := filepath.Join(km.path, fmt.Sprintf("keystore-%x.json", pubKey))
+	if err := ioutil.WriteFile(target, enc, 0600); err != nil {
+		return pubKey, errors.Wrapf(err, "could not write keystore %s", target)
+	}
+	km.files[pubKey] = target
+	km.addKey(sk)
+	log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Imported EIP-2335 keystore")
+	return pubKey, nil
+}
+
+// DeleteKey stops validating with the key, and removes its keystore from the directory of
+// keystores of the key manager.
+func (km *EIP2335) DeleteKey(pubKey [48]byte) error {
+	km.filesLock.Lock()
+	defer km.filesLock.Unlock()
+	if !km.removeKey(pubKey) {
+		return ErrNoSuchKey
+	}
+	file := km.files[pubKey]
+	delete(km.files, pubKey)
+	if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "could not remove keystore %s", file)
+	}
+	log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Deleted EIP-2335 keystore")
+	return nil
+}
+
+// ImportEIP2335Keystores copies the EIP-2335 keystores in sourceDir to targetDir, so they can be used
+// by the eip2335 key manager. Every keystore must decrypt with the given passphrase, and keystores
+// already present in targetDir are skipped. The public keys of the imported keystores are returned.
+func ImportEIP2335Keystores(sourceDir string, targetDir string, passphrase string) ([][48]byte, error) {
+	files, err := eip2335KeystoreFiles(sourceDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no keystore files found in %s", sourceDir)
+	}
+	if err := os.MkdirAll(targetDir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "could not create directory %s", targetDir)
+	}
+
+	imported := make([][48]byte, 0, len(files))
+	for _, file := range files {
+		enc, sk, err := decryptEIP2335KeystoreFile(file, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+		target := filepath.Join(targetDir, fmt.Sprintf("keystore-%x.json", pubKey))
+		if _, err := os.Stat(target); err == nil {
+			log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Keystore already imported, skipping")
+			continue
+		}
+		if err := ioutil.WriteFile(target, enc, 0600); err != nil {
+			return nil, errors.Wrapf(err, "could not write keystore %s", target)
+		}
+		imported = append(imported, pubKey)
+	}
+	return imported, nil
+}
+
+// eip2335KeystoreFiles returns the paths of the JSON files in the given directory.
+func eip2335KeystoreFiles(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	keystores := make([]string, 0, len(files))
+	for _, file := range files {
+		// Skip the deposit data generated alongside the keystores by the deposit CLI.
+		if strings.HasPrefix(filepath.Base(file), "deposit_data") {
+			continue
+		}
+		keystores = append(keystores, file)
+	}
+	return keystores, nil
+}
+
+// decryptEIP2335KeystoreFile decrypts the keystore file at the given path, returning its raw
+// content along with the decrypted secret key.
+func decryptEIP2335KeystoreFile(path string, passphrase string) ([]byte, bls.SecretKey, error) {
+	enc, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not read keystore %s", path)
+	}
+	keystore := &eip2335Keystore{}
+	if err := json.Unmarshal(enc, keystore); err != nil {
+		return nil, nil, errors.Wrapf(err, "could not decode keystore %s", path)
+	}
+	sk, err := decryptEIP2335Keystore(keystore, passphrase)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not decrypt keystore %s", path)
+	}
+	return enc, sk, nil
+}
+
+func decryptEIP2335Keystore(keystore *eip2335Keystore, passphrase string) (bls.SecretKey, error) {
+	if keystore.Version != 4 {
+		return nil, fmt.Errorf("unsupported keystore version %d", keystore.Version)
+	}
+	if keystore.Crypto == nil {
+		return nil, errors.New("keystore does not contain a crypto module")
+	}
+	// The decryptor verifies the checksum of the passphrase derived key before decrypting.
+	secret, err := keystorev4.New().Decrypt(keystore.Crypto, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	sk, err := bls.SecretKeyFromBytes(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse secret key")
+	}
+	if keystore.PubKey != "" {
+		pubKey, err := hex.DecodeString(strings.TrimPrefix(keystore.PubKey, "0x"))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not decode public key")
+		}
+		if !bytes.Equal(pubKey, sk.PublicKey().Marshal()) {
+			return nil, errors.New("public key of the keystore does not match its secret key")
+		}
+	}
+	return sk, nil
+}
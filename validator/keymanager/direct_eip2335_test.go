@@ -0,0 +1,120 @@
+package keymanager_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+func writeEIP2335Keystores(t *testing.T, passphrase string, numKeys int) (string, []bls.SecretKey) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	sks := make([]bls.SecretKey, numKeys)
+	for i := 0; i < numKeys; i++ {
+		sks[i] = bls.RandKey()
+		crypto, err := keystorev4.New().Encrypt(sks[i].Marshal(), passphrase)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, err := json.Marshal(map[string]interface{}{
+			"crypto":  crypto,
+			"pubkey":  fmt.Sprintf("%x", sks[i].PublicKey().Marshal()),
+			"path":    fmt.Sprintf("m/12381/3600/%d/0/0", i),
+			"uuid":    "9f75a3fa-1e5a-49f9-be3d-f5a19779c6fa",
+			"version": 4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("keystore-%d.json", i)), enc, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, sks
+}
+
+func TestNewEIP2335(t *testing.T) {
+	dir, sks := writeEIP2335Keystores(t, "secret", 2)
+
+	km, _, err := keymanager.NewEIP2335(fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(sks) {
+		t.Fatalf("Incorrect number of keys returned; expected %d, received %d", len(sks), len(keys))
+	}
+	pubKey := bytesutil.ToBytes48(sks[0].PublicKey().Marshal())
+	sig, err := km.Sign(pubKey, [32]byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(sks[0].PublicKey(), make([]byte, 32)) {
+		t.Error("Signature does not verify against the keystore public key")
+	}
+}
+
+func TestNewEIP2335_WrongPassphrase(t *testing.T) {
+	dir, _ := writeEIP2335Keystores(t, "secret", 1)
+
+	_, _, err := keymanager.NewEIP2335(fmt.Sprintf(`{"path":%q,"passphrase":"wrong"}`, dir))
+	if err == nil || !strings.Contains(err.Error(), "could not decrypt keystore") {
+		t.Errorf("Expected decryption to fail, received %v", err)
+	}
+}
+
+func TestImportEIP2335Keystores(t *testing.T) {
+	sourceDir, sks := writeEIP2335Keystores(t, "secret", 2)
+	targetDir := filepath.Join(sourceDir, "imported")
+
+	imported, err := keymanager.ImportEIP2335Keystores(sourceDir, targetDir, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != len(sks) {
+		t.Fatalf("Expected %d imported keys, received %d", len(sks), len(imported))
+	}
+	// Importing the same keystores again is a no-op.
+	imported, err = keymanager.ImportEIP2335Keystores(sourceDir, targetDir, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(imported) != 0 {
+		t.Errorf("Expected no keys to be imported twice, received %d", len(imported))
+	}
+
+	km, _, err := keymanager.NewEIP2335(fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, targetDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != len(sks) {
+		t.Errorf("Incorrect number of keys returned; expected %d, received %d", len(sks), len(keys))
+	}
+
+	if _, err := keymanager.ImportEIP2335Keystores(sourceDir, targetDir, "wrong"); err == nil {
+		t.Error("Expected import with the wrong passphrase to fail")
+	}
+}
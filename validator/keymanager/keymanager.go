@@ -16,6 +16,12 @@ var ErrCannotSign = errors.New("cannot sign")
 // ErrDenied is returned whenever a signing attempt is denied.
 var ErrDenied = errors.New("signing attempt denied")
 
+// ErrKeyExists is returned whenever a key is imported into a key manager which already holds it.
+var ErrKeyExists = errors.New("key already exists")
+
+// ErrCannotImport is returned whenever a key is imported into or deleted from a key manager which does not support it.
+var ErrCannotImport = errors.New("key manager cannot import or delete keys")
+
 // KeyManager controls access to private keys by the validator.
 type KeyManager interface {
 	// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
@@ -37,3 +43,13 @@ type ProtectingKeyManager interface {
 	// SignAttestation signs an attestation for the validator to broadcast.
 	SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (bls.Signature, error)
 }
+
+// ImportingKeyManager provides access to a keymanager whose keys can be imported and deleted at runtime.
+type ImportingKeyManager interface {
+	// ImportKeystore imports the secret key of an EIP-2335 keystore encrypted with the passphrase,
+	// and returns its public key.
+	ImportKeystore(keystore []byte, passphrase string) ([48]byte, error)
+
+	// DeleteKey deletes a key, so that it is no longer used to validate with.
+	DeleteKey(pubKey [48]byte) error
+}
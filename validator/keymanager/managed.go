@@ -0,0 +1,279 @@
+package keymanager
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+// ManagedKeyManager provides access to a keymanager whose keys can be managed at runtime.
+type ManagedKeyManager interface {
+	KeyManager
+	ImportingKeyManager
+
+	// ListKeys lists the keys of the key manager, whether they are enabled or not.
+	ListKeys() ([]*ManagedKey, error)
+
+	// EnableKey enables a key, so that it is used to validate with again.
+	EnableKey(pubKey [48]byte) error
+
+	// DisableKey disables a key, so that it is not used to validate with, while the key manager
+	// keeps holding it.
+	DisableKey(pubKey [48]byte) error
+}
+
+// ManagedKey is a key of a managed key manager.
+type ManagedKey struct {
+	PubKey  [48]byte
+	Enabled bool
+}
+
+// managed wraps a key manager, and disables its keys on demand. Disabled keys are neither fetched
+// to validate with nor signed with, and are saved to a file to remain disabled across restarts.
+type managed struct {
+	km               KeyManager
+	disabledKeysFile string
+	disabled         map[[48]byte]bool
+	lock             sync.RWMutex
+}
+
+// protectingManaged is a managed key manager wrapping a protecting key manager.
+type protectingManaged struct {
+	*managed
+	protecting ProtectingKeyManager
+}
+
+// NewManaged wraps the key manager so that its keys can be managed at runtime. The disabled keys
+// are loaded from and saved to the disabled keys file, unless it is empty. Keys can only be
+// imported and deleted if the wrapped key manager supports it.
+func NewManaged(km KeyManager, disabledKeysFile string) (ManagedKeyManager, error) {
+	m := &managed{
+		km:               km,
+		disabledKeysFile: disabledKeysFile,
+		disabled:         make(map[[48]byte]bool),
+	}
+	if err := m.loadDisabledKeys(); err != nil {
+		return nil, err
+	}
+	if protecting, ok := km.(ProtectingKeyManager); ok {
+		return &protectingManaged{managed: m, protecting: protecting}, nil
+	}
+	return m, nil
+}
+
+// FetchValidatingKeys fetches the list of enabled public keys that should be used to validate with.
+func (m *managed) FetchValidatingKeys() ([][48]byte, error) {
+	keys, err := m.km.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	enabled := make([][48]byte, 0, len(keys))
+	for _, key := range keys {
+		if !m.disabled[key] {
+			enabled = append(enabled, key)
+		}
+	}
+	return enabled, nil
+}
+
+// Sign signs a message for the validator to broadcast, unless the key is disabled.
+func (m *managed) Sign(pubKey [48]byte, root [32]byte) (bls.Signature, error) {
+	if m.isDisabled(pubKey) {
+		return nil, ErrDenied
+	}
+	return m.km.Sign(pubKey, root)
+}
+
+// ListKeys lists the keys of the key manager, sorted by public key.
+func (m *managed) ListKeys() ([]*ManagedKey, error) {
+	keys, err := m.km.FetchValidatingKeys()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return string(keys[i][:]) < string(keys[j][:])
+	})
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	managedKeys := make([]*ManagedKey, len(keys))
+	for i, key := range keys {
+		managedKeys[i] = &ManagedKey{PubKey: key, Enabled: !m.disabled[key]}
+	}
+	return managedKeys, nil
+}
+
+// ImportKeystore imports an EIP-2335 keystore into the wrapped key manager. The key is enabled.
+func (m *managed) ImportKeystore(keystore []byte, passphrase string) ([48]byte, error) {
+	importing, ok := m.km.(ImportingKeyManager)
+	if !ok {
+		return [48]byte{}, ErrCannotImport
+	}
+	pubKey, err := importing.ImportKeystore(keystore, passphrase)
+	if err != nil {
+		return pubKey, err
+	}
+	return pubKey, m.setDisabled(pubKey, false)
+}
+
+// DeleteKey deletes a key from the wrapped key manager.
+func (m *managed) DeleteKey(pubKey [48]byte) error {
+	importing, ok := m.km.(ImportingKeyManager)
+	if !ok {
+		return ErrCannotImport
+	}
+	if err := importing.DeleteKey(pubKey); err != nil {
+		return err
+	}
+	return m.setDisabled(pubKey, false)
+}
+
+// EnableKey enables a key of the wrapped key manager.
+func (m *managed) EnableKey(pubKey [48]byte) error {
+	if err := m.checkKey(pubKey); err != nil {
+		return err
+	}
+	return m.setDisabled(pubKey, false)
+}
+
+// DisableKey disables a key of the wrapped key manager.
+func (m *managed) DisableKey(pubKey [48]byte) error {
+	if err := m.checkKey(pubKey); err != nil {
+		return err
+	}
+	return m.setDisabled(pubKey, true)
+}
+
+// checkKey returns ErrNoSuchKey if the wrapped key manager does not hold the key.
+func (m *managed) checkKey(pubKey [48]byte) error {
+	keys, err := m.km.FetchValidatingKeys()
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if key == pubKey {
+			return nil
+		}
+	}
+	return ErrNoSuchKey
+}
+
+func (m *managed) isDisabled(pubKey [48]byte) bool {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.disabled[pubKey]
+}
+
+// setDisabled disables or enables the key, and saves the disabled keys if they changed.
+func (m *managed) setDisabled(pubKey [48]byte, disabled bool) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.disabled[pubKey] == disabled {
+		return nil
+	}
+	if disabled {
+		m.disabled[pubKey] = true
+	} else {
+		delete(m.disabled, pubKey)
+	}
+	if err := m.saveDisabledKeys(); err != nil {
+		return err
+	}
+	log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).WithField("disabled", disabled).Info("Updated validating key")
+	return nil
+}
+
+// loadDisabledKeys reads the hex encoded public keys of the disabled keys file, if it exists.
+func (m *managed) loadDisabledKeys() error {
+	if m.disabledKeysFile == "" {
+		return nil
+	}
+	enc, err := ioutil.ReadFile(m.disabledKeysFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not read disabled keys file %s", m.disabledKeysFile)
+	}
+	var pubKeys []string
+	if err := json.Unmarshal(enc, &pubKeys); err != nil {
+		return errors.Wrapf(err, "could not decode disabled keys file %s", m.disabledKeysFile)
+	}
+	for _, pubKey := range pubKeys {
+		key, err := decodePubKey(pubKey)
+		if err != nil {
+			return errors.Wrapf(err, "could not decode disabled keys file %s", m.disabledKeysFile)
+		}
+		m.disabled[key] = true
+	}
+	return nil
+}
+
+// saveDisabledKeys writes the hex encoded public keys of the disabled keys to the disabled keys
+// file. The caller must hold the lock.
+func (m *managed) saveDisabledKeys() error {
+	if m.disabledKeysFile == "" {
+		return nil
+	}
+	pubKeys := make([]string, 0, len(m.disabled))
+	for key := range m.disabled {
+		pubKeys = append(pubKeys, fmt.Sprintf("%#x", key))
+	}
+	sort.Strings(pubKeys)
+	enc, err := json.MarshalIndent(pubKeys, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(m.disabledKeysFile, enc, params.BeaconIoConfig().ReadWritePermissions); err != nil {
+		return errors.Wrapf(err, "could not write disabled keys file %s", m.disabledKeysFile)
+	}
+	return nil
+}
+
+// decodePubKey decodes a hex encoded public key, with or without the 0x prefix.
+func decodePubKey(pubKey string) ([48]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(pubKey, "0x"))
+	if err != nil {
+		return [48]byte{}, err
+	}
+	if len(key) != params.BeaconConfig().BLSPubkeyLength {
+		return [48]byte{}, fmt.Errorf("public key %s is not %d bytes long", pubKey, params.BeaconConfig().BLSPubkeyLength)
+	}
+	return bytesutil.ToBytes48(key), nil
+}
+
+// SignGeneric signs a generic root, unless the key is disabled.
+func (m *protectingManaged) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (bls.Signature, error) {
+	if m.isDisabled(pubKey) {
+		return nil, ErrDenied
+	}
+	return m.protecting.SignGeneric(pubKey, root, domain)
+}
+
+// SignProposal signs a block proposal, unless the key is disabled.
+func (m *protectingManaged) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (bls.Signature, error) {
+	if m.isDisabled(pubKey) {
+		return nil, ErrDenied
+	}
+	return m.protecting.SignProposal(pubKey, domain, data)
+}
+
+// SignAttestation signs an attestation, unless the key is disabled.
+func (m *protectingManaged) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (bls.Signature, error) {
+	if m.isDisabled(pubKey) {
+		return nil, ErrDenied
+	}
+	return m.protecting.SignAttestation(pubKey, domain, data)
+}
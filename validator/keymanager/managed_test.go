@@ -0,0 +1,133 @@
+package keymanager_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func TestManaged_DisablesKeys(t *testing.T) {
+	sks := []bls.SecretKey{bls.RandKey(), bls.RandKey()}
+	pubKey := bytesutil.ToBytes48(sks[0].PublicKey().Marshal())
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	disabledKeysFile := filepath.Join(dir, "disabled-keys.json")
+
+	km, err := keymanager.NewManaged(keymanager.NewDirect(sks), disabledKeysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := km.DisableKey(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] == pubKey {
+		t.Errorf("Expected only the enabled key to validate with, received %d keys", len(keys))
+	}
+	if _, err := km.Sign(pubKey, [32]byte{}); err != keymanager.ErrDenied {
+		t.Errorf("Expected signing with a disabled key to be denied, received %v", err)
+	}
+	if err := km.DisableKey([48]byte{1}); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Expected unknown key error, received %v", err)
+	}
+
+	// The disabled keys remain disabled across restarts.
+	km, err = keymanager.NewManaged(keymanager.NewDirect(sks), disabledKeysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	managedKeys, err := km.ListKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(managedKeys) != 2 {
+		t.Fatalf("Expected 2 keys, received %d", len(managedKeys))
+	}
+	for _, key := range managedKeys {
+		if key.Enabled == (key.PubKey == pubKey) {
+			t.Errorf("Unexpected enabled %v for key %#x", key.Enabled, key.PubKey)
+		}
+	}
+	if err := km.EnableKey(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := km.Sign(pubKey, [32]byte{}); err != nil {
+		t.Errorf("Expected to sign with an enabled key, received %v", err)
+	}
+
+	if _, err := km.ImportKeystore([]byte("{}"), ""); err != keymanager.ErrCannotImport {
+		t.Errorf("Expected direct key manager not to import keys, received %v", err)
+	}
+}
+
+func TestManaged_ImportsAndDeletesEIP2335Keystores(t *testing.T) {
+	dir, _ := writeEIP2335Keystores(t, "secret", 1)
+	sourceDir, sks := writeEIP2335Keystores(t, "other", 1)
+	pubKey := bytesutil.ToBytes48(sks[0].PublicKey().Marshal())
+	keystore, err := ioutil.ReadFile(filepath.Join(sourceDir, "keystore-0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eip2335, _, err := keymanager.NewEIP2335(fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	km, err := keymanager.NewManaged(eip2335, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := km.ImportKeystore(keystore, "wrong"); err == nil {
+		t.Error("Expected import with a wrong passphrase to fail")
+	}
+	imported, err := km.ImportKeystore(keystore, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != pubKey {
+		t.Errorf("Imported key %#x, expected %#x", imported, pubKey)
+	}
+	if _, err := km.ImportKeystore(keystore, "other"); err != keymanager.ErrKeyExists {
+		t.Errorf("Expected key exists error, received %v", err)
+	}
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("Expected 2 keys after import, received %d", len(keys))
+	}
+	target := filepath.Join(dir, fmt.Sprintf("keystore-%x.json", pubKey))
+	if _, err := ioutil.ReadFile(target); err != nil {
+		t.Errorf("Expected the keystore to be written to the keystores directory: %v", err)
+	}
+
+	if err := km.DeleteKey(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := km.DeleteKey(pubKey); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Expected unknown key error, received %v", err)
+	}
+	if _, err := km.Sign(pubKey, [32]byte{}); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Expected unknown key error, received %v", err)
+	}
+	if _, err := ioutil.ReadFile(target); err == nil {
+		t.Error("Expected the keystore to be removed from the keystores directory")
+	}
+}
@@ -87,36 +87,9 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 		return nil, remoteOptsHelp, errors.New("at least one account specifier is required")
 	}
 
-	// Load the client certificates.
-	if opts.Certificates == nil {
-		return nil, remoteOptsHelp, errors.New("certificates are required")
-	}
-	if opts.Certificates.ClientCert == "" {
-		return nil, remoteOptsHelp, errors.New("client certificate is required")
-	}
-	if opts.Certificates.ClientKey == "" {
-		return nil, remoteOptsHelp, errors.New("client key is required")
-	}
-	clientPair, err := tls.LoadX509KeyPair(opts.Certificates.ClientCert, opts.Certificates.ClientKey)
+	tlsCfg, err := remoteTLSConfig(opts.Certificates)
 	if err != nil {
-		return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain client's certificate and/or key")
-	}
-
-	// Load the CA for the server certificate if present.
-	cp := x509.NewCertPool()
-	if opts.Certificates.CACert != "" {
-		serverCA, err := ioutil.ReadFile(opts.Certificates.CACert)
-		if err != nil {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to obtain server's CA certificate")
-		}
-		if !cp.AppendCertsFromPEM(serverCA) {
-			return nil, remoteOptsHelp, errors.Wrap(err, "failed to add server's CA certificate to pool")
-		}
-	}
-
-	tlsCfg := &tls.Config{
-		Certificates: []tls.Certificate{clientPair},
-		RootCAs:      cp,
+		return nil, remoteOptsHelp, err
 	}
 	clientCreds := credentials.NewTLS(tlsCfg)
 
@@ -145,6 +118,42 @@ func NewRemoteWallet(input string) (KeyManager, string, error) {
 	return km, remoteOptsHelp, nil
 }
 
+// remoteTLSConfig returns the TLS configuration with the client certificate of the options, to
+// authenticate with a remote signer.
+func remoteTLSConfig(certs *remoteCertificateOpts) (*tls.Config, error) {
+	// Load the client certificates.
+	if certs == nil {
+		return nil, errors.New("certificates are required")
+	}
+	if certs.ClientCert == "" {
+		return nil, errors.New("client certificate is required")
+	}
+	if certs.ClientKey == "" {
+		return nil, errors.New("client key is required")
+	}
+	clientPair, err := tls.LoadX509KeyPair(certs.ClientCert, certs.ClientKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to obtain client's certificate and/or key")
+	}
+
+	// Load the CA for the server certificate if present.
+	cp := x509.NewCertPool()
+	if certs.CACert != "" {
+		serverCA, err := ioutil.ReadFile(certs.CACert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to obtain server's CA certificate")
+		}
+		if !cp.AppendCertsFromPEM(serverCA) {
+			return nil, errors.New("failed to add server's CA certificate to pool")
+		}
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{clientPair},
+		RootCAs:      cp,
+	}, nil
+}
+
 // FetchValidatingKeys fetches the list of public keys that should be used to validate with.
 func (km *Remote) FetchValidatingKeys() ([][48]byte, error) {
 	res := make([][48]byte, 0, len(km.accounts))
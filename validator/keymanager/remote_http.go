@@ -0,0 +1,360 @@
+package keymanager
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/go-ssz"
+	pb "github.com/prysmaticlabs/prysm/proto/beacon/p2p/v1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+)
+
+const (
+	// remoteHTTPPublicKeysPath is the path of the remote signer API listing the public keys.
+	remoteHTTPPublicKeysPath = "/api/v1/eth2/publicKeys"
+	// remoteHTTPSignPath is the path of the remote signer API signing with a public key.
+	remoteHTTPSignPath = "/api/v1/eth2/sign/"
+	// defaultRemoteHTTPTimeout is the default timeout of the requests to the remote signer.
+	defaultRemoteHTTPTimeout = 10 * time.Second
+)
+
+// RemoteHTTP is a key manager that signs with a remote signer over HTTP, such as Web3Signer.
+type RemoteHTTP struct {
+	url     string
+	client  *http.Client
+	pubKeys map[[48]byte]bool
+}
+
+type remoteHTTPOpts struct {
+	URL          string                 `json:"url"`
+	PublicKeys   []string               `json:"public_keys"`
+	Timeout      string                 `json:"timeout"`
+	Certificates *remoteCertificateOpts `json:"certificates"`
+}
+
+var remoteHTTPOptsHelp = `The remote-http key manager connects to a remote signer over its HTTP API,
+such as Web3Signer.  The options are:
+  - url This is the URL of the remote signer.
+  - public_keys This is the list of public keys to validate with.  If not supplied
+    all public keys of the remote signer will be used.
+  - timeout This is the timeout of the requests to the remote signer, such as 5s.
+    If not supplied it defaults to 10s.
+  - certificates This provides paths to certificates:
+    - ca_cert This is the path to the server's certificate authority certificate file
+    - client_cert This is the path to the client's certificate file
+    - client_key This is the path to the client's key file
+
+An sample keymanager options file (with annotations; these should be removed if
+using this as a template) is:
+
+  {
+    "url":         "https://signer.example.com:9000", // Connect to the remote signer at signer.example.com on port 9000
+    "public_keys": ["0xa99a...e44c"],                 // Only validate with this public key
+    "certificates": {
+      "ca_cert": "/home/eth2/certs/ca.crt"         // Certificate file for the CA that signed the server's certificate
+      "client_cert": "/home/eth2/certs/client.crt" // Certificate file for this client
+      "client_key": "/home/eth2/certs/client.key"  // Key file for this client
+    }
+  }`
+
+// NewRemoteHTTPWallet creates a key manager populated with the keys from the remote signer.
+func NewRemoteHTTPWallet(input string) (KeyManager, string, error) {
+	opts := &remoteHTTPOpts{}
+	if err := json.Unmarshal([]byte(input), opts); err != nil {
+		return nil, remoteHTTPOptsHelp, err
+	}
+
+	u, err := url.Parse(opts.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, remoteHTTPOptsHelp, errors.Errorf("remote signer URL %q is not an https URL", opts.URL)
+	}
+	timeout := defaultRemoteHTTPTimeout
+	if opts.Timeout != "" {
+		if timeout, err = time.ParseDuration(opts.Timeout); err != nil {
+			return nil, remoteHTTPOptsHelp, errors.Wrap(err, "invalid timeout")
+		}
+	}
+	tlsCfg, err := remoteTLSConfig(opts.Certificates)
+	if err != nil {
+		return nil, remoteHTTPOptsHelp, err
+	}
+
+	km := &RemoteHTTP{
+		url: strings.TrimSuffix(opts.URL, "/"),
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg},
+		},
+	}
+	if err := km.RefreshValidatingKeys(opts.PublicKeys); err != nil {
+		return nil, remoteHTTPOptsHelp, errors.Wrap(err, "failed to fetch public keys from remote signer")
+	}
+	return km, remoteHTTPOptsHelp, nil
+}
+
+// RefreshValidatingKeys refreshes the list of validating keys from the remote signer. If public
+// keys are given, only those of them which the remote signer has are used.
+func (km *RemoteHTTP) RefreshValidatingKeys(publicKeys []string) error {
+	resp, err := km.client.Get(km.url + remoteHTTPPublicKeysPath)
+	if err != nil {
+		return err
+	}
+	body, err := readRemoteHTTPResponse(resp)
+	if err != nil {
+		return err
+	}
+	var remoteKeys []string
+	if err := json.Unmarshal(body, &remoteKeys); err != nil {
+		return errors.Wrap(err, "invalid public keys")
+	}
+
+	wanted := make(map[string]bool, len(publicKeys))
+	for _, key := range publicKeys {
+		wanted[strings.ToLower(strings.TrimPrefix(key, "0x"))] = true
+	}
+	pubKeys := make(map[[48]byte]bool, len(remoteKeys))
+	for _, key := range remoteKeys {
+		key = strings.ToLower(strings.TrimPrefix(key, "0x"))
+		if len(wanted) > 0 && !wanted[key] {
+			continue
+		}
+		pubKey, err := hex.DecodeString(key)
+		if err != nil || len(pubKey) != params.BeaconConfig().BLSPubkeyLength {
+			log.WithField("pubKey", key).Warn("Received invalid public key from remote signer; ignoring")
+			continue
+		}
+		pubKeys[bytesutil.ToBytes48(pubKey)] = true
+	}
+	km.pubKeys = pubKeys
+	return nil
+}
+
+// FetchValidatingKeys fetches the list of public keys that should be used to validate with.
+func (km *RemoteHTTP) FetchValidatingKeys() ([][48]byte, error) {
+	res := make([][48]byte, 0, len(km.pubKeys))
+	for pubKey := range km.pubKeys {
+		res = append(res, pubKey)
+	}
+	return res, nil
+}
+
+// Sign without protection is not supported by remote keymanagers.
+func (km *RemoteHTTP) Sign(pubKey [48]byte, root [32]byte) (bls.Signature, error) {
+	return nil, errors.New("remote keymanager does not support unprotected signing")
+}
+
+// SignGeneric signs a generic root. The type of the signing request is derived from the domain.
+func (km *RemoteHTTP) SignGeneric(pubKey [48]byte, root [32]byte, domain [32]byte) (bls.Signature, error) {
+	signingRoot, err := remoteHTTPSigningRoot(root, domain)
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, &remoteHTTPSignRequest{
+		Type:        remoteHTTPSignType(domain),
+		SigningRoot: hexString(signingRoot[:]),
+	})
+}
+
+// SignProposal signs a block proposal for the validator to broadcast.
+func (km *RemoteHTTP) SignProposal(pubKey [48]byte, domain [32]byte, data *ethpb.BeaconBlockHeader) (bls.Signature, error) {
+	root, err := ssz.HashTreeRoot(data)
+	if err != nil {
+		return nil, err
+	}
+	signingRoot, err := remoteHTTPSigningRoot(root, domain)
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, &remoteHTTPSignRequest{
+		Type:        "BLOCK_V2",
+		SigningRoot: hexString(signingRoot[:]),
+		BeaconBlock: &remoteHTTPBeaconBlock{
+			Version: "PHASE0",
+			BlockHeader: &remoteHTTPBlockHeader{
+				Slot:          fmt.Sprint(data.Slot),
+				ProposerIndex: fmt.Sprint(data.ProposerIndex),
+				ParentRoot:    hexString(data.ParentRoot),
+				StateRoot:     hexString(data.StateRoot),
+				BodyRoot:      hexString(data.BodyRoot),
+			},
+		},
+	})
+}
+
+// SignAttestation signs an attestation for the validator to broadcast.
+func (km *RemoteHTTP) SignAttestation(pubKey [48]byte, domain [32]byte, data *ethpb.AttestationData) (bls.Signature, error) {
+	root, err := ssz.HashTreeRoot(data)
+	if err != nil {
+		return nil, err
+	}
+	signingRoot, err := remoteHTTPSigningRoot(root, domain)
+	if err != nil {
+		return nil, err
+	}
+	return km.sign(pubKey, &remoteHTTPSignRequest{
+		Type:        "ATTESTATION",
+		SigningRoot: hexString(signingRoot[:]),
+		Attestation: &remoteHTTPAttestationData{
+			Slot:            fmt.Sprint(data.Slot),
+			Index:           fmt.Sprint(data.CommitteeIndex),
+			BeaconBlockRoot: hexString(data.BeaconBlockRoot),
+			Source: &remoteHTTPCheckpoint{
+				Epoch: fmt.Sprint(data.Source.Epoch),
+				Root:  hexString(data.Source.Root),
+			},
+			Target: &remoteHTTPCheckpoint{
+				Epoch: fmt.Sprint(data.Target.Epoch),
+				Root:  hexString(data.Target.Root),
+			},
+		},
+	})
+}
+
+// remoteHTTPSignRequest is the typed signing payload of the remote signer API. The keymanager
+// interface does not provide the fork info, so the signing root is computed with the domain,
+// and the remote signer verifies the typed data against its own slashing protection.
+type remoteHTTPSignRequest struct {
+	Type        string                     `json:"type"`
+	SigningRoot string                     `json:"signingRoot"`
+	BeaconBlock *remoteHTTPBeaconBlock     `json:"beacon_block,omitempty"`
+	Attestation *remoteHTTPAttestationData `json:"attestation,omitempty"`
+}
+
+type remoteHTTPBeaconBlock struct {
+	Version     string                 `json:"version"`
+	BlockHeader *remoteHTTPBlockHeader `json:"block_header"`
+}
+
+type remoteHTTPBlockHeader struct {
+	Slot          string `json:"slot"`
+	ProposerIndex string `json:"proposer_index"`
+	ParentRoot    string `json:"parent_root"`
+	StateRoot     string `json:"state_root"`
+	BodyRoot      string `json:"body_root"`
+}
+
+type remoteHTTPAttestationData struct {
+	Slot            string                `json:"slot"`
+	Index           string                `json:"index"`
+	BeaconBlockRoot string                `json:"beacon_block_root"`
+	Source          *remoteHTTPCheckpoint `json:"source"`
+	Target          *remoteHTTPCheckpoint `json:"target"`
+}
+
+type remoteHTTPCheckpoint struct {
+	Epoch string `json:"epoch"`
+	Root  string `json:"root"`
+}
+
+// sign posts the signing request for the public key to the remote signer.
+func (km *RemoteHTTP) sign(pubKey [48]byte, req *remoteHTTPSignRequest) (bls.Signature, error) {
+	if !km.pubKeys[pubKey] {
+		return nil, ErrNoSuchKey
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequest(http.MethodPost, km.url+remoteHTTPSignPath+hexString(pubKey[:]), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	resp, err := km.client.Do(httpReq)
+	if err != nil {
+		return nil, errors.Wrap(ErrCannotSign, err.Error())
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, ErrNoSuchKey
+	case http.StatusPreconditionFailed:
+		// The remote signer refuses to sign slashable data.
+		return nil, ErrDenied
+	}
+	respBody, err := readRemoteHTTPResponse(resp)
+	if err != nil {
+		return nil, errors.Wrap(ErrCannotSign, err.Error())
+	}
+
+	// The signature is returned as JSON, or as plain text by older remote signers.
+	signature := strings.TrimSpace(string(respBody))
+	if strings.HasPrefix(signature, "{") {
+		res := &struct {
+			Signature string `json:"signature"`
+		}{}
+		if err := json.Unmarshal(respBody, res); err != nil {
+			return nil, errors.Wrap(err, "invalid signature response")
+		}
+		signature = res.Signature
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	return bls.SignatureFromBytes(sig)
+}
+
+// readRemoteHTTPResponse reads and closes the body of the response. Responses other than 2xx
+// are errors.
+func readRemoteHTTPResponse(resp *http.Response) ([]byte, error) {
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.WithError(err).Error("Failed to close remote signer response body")
+		}
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("remote signer responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// remoteHTTPSignType returns the type of the signing request of the domain.
+func remoteHTTPSignType(domain [32]byte) string {
+	cfg := params.BeaconConfig()
+	switch bytesutil.ToBytes4(domain[:4]) {
+	case cfg.DomainBeaconProposer:
+		return "BLOCK"
+	case cfg.DomainBeaconAttester:
+		return "ATTESTATION"
+	case cfg.DomainRandao:
+		return "RANDAO_REVEAL"
+	case cfg.DomainVoluntaryExit:
+		return "VOLUNTARY_EXIT"
+	case cfg.DomainSelectionProof:
+		return "AGGREGATION_SLOT"
+	case cfg.DomainAggregateAndProof:
+		return "AGGREGATE_AND_PROOF"
+	case cfg.DomainDeposit:
+		return "DEPOSIT"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// remoteHTTPSigningRoot returns the signing root of the object root with the domain.
+func remoteHTTPSigningRoot(root [32]byte, domain [32]byte) ([32]byte, error) {
+	return ssz.HashTreeRoot(&pb.SigningData{
+		ObjectRoot: root[:],
+		Domain:     domain[:],
+	})
+}
+
+func hexString(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,198 @@
+package keymanager_test
+
+import (
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/shared/testutil"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+)
+
+func TestNewRemoteHTTPWallet_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts string
+		err  string
+	}{
+		{
+			name: "Empty",
+			opts: ``,
+			err:  "unexpected end of JSON input",
+		},
+		{
+			name: "NoURL",
+			opts: `{}`,
+			err:  `remote signer URL "" is not an https URL`,
+		},
+		{
+			name: "HTTPURL",
+			opts: `{"url":"http://localhost:9000"}`,
+			err:  `remote signer URL "http://localhost:9000" is not an https URL`,
+		},
+		{
+			name: "BadTimeout",
+			opts: `{"url":"https://localhost:9000","timeout":"soon"}`,
+			err:  "invalid timeout",
+		},
+		{
+			name: "NoCertificates",
+			opts: `{"url":"https://localhost:9000"}`,
+			err:  "certificates are required",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := keymanager.NewRemoteHTTPWallet(test.opts)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("Expected error %q, received %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestRemoteHTTP_Sign(t *testing.T) {
+	secretKey := bls.RandKey()
+	pubKey := bytesutil.ToBytes48(secretKey.PublicKey().Marshal())
+	otherKey := bytesutil.ToBytes48(bls.RandKey().PublicKey().Marshal())
+	slashableSlot := uint64(13)
+
+	var lock sync.Mutex
+	var requests []map[string]interface{}
+	received := func() []map[string]interface{} {
+		lock.Lock()
+		defer lock.Unlock()
+		return append([]map[string]interface{}{}, requests...)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/eth2/publicKeys":
+			keys := []string{
+				"0x" + hex.EncodeToString(pubKey[:]),
+				"0x" + hex.EncodeToString(otherKey[:]),
+			}
+			if err := json.NewEncoder(w).Encode(keys); err != nil {
+				t.Error(err)
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/eth2/sign/0x"+hex.EncodeToString(pubKey[:]):
+			req := make(map[string]interface{})
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			lock.Lock()
+			requests = append(requests, req)
+			lock.Unlock()
+			if att, ok := req["attestation"].(map[string]interface{}); ok && att["slot"] == fmt.Sprint(slashableSlot) {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			signingRoot, err := hex.DecodeString(strings.TrimPrefix(req["signingRoot"].(string), "0x"))
+			if err != nil {
+				t.Error(err)
+			}
+			resp := map[string]string{"signature": "0x" + hex.EncodeToString(secretKey.Sign(signingRoot).Marshal())}
+			if err := json.NewEncoder(w).Encode(resp); err != nil {
+				t.Error(err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := fmt.Sprintf("%s/%s", testutil.TempDir(), "remote-http")
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Error(err)
+		}
+	}()
+	files := map[string]string{
+		"ca.crt":     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		"client.crt": validClientCert,
+		"client.key": validClientKey,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(dir+"/"+name, []byte(content), params.BeaconIoConfig().ReadWritePermissions); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opts := fmt.Sprintf(`{"url":%q,"public_keys":["0x%s"],"certificates":{"ca_cert":"%s/ca.crt","client_cert":"%s/client.crt","client_key":"%s/client.key"}}`,
+		server.URL, hex.EncodeToString(pubKey[:]), dir, dir, dir)
+	km, _, err := keymanager.NewRemoteHTTPWallet(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := km.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != pubKey {
+		t.Fatalf("Expected only the configured public key, received %d keys", len(keys))
+	}
+
+	protecting, ok := km.(keymanager.ProtectingKeyManager)
+	if !ok {
+		t.Fatal("Expected a protecting keymanager")
+	}
+	domain := [32]byte{1}
+	data := &ethpb.AttestationData{
+		Slot:            12,
+		CommitteeIndex:  2,
+		BeaconBlockRoot: make([]byte, 32),
+		Source:          &ethpb.Checkpoint{Epoch: 0, Root: make([]byte, 32)},
+		Target:          &ethpb.Checkpoint{Epoch: 1, Root: make([]byte, 32)},
+	}
+	sig, err := protecting.SignAttestation(pubKey, domain, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reqs := received()
+	if len(reqs) != 1 || reqs[0]["type"] != "ATTESTATION" {
+		t.Fatalf("Expected an attestation signing request, received %v", reqs)
+	}
+	signingRoot, err := hex.DecodeString(strings.TrimPrefix(reqs[0]["signingRoot"].(string), "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sig.Verify(secretKey.PublicKey(), signingRoot) {
+		t.Error("Expected a valid signature of the signing root")
+	}
+
+	if _, err := protecting.SignGeneric(pubKey, [32]byte{'r'}, [32]byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := received(); len(reqs) != 2 || reqs[1]["type"] != "RANDAO_REVEAL" {
+		t.Errorf("Expected a randao reveal signing request, received %v", reqs)
+	}
+
+	data.Slot = slashableSlot
+	if _, err := protecting.SignAttestation(pubKey, domain, data); err != keymanager.ErrDenied {
+		t.Errorf("Expected slashable attestation to be denied, received %v", err)
+	}
+	if _, err := protecting.SignAttestation(otherKey, domain, data); err != keymanager.ErrNoSuchKey {
+		t.Errorf("Expected unknown key error, received %v", err)
+	}
+}
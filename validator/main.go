@@ -13,17 +13,23 @@ import (
 	"time"
 
 	joonix "github.com/joonix/log"
+	"github.com/pkg/errors"
 	ethpb "github.com/prysmaticlabs/ethereumapis/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"github.com/prysmaticlabs/prysm/shared/bytesutil"
 	"github.com/prysmaticlabs/prysm/shared/cmd"
 	"github.com/prysmaticlabs/prysm/shared/debug"
 	"github.com/prysmaticlabs/prysm/shared/featureconfig"
 	"github.com/prysmaticlabs/prysm/shared/logutil"
+	"github.com/prysmaticlabs/prysm/shared/migration"
 	"github.com/prysmaticlabs/prysm/shared/params"
 	"github.com/prysmaticlabs/prysm/shared/version"
 	"github.com/prysmaticlabs/prysm/validator/accounts"
-	"github.com/prysmaticlabs/prysm/validator/client/streaming"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
+	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
 	"github.com/prysmaticlabs/prysm/validator/node"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -48,10 +54,17 @@ var appFlags = []cli.Flag{
 	flags.BeaconRPCProviderFlag,
 	flags.CertFlag,
 	flags.GraffitiFlag,
+	flags.DoppelgangerEpochsFlag,
 	flags.KeystorePathFlag,
+	flags.KeysDirFlag,
+	flags.MnemonicPassphraseFlag,
+	flags.NumAccountsFlag,
+	flags.AccountStartIndexFlag,
 	flags.SourceDirectories,
 	flags.SourceDirectory,
 	flags.TargetDirectory,
+	flags.SlashingProtectionFileFlag,
+	flags.GenesisValidatorsRootFlag,
 	flags.PasswordFlag,
 	flags.DisablePenaltyRewardLogFlag,
 	flags.UnencryptedKeysFlag,
@@ -62,6 +75,10 @@ var appFlags = []cli.Flag{
 	flags.KeyManager,
 	flags.KeyManagerOpts,
 	flags.DisableAccountMetricsFlag,
+	flags.EnableKeyManagerAPIFlag,
+	flags.KeyManagerAPIHostFlag,
+	flags.KeyManagerAPIPortFlag,
+	flags.KeyManagerAPITokenFileFlag,
 	cmd.MonitoringHostFlag,
 	flags.MonitoringPortFlag,
 	flags.SlasherRPCProviderFlag,
@@ -181,20 +198,26 @@ contract in order to activate the validator client`,
 						if err != nil {
 							return err
 						}
-						ctx, cancel := context.WithTimeout(
-							context.Background(), 10*time.Second /* Cancel if cannot connect to beacon node in 10 seconds. */)
-						defer cancel()
-						dialOpts := streaming.ConstructDialOptions(
+						dialOpts := client.ConstructDialOptions(
 							cliCtx.Int(cmd.GrpcMaxCallRecvMsgSizeFlag.Name),
 							cliCtx.String(flags.CertFlag.Name),
 							strings.Split(cliCtx.String(flags.GrpcHeadersFlag.Name), ","),
 							cliCtx.Uint(flags.GrpcRetriesFlag.Name),
 							grpc.WithBlock())
-						endpoint := cliCtx.String(flags.BeaconRPCProviderFlag.Name)
-						conn, err := grpc.DialContext(ctx, endpoint, dialOpts...)
-						if err != nil {
+						// Use the first beacon node which can be dialed.
+						var conn *grpc.ClientConn
+						for _, endpoint := range failover.ParseEndpoints(cliCtx.String(flags.BeaconRPCProviderFlag.Name)) {
+							ctx, cancel := context.WithTimeout(
+								context.Background(), 10*time.Second /* Cancel if cannot connect to beacon node in 10 seconds. */)
+							conn, err = grpc.DialContext(ctx, endpoint, dialOpts...)
+							cancel()
+							if err == nil {
+								break
+							}
 							log.WithError(err).Errorf("Failed to dial beacon node endpoint at %s", endpoint)
-							return err
+						}
+						if conn == nil {
+							return errors.New("could not dial any beacon node endpoint")
 						}
 						err = accounts.RunStatusCommand(pubKeys, ethpb.NewBeaconNodeValidatorClient(conn))
 						if closed := conn.Close(); closed != nil {
@@ -203,6 +226,90 @@ contract in order to activate the validator client`,
 						return err
 					},
 				},
+				{
+					Name:        "generate-mnemonic",
+					Description: `generates a new mnemonic, from which the keys of validator accounts can be derived and recovered`,
+					Action: func(cliCtx *cli.Context) error {
+						mnemonic, err := bls.NewMnemonic()
+						if err != nil {
+							log.WithError(err).Error("Could not generate mnemonic")
+							return err
+						}
+						log.Info("Write down the mnemonic shown below and store it safely, it is the only way to recover your validator keys")
+						fmt.Printf(`
+========================Mnemonic=======================
+
+%s
+
+===================================================================
+`, mnemonic)
+						return nil
+					},
+				},
+				{
+					Name: "derive",
+					Description: `derives the signing and withdrawal keys of validator accounts from a mnemonic along the EIP-2334
+paths m/12381/3600/i/0/0 and m/12381/3600/i/0, and outputs the deposit data needed to activate them - use
+--keymanager=derived to validate with the derived keys`,
+					Flags: []cli.Flag{
+						flags.MnemonicPassphraseFlag,
+						flags.NumAccountsFlag,
+						flags.AccountStartIndexFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						log.Info("Enter your mnemonic:")
+						mnemonic, err := cmd.StdInPasswordReader{}.ReadPassword()
+						if err != nil {
+							log.WithError(err).Error("Could not read mnemonic")
+							return err
+						}
+						derivedAccounts, err := accounts.DeriveAccounts(
+							strings.TrimSpace(mnemonic),
+							cliCtx.String(flags.MnemonicPassphraseFlag.Name),
+							cliCtx.Uint64(flags.AccountStartIndexFlag.Name),
+							cliCtx.Uint64(flags.NumAccountsFlag.Name),
+						)
+						if err != nil {
+							log.WithError(err).Error("Could not derive validator accounts")
+							return err
+						}
+						return accounts.PrintDerivedAccounts(derivedAccounts)
+					},
+				},
+				{
+					Name: "import",
+					Description: `imports EIP-2335 keystores, such as the ones generated by the deposit CLI, into a directory
+used by the 'eip2335' keymanager - every keystore must be encrypted with the given password`,
+					Flags: []cli.Flag{
+						flags.KeysDirFlag,
+						flags.KeystorePathFlag,
+						flags.PasswordFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						keysDir := cliCtx.String(flags.KeysDirFlag.Name)
+						if keysDir == "" {
+							return fmt.Errorf("--%s is required", flags.KeysDirFlag.Name)
+						}
+						keystorePath, passphrase, err := accounts.HandleEmptyKeystoreFlags(cliCtx, false /*confirmPassword*/)
+						if err != nil {
+							log.WithError(err).Error("Could not read keystore path and/or password")
+							return err
+						}
+						pubKeys, err := keymanager.ImportEIP2335Keystores(keysDir, keystorePath, passphrase)
+						if err != nil {
+							log.WithError(err).Error("Importing keystores failed")
+							return err
+						}
+						for _, pubKey := range pubKeys {
+							log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Imported keystore")
+						}
+						log.Infof(
+							"Import completed successfully, use --keymanager=eip2335 --keymanageropts='{\"path\":%q}' to validate with the imported keys",
+							keystorePath,
+						)
+						return nil
+					},
+				},
 				{
 					Name:        "change-password",
 					Description: "changes password for all keys located in a keystore",
@@ -275,6 +382,81 @@ contract in order to activate the validator client`,
 				},
 			},
 		},
+		{
+			Name:     "slashing-protection",
+			Category: "slashing-protection",
+			Usage:    "defines commands to move the validator's slashing protection history between clients and hosts",
+			Subcommands: []*cli.Command{
+				{
+					Name: "export",
+					Description: `exports the proposal and attestation history of the validator database to a
+slashing protection interchange file, as defined by EIP-3076`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.SlashingProtectionFileFlag,
+						flags.GenesisValidatorsRootFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+						file := cliCtx.String(flags.SlashingProtectionFileFlag.Name)
+						root := cliCtx.String(flags.GenesisValidatorsRootFlag.Name)
+
+						if err := accounts.ExportSlashingProtection(context.Background(), dataDir, file, root); err != nil {
+							log.WithError(err).Error("Exporting slashing protection history failed")
+							return err
+						}
+						log.WithField("file", file).Info("Export completed successfully")
+						return nil
+					},
+				},
+				{
+					Name: "import",
+					Description: `imports a slashing protection interchange file, as defined by EIP-3076, merging
+it with the existing history of the validator database`,
+					Flags: []cli.Flag{
+						cmd.DataDirFlag,
+						flags.SlashingProtectionFileFlag,
+						flags.GenesisValidatorsRootFlag,
+					},
+					Action: func(cliCtx *cli.Context) error {
+						dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+						file := cliCtx.String(flags.SlashingProtectionFileFlag.Name)
+						root := cliCtx.String(flags.GenesisValidatorsRootFlag.Name)
+
+						if err := accounts.ImportSlashingProtection(context.Background(), dataDir, file, root); err != nil {
+							log.WithError(err).Error("Importing slashing protection history failed")
+							return err
+						}
+						log.WithField("file", file).Info("Import completed successfully")
+						return nil
+					},
+				},
+			},
+		},
+		{
+			Name:     "db",
+			Category: "db",
+			Usage:    "defines commands to maintain the validator database",
+			Subcommands: []*cli.Command{
+				migration.Command(
+					[]cli.Flag{cmd.DataDirFlag},
+					func(cliCtx *cli.Context) (uint64, []*migration.Migration, error) {
+						return db.PendingMigrations(cliCtx.String(cmd.DataDirFlag.Name))
+					},
+					func(cliCtx *cli.Context) error {
+						// Opening the database applies the pending migrations.
+						store, err := db.GetKVStore(cliCtx.String(cmd.DataDirFlag.Name))
+						if err != nil {
+							return err
+						}
+						if store == nil {
+							return errors.New("no validator database found in the data directory")
+						}
+						return store.Close()
+					},
+				),
+			},
+		},
 	}
 
 	app.Flags = appFlags
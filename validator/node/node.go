@@ -5,10 +5,12 @@ package node
 
 import (
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -22,11 +24,12 @@ import (
 	"github.com/prysmaticlabs/prysm/shared/prometheus"
 	"github.com/prysmaticlabs/prysm/shared/tracing"
 	"github.com/prysmaticlabs/prysm/shared/version"
-	"github.com/prysmaticlabs/prysm/validator/client/polling"
-	"github.com/prysmaticlabs/prysm/validator/client/streaming"
+	"github.com/prysmaticlabs/prysm/validator/client"
+	"github.com/prysmaticlabs/prysm/validator/client/failover"
 	"github.com/prysmaticlabs/prysm/validator/db"
 	"github.com/prysmaticlabs/prysm/validator/flags"
 	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/rpc"
 	slashing_protection "github.com/prysmaticlabs/prysm/validator/slashing-protection"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v2"
@@ -34,12 +37,20 @@ import (
 
 var log = logrus.WithField("prefix", "node")
 
+const (
+	// disabledKeysFileName is the name of the file of the keys disabled with the key management API.
+	disabledKeysFileName = "disabled-keys.json"
+	// keyManagerAPITokenFileName is the default name of the file of the key management API token.
+	keyManagerAPITokenFileName = "keymanager-api-token"
+)
+
 // ValidatorClient defines an instance of a sharding validator that manages
 // the entire lifecycle of services attached to it participating in
 // Ethereum Serenity.
 type ValidatorClient struct {
 	cliCtx   *cli.Context
 	services *shared.ServiceRegistry // Lifecycle and service store.
+	db       *db.Store
 	lock     sync.RWMutex
 	stop     chan struct{} // Channel to wait for termination notifications.
 }
@@ -83,6 +94,27 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 		return nil, err
 	}
 
+	dataDir := cliCtx.String(cmd.DataDirFlag.Name)
+	if dataDir == "" {
+		dataDir = cmd.DefaultDataDir()
+		if dataDir == "" {
+			log.Fatal(
+				"Could not determine your system's HOME path, please specify a --datadir you wish " +
+					"to use for your validator data",
+			)
+		}
+	}
+
+	var managedKeyManager keymanager.ManagedKeyManager
+	if cliCtx.Bool(flags.EnableKeyManagerAPIFlag.Name) {
+		// Disabled keys are kept alongside the validator database.
+		managedKeyManager, err = keymanager.NewManaged(keyManager, filepath.Join(dataDir, disabledKeysFileName))
+		if err != nil {
+			return nil, err
+		}
+		keyManager = managedKeyManager
+	}
+
 	pubKeys, err := keyManager.FetchValidatingKeys()
 	if err != nil {
 		log.WithError(err).Error("Failed to obtain public keys for validation")
@@ -99,27 +131,22 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 
 	clearFlag := cliCtx.Bool(cmd.ClearDB.Name)
 	forceClearFlag := cliCtx.Bool(cmd.ForceClearDB.Name)
-	dataDir := cliCtx.String(cmd.DataDirFlag.Name)
 	if clearFlag || forceClearFlag {
 		pubkeys, err := keyManager.FetchValidatingKeys()
 		if err != nil {
 			return nil, err
 		}
-		if dataDir == "" {
-			dataDir = cmd.DefaultDataDir()
-			if dataDir == "" {
-				log.Fatal(
-					"Could not determine your system's HOME path, please specify a --datadir you wish " +
-						"to use for your validator data",
-				)
-			}
-
-		}
 		if err := clearDB(dataDir, pubkeys, forceClearFlag); err != nil {
 			return nil, err
 		}
 	}
 	log.WithField("databasePath", dataDir).Info("Checking DB")
+	// The validator database is shared by the services, as it is opened once per process.
+	valDB, err := db.NewKVStore(dataDir, pubKeys)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not initialize db in dir %s", dataDir)
+	}
+	ValidatorClient.db = valDB
 
 	if err := ValidatorClient.registerPrometheusService(); err != nil {
 		return nil, err
@@ -132,6 +159,11 @@ func NewValidatorClient(cliCtx *cli.Context) (*ValidatorClient, error) {
 	if err := ValidatorClient.registerClientService(keyManager); err != nil {
 		return nil, err
 	}
+	if managedKeyManager != nil {
+		if err := ValidatorClient.registerKeyManagerAPIService(managedKeyManager, dataDir); err != nil {
+			return nil, err
+		}
+	}
 
 	return ValidatorClient, nil
 }
@@ -176,6 +208,9 @@ func (s *ValidatorClient) Close() {
 	defer s.lock.Unlock()
 
 	s.services.StopAll()
+	if err := s.db.Close(); err != nil {
+		log.WithError(err).Error("Failed to close database")
+	}
 	log.Info("Stopping sharding validator")
 
 	close(s.stop)
@@ -191,8 +226,7 @@ func (s *ValidatorClient) registerPrometheusService() error {
 }
 
 func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager) error {
-	endpoint := s.cliCtx.String(flags.BeaconRPCProviderFlag.Name)
-	dataDir := s.cliCtx.String(cmd.DataDirFlag.Name)
+	endpoints := failover.ParseEndpoints(s.cliCtx.String(flags.BeaconRPCProviderFlag.Name))
 	logValidatorBalances := !s.cliCtx.Bool(flags.DisablePenaltyRewardLogFlag.Name)
 	emitAccountMetrics := !s.cliCtx.Bool(flags.DisableAccountMetricsFlag.Name)
 	cert := s.cliCtx.String(flags.CertFlag.Name)
@@ -204,29 +238,9 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 	if err := s.services.FetchService(&sp); err == nil {
 		protector = sp
 	}
-	if featureconfig.Get().EnableStreamDuties {
-		v, err := streaming.NewValidatorService(context.Background(), &streaming.Config{
-			Endpoint:                   endpoint,
-			DataDir:                    dataDir,
-			KeyManager:                 keyManager,
-			LogValidatorBalances:       logValidatorBalances,
-			EmitAccountMetrics:         emitAccountMetrics,
-			CertFlag:                   cert,
-			GraffitiFlag:               graffiti,
-			GrpcMaxCallRecvMsgSizeFlag: maxCallRecvMsgSize,
-			GrpcRetriesFlag:            grpcRetries,
-			GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
-			Protector:                  protector,
-		})
-
-		if err != nil {
-			return errors.Wrap(err, "could not initialize client service")
-		}
-		return s.services.RegisterService(v)
-	}
-	v, err := polling.NewValidatorService(context.Background(), &polling.Config{
-		Endpoint:                   endpoint,
-		DataDir:                    dataDir,
+	v, err := client.NewValidatorService(context.Background(), &client.Config{
+		Endpoints:                  endpoints,
+		ValidatorDB:                s.db,
 		KeyManager:                 keyManager,
 		LogValidatorBalances:       logValidatorBalances,
 		EmitAccountMetrics:         emitAccountMetrics,
@@ -236,6 +250,7 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 		GrpcRetriesFlag:            grpcRetries,
 		GrpcHeadersFlag:            s.cliCtx.String(flags.GrpcHeadersFlag.Name),
 		Protector:                  protector,
+		DoppelgangerEpochs:         s.cliCtx.Uint64(flags.DoppelgangerEpochsFlag.Name),
 	})
 
 	if err != nil {
@@ -243,6 +258,33 @@ func (s *ValidatorClient) registerClientService(keyManager keymanager.KeyManager
 	}
 	return s.services.RegisterService(v)
 }
+
+func (s *ValidatorClient) registerKeyManagerAPIService(keyManager keymanager.ManagedKeyManager, dataDir string) error {
+	tokenFile := s.cliCtx.String(flags.KeyManagerAPITokenFileFlag.Name)
+	if tokenFile == "" {
+		tokenFile = filepath.Join(dataDir, keyManagerAPITokenFileName)
+	}
+	var genesisValidatorsRoot []byte
+	if root := s.cliCtx.String(flags.GenesisValidatorsRootFlag.Name); root != "" {
+		var err error
+		genesisValidatorsRoot, err = hex.DecodeString(strings.TrimPrefix(root, "0x"))
+		if err != nil {
+			return errors.Wrap(err, "could not decode genesis validators root")
+		}
+	}
+	server, err := rpc.NewServer(&rpc.Config{
+		Addr:                  fmt.Sprintf("%s:%d", s.cliCtx.String(flags.KeyManagerAPIHostFlag.Name), s.cliCtx.Int(flags.KeyManagerAPIPortFlag.Name)),
+		TokenFile:             tokenFile,
+		KeyManager:            keyManager,
+		ValidatorDB:           s.db,
+		GenesisValidatorsRoot: genesisValidatorsRoot,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not initialize key management API")
+	}
+	return s.services.RegisterService(server)
+}
+
 func (s *ValidatorClient) registerSlasherClientService() error {
 	endpoint := s.cliCtx.String(flags.SlasherRPCProviderFlag.Name)
 	if endpoint == "" {
@@ -316,10 +358,16 @@ func selectKeyManager(ctx *cli.Context) (keymanager.KeyManager, error) {
 		km, help, err = keymanager.NewUnencrypted(opts)
 	case "keystore":
 		km, help, err = keymanager.NewKeystore(opts)
+	case "eip2335":
+		km, help, err = keymanager.NewEIP2335(opts)
+	case "derived":
+		km, help, err = keymanager.NewDerived(opts)
 	case "wallet":
 		km, help, err = keymanager.NewWallet(opts)
 	case "remote":
 		km, help, err = keymanager.NewRemoteWallet(opts)
+	case "remote-http":
+		km, help, err = keymanager.NewRemoteHTTPWallet(opts)
 	default:
 		return nil, fmt.Errorf("unknown keymanager %q", manager)
 	}
@@ -0,0 +1,241 @@
+package rpc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/slashing-protection/interchange"
+)
+
+// keyResponse is a validating key of the validator client.
+type keyResponse struct {
+	PubKey  string `json:"pubkey"`
+	Enabled bool   `json:"enabled"`
+}
+
+// listKeysResponse lists the validating keys of the validator client.
+type listKeysResponse struct {
+	Keys []*keyResponse `json:"keys"`
+}
+
+// importKeyRequest imports an EIP-2335 keystore encrypted with the passphrase.
+type importKeyRequest struct {
+	Keystore   json.RawMessage `json:"keystore"`
+	Passphrase string          `json:"passphrase"`
+}
+
+// errorResponse describes why a request failed.
+type errorResponse struct {
+	Message string `json:"message"`
+}
+
+// routes returns the handler of the key management API. GET /keys lists the keys, and POST /keys
+// imports a keystore. DELETE /keys/{pubkey} deletes a key and responds with its slashing protection
+// history, while POST /keys/{pubkey}/enable and POST /keys/{pubkey}/disable enable and disable it.
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			s.listKeys(w, r)
+		case http.MethodPost:
+			s.importKey(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s not allowed", r.Method))
+		}
+	})
+	mux.HandleFunc("/keys/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/keys/"), "/")
+		pubKey, err := decodePubKey(parts[0])
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		switch {
+		case len(parts) == 1 && r.Method == http.MethodDelete:
+			s.deleteKey(w, r, pubKey)
+		case len(parts) == 2 && parts[1] == "enable" && r.Method == http.MethodPost:
+			s.enableKey(w, r, pubKey, true)
+		case len(parts) == 2 && parts[1] == "disable" && r.Method == http.MethodPost:
+			s.enableKey(w, r, pubKey, false)
+		default:
+			writeError(w, http.StatusNotFound, errors.Errorf("no route for %s %s", r.Method, r.URL.Path))
+		}
+	})
+	return mux
+}
+
+func (s *Server) listKeys(w http.ResponseWriter, _ *http.Request) {
+	keys, err := s.keyManager.ListKeys()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "could not list keys"))
+		return
+	}
+	resp := &listKeysResponse{Keys: make([]*keyResponse, len(keys))}
+	for i, key := range keys {
+		resp.Keys[i] = &keyResponse{PubKey: fmt.Sprintf("%#x", key.PubKey), Enabled: key.Enabled}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// importKey imports the keystore, and initializes the slashing protection of its key before the
+// validator client starts performing its duties.
+func (s *Server) importKey(w http.ResponseWriter, r *http.Request) {
+	req := &importKeyRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode request"))
+		return
+	}
+	if len(req.Keystore) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("a keystore is required"))
+		return
+	}
+	pubKey, err := s.keyManager.ImportKeystore(req.Keystore, req.Passphrase)
+	switch err {
+	case nil:
+	case keymanager.ErrKeyExists:
+		writeError(w, http.StatusConflict, errors.Errorf("key %#x already exists", pubKey))
+		return
+	case keymanager.ErrCannotImport:
+		writeError(w, http.StatusNotImplemented, err)
+		return
+	default:
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.db.InitializePublicKeys(r.Context(), [][48]byte{pubKey}); err != nil {
+		if deleteErr := s.keyManager.DeleteKey(pubKey); deleteErr != nil {
+			log.WithError(deleteErr).Error("Could not delete key after failing to initialize its slashing protection")
+		}
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "could not initialize slashing protection"))
+		return
+	}
+	log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Imported validating key")
+	writeJSON(w, http.StatusCreated, &keyResponse{PubKey: fmt.Sprintf("%#x", pubKey), Enabled: true})
+}
+
+// deleteKey deletes the key, and responds with its slashing protection history in the EIP-3076
+// interchange format, so that it can be imported wherever the key validates next. The key is
+// disabled first, so that nothing is signed after its history is exported.
+func (s *Server) deleteKey(w http.ResponseWriter, r *http.Request, pubKey [48]byte) {
+	root := s.genesisValidatorsRoot
+	if param := r.URL.Query().Get("genesis_validators_root"); param != "" {
+		var err error
+		root, err = hex.DecodeString(strings.TrimPrefix(param, "0x"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, errors.Wrap(err, "could not decode genesis validators root"))
+			return
+		}
+	}
+	if len(root) != 32 {
+		writeError(w, http.StatusBadRequest, errors.New("a 32 byte genesis validators root is required to export slashing protection history"))
+		return
+	}
+
+	enabled, err := s.isEnabled(pubKey)
+	if err != nil {
+		writeKeyError(w, pubKey, err)
+		return
+	}
+	if err := s.keyManager.DisableKey(pubKey); err != nil {
+		writeKeyError(w, pubKey, err)
+		return
+	}
+	history, err := interchange.ExportPublicKeys(r.Context(), s.db, root, [][48]byte{pubKey})
+	if err == nil {
+		err = s.keyManager.DeleteKey(pubKey)
+	}
+	if err != nil {
+		// The key is kept, restore it as it was.
+		if enabled {
+			if enableErr := s.keyManager.EnableKey(pubKey); enableErr != nil {
+				log.WithError(enableErr).Error("Could not enable key after failing to delete it")
+			}
+		}
+		writeKeyError(w, pubKey, err)
+		return
+	}
+	log.WithField("pubKey", fmt.Sprintf("%#x", pubKey)).Info("Deleted validating key")
+	writeJSON(w, http.StatusOK, history)
+}
+
+// enableKey enables or disables the key. The slashing protection of enabled keys is initialized, as
+// keys disabled when the validator client started were not.
+func (s *Server) enableKey(w http.ResponseWriter, r *http.Request, pubKey [48]byte, enabled bool) {
+	if _, err := s.isEnabled(pubKey); err != nil {
+		writeKeyError(w, pubKey, err)
+		return
+	}
+	var err error
+	if enabled {
+		if err := s.db.InitializePublicKeys(r.Context(), [][48]byte{pubKey}); err != nil {
+			writeError(w, http.StatusInternalServerError, errors.Wrap(err, "could not initialize slashing protection"))
+			return
+		}
+		err = s.keyManager.EnableKey(pubKey)
+	} else {
+		err = s.keyManager.DisableKey(pubKey)
+	}
+	if err != nil {
+		writeKeyError(w, pubKey, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &keyResponse{PubKey: fmt.Sprintf("%#x", pubKey), Enabled: enabled})
+}
+
+// isEnabled returns whether the key is enabled, or ErrNoSuchKey if the key manager does not hold it.
+func (s *Server) isEnabled(pubKey [48]byte) (bool, error) {
+	keys, err := s.keyManager.ListKeys()
+	if err != nil {
+		return false, err
+	}
+	for _, key := range keys {
+		if key.PubKey == pubKey {
+			return key.Enabled, nil
+		}
+	}
+	return false, keymanager.ErrNoSuchKey
+}
+
+// writeKeyError writes the error of a request on a key.
+func writeKeyError(w http.ResponseWriter, pubKey [48]byte, err error) {
+	switch err {
+	case keymanager.ErrNoSuchKey:
+		writeError(w, http.StatusNotFound, errors.Errorf("no key %#x", pubKey))
+	case keymanager.ErrCannotImport:
+		writeError(w, http.StatusNotImplemented, err)
+	default:
+		writeError(w, http.StatusInternalServerError, err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, &errorResponse{Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, code int, resp interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.WithError(err).Error("Could not write response")
+	}
+}
+
+// decodePubKey decodes a 0x prefixed hex public key.
+func decodePubKey(pubKey string) ([48]byte, error) {
+	key, err := hex.DecodeString(strings.TrimPrefix(pubKey, "0x"))
+	if err != nil {
+		return [48]byte{}, errors.Wrap(err, "could not decode public key")
+	}
+	if len(key) != params.BeaconConfig().BLSPubkeyLength {
+		return [48]byte{}, errors.Errorf("public key %s is not %d bytes long", pubKey, params.BeaconConfig().BLSPubkeyLength)
+	}
+	return bytesutil.ToBytes48(key), nil
+}
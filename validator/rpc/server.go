@@ -0,0 +1,143 @@
+// Package rpc defines the key management API of the validator client, a local HTTP API
+// authenticated with a bearer token to list, import, delete, enable and disable the validating
+// keys of the validator client while it is running.
+package rpc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.WithField("prefix", "rpc")
+
+// tokenLength is the length in bytes of the generated authentication tokens.
+const tokenLength = 32
+
+// Server serves the key management API.
+type Server struct {
+	addr                  string
+	token                 string
+	keyManager            keymanager.ManagedKeyManager
+	db                    iface.ValidatorDB
+	genesisValidatorsRoot []byte
+	server                *http.Server
+	failStatus            error
+}
+
+// Config for the key management API server.
+type Config struct {
+	// Addr is the host:port address to listen on.
+	Addr string
+	// TokenFile is the file of the authentication token. A token is generated and written to
+	// the file if it does not exist.
+	TokenFile  string
+	KeyManager keymanager.ManagedKeyManager
+	// ValidatorDB initializes the slashing protection of imported keys, and exports the one
+	// of deleted keys.
+	ValidatorDB iface.ValidatorDB
+	// GenesisValidatorsRoot is the default genesis validators root of the slashing protection
+	// history exported for deleted keys.
+	GenesisValidatorsRoot []byte
+}
+
+// NewServer creates a key management API server, loading or generating its authentication token.
+func NewServer(cfg *Config) (*Server, error) {
+	token, err := loadOrCreateToken(cfg.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		addr:                  cfg.Addr,
+		token:                 token,
+		keyManager:            cfg.KeyManager,
+		db:                    cfg.ValidatorDB,
+		genesisValidatorsRoot: cfg.GenesisValidatorsRoot,
+	}
+	s.server = &http.Server{Addr: cfg.Addr, Handler: s.authenticate(s.routes())}
+	return s, nil
+}
+
+// Start listening for key management API requests.
+func (s *Server) Start() {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		log.WithError(err).Errorf("Could not listen to %s", s.addr)
+		s.failStatus = err
+		return
+	}
+	log.WithField("address", s.addr).Info("Starting key management API")
+	go func() {
+		if err := s.server.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.WithError(err).Error("Key management API stopped")
+			s.failStatus = err
+		}
+	}()
+}
+
+// Stop the key management API gracefully.
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Status returns the error which stopped the key management API, if any.
+func (s *Server) Status() error {
+	return s.failStatus
+}
+
+// authenticate only serves the requests bearing the authentication token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid authentication token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loadOrCreateToken reads the authentication token of the token file, and generates a random one
+// written to the token file if it does not exist.
+func loadOrCreateToken(tokenFile string) (string, error) {
+	if tokenFile == "" {
+		return "", errors.New("a token file is required to authenticate key management API requests")
+	}
+	enc, err := ioutil.ReadFile(tokenFile)
+	if err == nil {
+		token := strings.TrimSpace(string(enc))
+		if token == "" {
+			return "", errors.Errorf("token file %s is empty", tokenFile)
+		}
+		return token, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", errors.Wrapf(err, "could not read token file %s", tokenFile)
+	}
+
+	secret := make([]byte, tokenLength)
+	if _, err := rand.Read(secret); err != nil {
+		return "", errors.Wrap(err, "could not generate token")
+	}
+	token := hex.EncodeToString(secret)
+	if err := ioutil.WriteFile(tokenFile, []byte(token), params.BeaconIoConfig().ReadWritePermissions); err != nil {
+		return "", errors.Wrapf(err, "could not write token file %s", tokenFile)
+	}
+	log.WithField("tokenFile", tokenFile).Info("Generated key management API token")
+	return token, nil
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/shared/bls"
+	"github.com/prysmaticlabs/prysm/shared/bytesutil"
+	"github.com/prysmaticlabs/prysm/validator/db"
+	"github.com/prysmaticlabs/prysm/validator/keymanager"
+	"github.com/prysmaticlabs/prysm/validator/slashing-protection/interchange"
+	keystorev4 "github.com/wealdtech/go-eth2-wallet-encryptor-keystorev4"
+)
+
+func encryptKeystore(t *testing.T, sk bls.SecretKey, passphrase string) []byte {
+	crypto, err := keystorev4.New().Encrypt(sk.Marshal(), passphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := json.Marshal(map[string]interface{}{
+		"crypto":  crypto,
+		"pubkey":  fmt.Sprintf("%x", sk.PublicKey().Marshal()),
+		"uuid":    "9f75a3fa-1e5a-49f9-be3d-f5a19779c6fa",
+		"version": 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+func setupServer(t *testing.T) (*Server, *httptest.Server, bls.SecretKey) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := os.RemoveAll(dir); err != nil {
+			t.Log(err)
+		}
+	})
+	sk := bls.RandKey()
+	if err := ioutil.WriteFile(filepath.Join(dir, "keystore-0.json"), encryptKeystore(t, sk, "secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	eip2335, _, err := keymanager.NewEIP2335(fmt.Sprintf(`{"path":%q,"passphrase":"secret"}`, dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	km, err := keymanager.NewManaged(eip2335, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := bytesutil.ToBytes48(sk.PublicKey().Marshal())
+	s, err := NewServer(&Config{
+		TokenFile:             filepath.Join(dir, "token"),
+		KeyManager:            km,
+		ValidatorDB:           db.SetupDB(t, [][48]byte{pubKey}),
+		GenesisValidatorsRoot: make([]byte, 32),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(s.server.Handler)
+	t.Cleanup(server.Close)
+	return s, server, sk
+}
+
+func request(t *testing.T, s *Server, server *httptest.Server, method string, path string, body []byte, resp interface{}) int {
+	req, err := http.NewRequest(method, server.URL+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	res, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if resp != nil && res.StatusCode < http.StatusBadRequest {
+		if err := json.NewDecoder(res.Body).Decode(resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res.StatusCode
+}
+
+func TestServer_RequiresToken(t *testing.T) {
+	s, server, _ := setupServer(t)
+	if len(s.token) != 2*tokenLength {
+		t.Errorf("Expected a generated token of %d hex characters, received %q", 2*tokenLength, s.token)
+	}
+	for _, token := range []string{"", "Bearer wrong"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/keys", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", token)
+		res, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := res.Body.Close(); err != nil {
+			t.Error(err)
+		}
+		if res.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Expected status %d with token %q, received %d", http.StatusUnauthorized, token, res.StatusCode)
+		}
+	}
+}
+
+func TestServer_ManagesKeys(t *testing.T) {
+	s, server, sk := setupServer(t)
+	pubKey := fmt.Sprintf("%#x", sk.PublicKey().Marshal())
+
+	list := &listKeysResponse{}
+	if code := request(t, s, server, http.MethodGet, "/keys", nil, list); code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", code)
+	}
+	if len(list.Keys) != 1 || list.Keys[0].PubKey != pubKey || !list.Keys[0].Enabled {
+		t.Fatalf("Unexpected keys %v", list.Keys)
+	}
+
+	// Import a key.
+	newKey := bls.RandKey()
+	newPubKey := fmt.Sprintf("%#x", newKey.PublicKey().Marshal())
+	body, err := json.Marshal(map[string]interface{}{
+		"keystore":   json.RawMessage(encryptKeystore(t, newKey, "new")),
+		"passphrase": "new",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := &keyResponse{}
+	if code := request(t, s, server, http.MethodPost, "/keys", body, imported); code != http.StatusCreated {
+		t.Fatalf("Unexpected status %d", code)
+	}
+	if imported.PubKey != newPubKey {
+		t.Errorf("Imported key %s, expected %s", imported.PubKey, newPubKey)
+	}
+	if code := request(t, s, server, http.MethodPost, "/keys", body, nil); code != http.StatusConflict {
+		t.Errorf("Expected status %d importing a key twice, received %d", http.StatusConflict, code)
+	}
+	if _, err := s.db.ProposalHistoryForEpoch(context.Background(), newKey.PublicKey().Marshal(), 0); err != nil {
+		t.Errorf("Expected the proposal history of the imported key to be initialized: %v", err)
+	}
+
+	// Disable and enable the key.
+	if code := request(t, s, server, http.MethodPost, "/keys/"+newPubKey+"/disable", nil, nil); code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", code)
+	}
+	keys, err := s.keyManager.FetchValidatingKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("Expected the disabled key not to validate, received %d keys", len(keys))
+	}
+	if code := request(t, s, server, http.MethodPost, "/keys/"+newPubKey+"/enable", nil, nil); code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", code)
+	}
+
+	// Delete the key, exporting its slashing protection history.
+	history := &interchange.Interchange{}
+	if code := request(t, s, server, http.MethodDelete, "/keys/"+newPubKey, nil, history); code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", code)
+	}
+	if len(history.Data) != 1 || history.Data[0].PubKey != newPubKey {
+		t.Errorf("Expected the slashing protection history of the deleted key, received %v", history.Data)
+	}
+	if code := request(t, s, server, http.MethodDelete, "/keys/"+newPubKey, nil, nil); code != http.StatusNotFound {
+		t.Errorf("Expected status %d deleting an unknown key, received %d", http.StatusNotFound, code)
+	}
+	if code := request(t, s, server, http.MethodDelete, "/keys/"+strings.TrimPrefix(pubKey, "0x")+"?genesis_validators_root=0x01", nil, nil); code != http.StatusBadRequest {
+		t.Errorf("Expected status %d with an invalid genesis validators root, received %d", http.StatusBadRequest, code)
+	}
+	list = &listKeysResponse{}
+	if code := request(t, s, server, http.MethodGet, "/keys", nil, list); code != http.StatusOK {
+		t.Fatalf("Unexpected status %d", code)
+	}
+	if len(list.Keys) != 1 || list.Keys[0].PubKey != pubKey {
+		t.Errorf("Unexpected keys after deletion %v", list.Keys)
+	}
+}
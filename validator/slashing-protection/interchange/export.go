@@ -0,0 +1,124 @@
+package interchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	"go.opencensus.io/trace"
+)
+
+// ExportJSON writes the proposal and attestation history of every validator public key in the
+// database to w, in the interchange format.
+func ExportJSON(ctx context.Context, validatorDB iface.ValidatorDB, genesisValidatorsRoot []byte, w io.Writer) error {
+	interchange, err := Export(ctx, validatorDB, genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(interchange)
+}
+
+// Export retrieves the proposal and attestation history of every validator public key in the database.
+func Export(ctx context.Context, validatorDB iface.ValidatorDB, genesisValidatorsRoot []byte) (*Interchange, error) {
+	ctx, span := trace.StartSpan(ctx, "Interchange.Export")
+	defer span.End()
+
+	pubKeys, err := validatorDB.PublicKeys(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get public keys")
+	}
+	return ExportPublicKeys(ctx, validatorDB, genesisValidatorsRoot, pubKeys)
+}
+
+// ExportPublicKeys retrieves the proposal and attestation history of the validator public keys.
+func ExportPublicKeys(ctx context.Context, validatorDB iface.ValidatorDB, genesisValidatorsRoot []byte, pubKeys [][48]byte) (*Interchange, error) {
+	ctx, span := trace.StartSpan(ctx, "Interchange.ExportPublicKeys")
+	defer span.End()
+
+	if len(genesisValidatorsRoot) != 32 {
+		return nil, errors.New("a 32 byte genesis validators root is required to export slashing protection history")
+	}
+	attHistories, err := validatorDB.AttestationHistoryForPubKeys(ctx, pubKeys)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get attestation history")
+	}
+
+	interchange := &Interchange{
+		Metadata: &Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: make([]*ValidatorData, 0, len(pubKeys)),
+	}
+	for _, pubKey := range pubKeys {
+		signedBlocks, err := exportSignedBlocks(ctx, validatorDB, pubKey)
+		if err != nil {
+			return nil, err
+		}
+		interchange.Data = append(interchange.Data, &ValidatorData{
+			PubKey:             fmt.Sprintf("%#x", pubKey),
+			SignedBlocks:       signedBlocks,
+			SignedAttestations: exportSignedAttestations(attHistories[pubKey]),
+		})
+	}
+	return interchange, nil
+}
+
+func exportSignedBlocks(ctx context.Context, validatorDB iface.ValidatorDB, pubKey [48]byte) ([]*SignedBlock, error) {
+	history, err := validatorDB.ProposalHistoryForPubKey(ctx, pubKey[:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not get proposal history for public key %#x", pubKey)
+	}
+	epochs := make([]uint64, 0, len(history))
+	for epoch := range history {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	signedBlocks := make([]*SignedBlock, 0)
+	for _, epoch := range epochs {
+		slotBits := history[epoch]
+		for i := uint64(0); i < slotsPerEpoch; i++ {
+			if slotBits.BitAt(i) {
+				signedBlocks = append(signedBlocks, &SignedBlock{Slot: formatUint(epoch*slotsPerEpoch + i)})
+			}
+		}
+	}
+	return signedBlocks, nil
+}
+
+// exportSignedAttestations walks the target epochs covered by the attestation history, which is
+// a ring buffer of one weak subjectivity period ending at the latest written epoch.
+func exportSignedAttestations(history *slashpb.AttestationHistory) []*SignedAttestation {
+	signedAttestations := make([]*SignedAttestation, 0)
+	if history == nil {
+		return signedAttestations
+	}
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	lowestTarget := uint64(0)
+	if history.LatestEpochWritten >= wsPeriod {
+		lowestTarget = history.LatestEpochWritten - wsPeriod + 1
+	}
+	for target := lowestTarget; target <= history.LatestEpochWritten; target++ {
+		source, ok := history.TargetToSource[target%wsPeriod]
+		if !ok || source == params.BeaconConfig().FarFutureEpoch {
+			continue
+		}
+		signedAttestations = append(signedAttestations, &SignedAttestation{
+			SourceEpoch: formatUint(source),
+			TargetEpoch: formatUint(target),
+		})
+	}
+	return signedAttestations
+}
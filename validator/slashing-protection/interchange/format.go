@@ -0,0 +1,106 @@
+// Package interchange implements the slashing protection interchange format defined in
+// EIP-3076, allowing the proposal and attestation history of validators to be moved
+// between validator clients and hosts without the risk of being slashed.
+package interchange
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FormatVersion is the version of the EIP-3076 interchange format produced and accepted.
+const FormatVersion = "5"
+
+// Interchange is the top level object of a slashing protection interchange file.
+type Interchange struct {
+	Metadata *Metadata        `json:"metadata"`
+	Data     []*ValidatorData `json:"data"`
+}
+
+// Metadata identifies the interchange format version and the chain the history belongs to.
+type Metadata struct {
+	InterchangeFormatVersion string `json:"interchange_format_version"`
+	GenesisValidatorsRoot    string `json:"genesis_validators_root"`
+}
+
+// ValidatorData is the signing history of a single validator public key.
+type ValidatorData struct {
+	PubKey             string               `json:"pubkey"`
+	SignedBlocks       []*SignedBlock       `json:"signed_blocks"`
+	SignedAttestations []*SignedAttestation `json:"signed_attestations"`
+}
+
+// SignedBlock is a block proposal signed by a validator. Slots are encoded as decimal strings.
+type SignedBlock struct {
+	Slot        string `json:"slot"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// SignedAttestation is an attestation signed by a validator. Epochs are encoded as decimal strings.
+type SignedAttestation struct {
+	SourceEpoch string `json:"source_epoch"`
+	TargetEpoch string `json:"target_epoch"`
+	SigningRoot string `json:"signing_root,omitempty"`
+}
+
+// Decode reads an interchange file and checks its metadata.
+func Decode(r io.Reader) (*Interchange, error) {
+	interchange := &Interchange{}
+	if err := json.NewDecoder(r).Decode(interchange); err != nil {
+		return nil, errors.Wrap(err, "could not decode slashing protection interchange file")
+	}
+	if interchange.Metadata == nil {
+		return nil, errors.New("slashing protection interchange file is missing metadata")
+	}
+	if interchange.Metadata.InterchangeFormatVersion != FormatVersion {
+		return nil, errors.Errorf(
+			"unsupported interchange format version %s, wanted %s",
+			interchange.Metadata.InterchangeFormatVersion,
+			FormatVersion,
+		)
+	}
+	return interchange, nil
+}
+
+// PublicKeys returns the validator public keys contained in the interchange file.
+func (i *Interchange) PublicKeys() ([][48]byte, error) {
+	pubKeys := make([][48]byte, 0, len(i.Data))
+	for _, data := range i.Data {
+		pubKey, err := decodePubKey(data.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		pubKeys = append(pubKeys, pubKey)
+	}
+	return pubKeys, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
+
+func decodePubKey(s string) ([48]byte, error) {
+	var pubKey [48]byte
+	b, err := decodeHex(s)
+	if err != nil {
+		return pubKey, errors.Wrapf(err, "could not decode public key %s", s)
+	}
+	if len(b) != len(pubKey) {
+		return pubKey, errors.Errorf("public key %s is not %d bytes long", s, len(pubKey))
+	}
+	copy(pubKey[:], b)
+	return pubKey, nil
+}
+
+func parseUint(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func formatUint(i uint64) string {
+	return strconv.FormatUint(i, 10)
+}
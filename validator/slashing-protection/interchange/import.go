@@ -0,0 +1,261 @@
+package interchange
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db/iface"
+	"github.com/sirupsen/logrus"
+	"go.opencensus.io/trace"
+)
+
+type attestation struct {
+	source uint64
+	target uint64
+}
+
+// ImportJSON reads an interchange file from r and merges it into the database.
+func ImportJSON(ctx context.Context, validatorDB iface.ValidatorDB, genesisValidatorsRoot []byte, r io.Reader) error {
+	interchange, err := Decode(r)
+	if err != nil {
+		return err
+	}
+	return Import(ctx, validatorDB, genesisValidatorsRoot, interchange)
+}
+
+// Import merges the proposal and attestation history of an interchange file into the database.
+// History is only ever added: proposed slots are marked on top of the existing proposal history,
+// and attestations are recorded for target epochs without a stored attestation. If the database
+// already holds an attestation with a different source epoch for a target epoch, the stored
+// attestation is kept, as the validator must not sign at that target epoch again either way.
+// Attestations older than the attestation history can hold raise the low watermark of the
+// validator instead, so that their epochs stay protected.
+//
+// The whole interchange file is parsed and validated before anything is written to the database.
+// The proposal history of every public key in the interchange must have been initialized in the
+// database beforehand.
+func Import(ctx context.Context, validatorDB iface.ValidatorDB, genesisValidatorsRoot []byte, interchange *Interchange) error {
+	ctx, span := trace.StartSpan(ctx, "Interchange.Import")
+	defer span.End()
+
+	root, err := decodeHex(interchange.Metadata.GenesisValidatorsRoot)
+	if err != nil {
+		return errors.Wrap(err, "could not decode genesis validators root")
+	}
+	if !bytes.Equal(root, genesisValidatorsRoot) {
+		return errors.Errorf(
+			"genesis validators root %#x of the interchange file does not match the expected root %#x",
+			root,
+			genesisValidatorsRoot,
+		)
+	}
+
+	pubKeys, err := interchange.PublicKeys()
+	if err != nil {
+		return err
+	}
+	slotsByPubKey := make(map[[48]byte][]uint64, len(pubKeys))
+	attsByPubKey := make(map[[48]byte][]attestation, len(pubKeys))
+	for i, data := range interchange.Data {
+		pubKey := pubKeys[i]
+		slots, err := parseSignedBlocks(pubKey, data.SignedBlocks)
+		if err != nil {
+			return err
+		}
+		atts, err := parseSignedAttestations(pubKey, data.SignedAttestations)
+		if err != nil {
+			return err
+		}
+		slotsByPubKey[pubKey] = append(slotsByPubKey[pubKey], slots...)
+		attsByPubKey[pubKey] = append(attsByPubKey[pubKey], atts...)
+	}
+
+	attHistories, err := validatorDB.AttestationHistoryForPubKeys(ctx, pubKeys)
+	if err != nil {
+		return errors.Wrap(err, "could not get attestation history")
+	}
+	watermarks := make(map[[48]byte]*iface.LowWatermark)
+	skipped := 0
+	for pubKey, atts := range attsByPubKey {
+		watermark := importAttestations(attHistories[pubKey], pubKey, atts)
+		if watermark != nil {
+			watermarks[pubKey] = watermark
+			skipped += watermark.skipped
+		}
+	}
+	if len(watermarks) > 0 {
+		lowWatermarks := make(map[[48]byte]*iface.LowWatermark, len(watermarks))
+		for pubKey, watermark := range watermarks {
+			lowWatermarks[pubKey] = watermark.LowWatermark
+		}
+		// The low watermarks are saved first, so that an interrupted import never leaves
+		// the skipped epochs unprotected.
+		if err := validatorDB.SaveAttestationLowWatermarks(ctx, lowWatermarks); err != nil {
+			return errors.Wrap(err, "could not save attestation low watermarks")
+		}
+		log.WithFields(logrus.Fields{
+			"attestations": skipped,
+			"validators":   len(watermarks),
+		}).Warn("Imported attestations are older than the attestation history holds, protecting them by low watermark")
+	}
+	for pubKey, slots := range slotsByPubKey {
+		if err := importSignedBlocks(ctx, validatorDB, pubKey, slots); err != nil {
+			return err
+		}
+	}
+	if err := validatorDB.SaveAttestationHistoryForPubKeys(ctx, attHistories); err != nil {
+		return errors.Wrap(err, "could not save attestation history")
+	}
+	log.WithField("validators", len(pubKeys)).Info("Imported slashing protection history")
+	return nil
+}
+
+// lowWatermark is the low watermark raised by the attestations skipped by an import.
+type lowWatermark struct {
+	*iface.LowWatermark
+	skipped int
+}
+
+func parseSignedBlocks(pubKey [48]byte, signedBlocks []*SignedBlock) ([]uint64, error) {
+	slots := make([]uint64, 0, len(signedBlocks))
+	for _, blk := range signedBlocks {
+		slot, err := parseUint(blk.Slot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse slot of signed block for public key %#x", pubKey)
+		}
+		slots = append(slots, slot)
+	}
+	return slots, nil
+}
+
+func parseSignedAttestations(pubKey [48]byte, signedAttestations []*SignedAttestation) ([]attestation, error) {
+	atts := make([]attestation, 0, len(signedAttestations))
+	for _, att := range signedAttestations {
+		source, err := parseUint(att.SourceEpoch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse source epoch of signed attestation for public key %#x", pubKey)
+		}
+		target, err := parseUint(att.TargetEpoch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse target epoch of signed attestation for public key %#x", pubKey)
+		}
+		if source > target {
+			return nil, errors.Errorf("signed attestation for public key %#x has source epoch %d after target epoch %d", pubKey, source, target)
+		}
+		atts = append(atts, attestation{source: source, target: target})
+	}
+	return atts, nil
+}
+
+func importSignedBlocks(ctx context.Context, validatorDB iface.ValidatorDB, pubKey [48]byte, slots []uint64) error {
+	slotsPerEpoch := params.BeaconConfig().SlotsPerEpoch
+	slotsByEpoch := make(map[uint64][]uint64)
+	for _, slot := range slots {
+		slotsByEpoch[slot/slotsPerEpoch] = append(slotsByEpoch[slot/slotsPerEpoch], slot)
+	}
+	epochs := make([]uint64, 0, len(slotsByEpoch))
+	for epoch := range slotsByEpoch {
+		epochs = append(epochs, epoch)
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	for _, epoch := range epochs {
+		slotBits, err := validatorDB.ProposalHistoryForEpoch(ctx, pubKey[:], epoch)
+		if err != nil {
+			return errors.Wrapf(err, "could not get proposal history for public key %#x", pubKey)
+		}
+		for _, slot := range slotsByEpoch[epoch] {
+			slotBits.SetBitAt(slot%slotsPerEpoch, true)
+		}
+		if err := validatorDB.SaveProposalHistoryForEpoch(ctx, pubKey[:], epoch, slotBits); err != nil {
+			return errors.Wrapf(err, "could not save proposal history for public key %#x", pubKey)
+		}
+	}
+	return nil
+}
+
+// importAttestations records the attestations in the attestation history. Attestations older
+// than the history can hold are skipped, and the returned low watermark covers them. Returns
+// nil if no attestation was skipped.
+func importAttestations(history *slashpb.AttestationHistory, pubKey [48]byte, atts []attestation) *lowWatermark {
+	sort.Slice(atts, func(i, j int) bool {
+		return atts[i].target < atts[j].target
+	})
+	if history.TargetToSource == nil {
+		history.TargetToSource = make(map[uint64]uint64)
+	}
+
+	var watermark *lowWatermark
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	farFuture := params.BeaconConfig().FarFutureEpoch
+	for _, att := range atts {
+		// Previously pruned, it cannot be stored anymore.
+		if int(att.target) <= int(history.LatestEpochWritten)-int(wsPeriod) {
+			if watermark == nil {
+				watermark = &lowWatermark{LowWatermark: &iface.LowWatermark{}}
+			}
+			if att.source > watermark.SourceEpoch {
+				watermark.SourceEpoch = att.source
+			}
+			if att.target > watermark.TargetEpoch {
+				watermark.TargetEpoch = att.target
+			}
+			watermark.skipped++
+			continue
+		}
+		source := safeTargetToSource(history, att.target)
+		if source == att.source {
+			continue
+		}
+		if source != farFuture {
+			log.WithFields(logrus.Fields{
+				"pubKey":         fmt.Sprintf("%#x", pubKey),
+				"targetEpoch":    att.target,
+				"storedSource":   source,
+				"importedSource": att.source,
+			}).Warn("Imported attestation conflicts with stored attestation history, keeping the stored attestation")
+			continue
+		}
+		markAttestationForTargetEpoch(history, att.source, att.target)
+	}
+	return watermark
+}
+
+// markAttestationForTargetEpoch marks the passed-in epochs as attested for in the attestation history,
+// the same way the validator client does when signing an attestation.
+func markAttestationForTargetEpoch(history *slashpb.AttestationHistory, sourceEpoch uint64, targetEpoch uint64) {
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+
+	if targetEpoch > history.LatestEpochWritten {
+		// If the target epoch to mark is ahead of latest written epoch, override the old targets and mark the requested epoch.
+		// Limit the overwriting to one weak subjectivity period as further is not needed.
+		maxToWrite := history.LatestEpochWritten + wsPeriod
+		for i := history.LatestEpochWritten + 1; i < targetEpoch && i <= maxToWrite; i++ {
+			history.TargetToSource[i%wsPeriod] = params.BeaconConfig().FarFutureEpoch
+		}
+		history.LatestEpochWritten = targetEpoch
+	}
+	history.TargetToSource[targetEpoch%wsPeriod] = sourceEpoch
+}
+
+// safeTargetToSource makes sure the epoch accessed is within bounds, and if it's not it
+// returns the "default" FAR_FUTURE_EPOCH value.
+func safeTargetToSource(history *slashpb.AttestationHistory, targetEpoch uint64) uint64 {
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	if targetEpoch > history.LatestEpochWritten || int(targetEpoch) < int(history.LatestEpochWritten)-int(wsPeriod) {
+		return params.BeaconConfig().FarFutureEpoch
+	}
+	source, ok := history.TargetToSource[targetEpoch%wsPeriod]
+	if !ok {
+		return params.BeaconConfig().FarFutureEpoch
+	}
+	return source
+}
@@ -0,0 +1,298 @@
+package interchange
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prysmaticlabs/go-bitfield"
+	slashpb "github.com/prysmaticlabs/prysm/proto/slashing"
+	"github.com/prysmaticlabs/prysm/shared/params"
+	"github.com/prysmaticlabs/prysm/validator/db"
+)
+
+var genesisValidatorsRoot = bytes.Repeat([]byte{0x04}, 32)
+
+func setupHistory(t *testing.T, validatorDB *db.Store, pubKey [48]byte) {
+	ctx := context.Background()
+	slotBits := bitfield.NewBitlist(params.BeaconConfig().SlotsPerEpoch)
+	slotBits.SetBitAt(3, true)
+	if err := validatorDB.SaveProposalHistoryForEpoch(ctx, pubKey[:], 2, slotBits); err != nil {
+		t.Fatal(err)
+	}
+	history := &slashpb.AttestationHistory{
+		TargetToSource: map[uint64]uint64{
+			0: 0,
+			1: params.BeaconConfig().FarFutureEpoch,
+			2: 1,
+		},
+		LatestEpochWritten: 2,
+	}
+	if err := validatorDB.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKey: history}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExport(t *testing.T) {
+	pubKey := [48]byte{1}
+	validatorDB := db.SetupDB(t, [][48]byte{pubKey})
+	setupHistory(t, validatorDB, pubKey)
+
+	interchange, err := Export(context.Background(), validatorDB, genesisValidatorsRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if interchange.Metadata.InterchangeFormatVersion != FormatVersion {
+		t.Errorf("Wanted format version %s, received %s", FormatVersion, interchange.Metadata.InterchangeFormatVersion)
+	}
+	if interchange.Metadata.GenesisValidatorsRoot != fmt.Sprintf("%#x", genesisValidatorsRoot) {
+		t.Errorf("Unexpected genesis validators root %s", interchange.Metadata.GenesisValidatorsRoot)
+	}
+	want := []*ValidatorData{
+		{
+			PubKey:       fmt.Sprintf("%#x", pubKey),
+			SignedBlocks: []*SignedBlock{{Slot: fmt.Sprintf("%d", 2*params.BeaconConfig().SlotsPerEpoch+3)}},
+			SignedAttestations: []*SignedAttestation{
+				{SourceEpoch: "0", TargetEpoch: "0"},
+				{SourceEpoch: "1", TargetEpoch: "2"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(interchange.Data, want) {
+		t.Errorf("Wanted data %v, received %v", want, interchange.Data)
+	}
+}
+
+func TestExport_MissingGenesisValidatorsRoot(t *testing.T) {
+	validatorDB := db.SetupDB(t, [][48]byte{})
+	if _, err := Export(context.Background(), validatorDB, nil); err == nil {
+		t.Error("Expected export without genesis validators root to fail")
+	}
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	source := db.SetupDB(t, [][48]byte{pubKey})
+	setupHistory(t, source, pubKey)
+
+	buf := new(bytes.Buffer)
+	if err := ExportJSON(ctx, source, genesisValidatorsRoot, buf); err != nil {
+		t.Fatal(err)
+	}
+	target := db.SetupDB(t, [][48]byte{pubKey})
+	if err := ImportJSON(ctx, target, genesisValidatorsRoot, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	slotBits, err := target.ProposalHistoryForEpoch(ctx, pubKey[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slotBits.BitAt(3) {
+		t.Error("Expected imported proposal to be marked")
+	}
+	histories, err := target.AttestationHistoryForPubKeys(ctx, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := histories[pubKey]
+	if history.LatestEpochWritten != 2 {
+		t.Errorf("Wanted latest epoch written 2, received %d", history.LatestEpochWritten)
+	}
+	if safeTargetToSource(history, 0) != 0 || safeTargetToSource(history, 2) != 1 {
+		t.Errorf("Unexpected attestation history %v", history.TargetToSource)
+	}
+	if safeTargetToSource(history, 1) != params.BeaconConfig().FarFutureEpoch {
+		t.Errorf("Expected target epoch 1 to not be attested for, received %v", history.TargetToSource)
+	}
+}
+
+func TestImport_MergesWithExistingHistory(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	validatorDB := db.SetupDB(t, [][48]byte{pubKey})
+	setupHistory(t, validatorDB, pubKey)
+
+	interchange := &Interchange{
+		Metadata: &Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: []*ValidatorData{
+			{
+				PubKey:       fmt.Sprintf("%#x", pubKey),
+				SignedBlocks: []*SignedBlock{{Slot: fmt.Sprintf("%d", 2*params.BeaconConfig().SlotsPerEpoch+5)}},
+				SignedAttestations: []*SignedAttestation{
+					// Conflicts with the stored attestation for target epoch 2.
+					{SourceEpoch: "0", TargetEpoch: "2"},
+					{SourceEpoch: "2", TargetEpoch: "4"},
+				},
+			},
+		},
+	}
+	if err := Import(ctx, validatorDB, genesisValidatorsRoot, interchange); err != nil {
+		t.Fatal(err)
+	}
+
+	slotBits, err := validatorDB.ProposalHistoryForEpoch(ctx, pubKey[:], 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slotBits.BitAt(3) || !slotBits.BitAt(5) {
+		t.Errorf("Expected existing and imported proposals to be marked, received %v", slotBits)
+	}
+	histories, err := validatorDB.AttestationHistoryForPubKeys(ctx, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := histories[pubKey]
+	if source := safeTargetToSource(history, 2); source != 1 {
+		t.Errorf("Expected stored source epoch 1 to be kept for target epoch 2, received %d", source)
+	}
+	if source := safeTargetToSource(history, 4); source != 2 {
+		t.Errorf("Wanted source epoch 2 for target epoch 4, received %d", source)
+	}
+	if safeTargetToSource(history, 3) != params.BeaconConfig().FarFutureEpoch {
+		t.Error("Expected target epoch 3 to not be attested for")
+	}
+}
+
+func TestImport_RecordsLowWatermarkForSkippedAttestations(t *testing.T) {
+	ctx := context.Background()
+	pubKey := [48]byte{1}
+	validatorDB := db.SetupDB(t, [][48]byte{pubKey})
+	wsPeriod := params.BeaconConfig().WeakSubjectivityPeriod
+	history := &slashpb.AttestationHistory{
+		TargetToSource:     map[uint64]uint64{(wsPeriod + 10) % wsPeriod: wsPeriod + 9},
+		LatestEpochWritten: wsPeriod + 10,
+	}
+	if err := validatorDB.SaveAttestationHistoryForPubKeys(ctx, map[[48]byte]*slashpb.AttestationHistory{pubKey: history}); err != nil {
+		t.Fatal(err)
+	}
+
+	interchange := &Interchange{
+		Metadata: &Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: []*ValidatorData{
+			{
+				PubKey: fmt.Sprintf("%#x", pubKey),
+				SignedAttestations: []*SignedAttestation{
+					{SourceEpoch: "1", TargetEpoch: "4"},
+					{SourceEpoch: "3", TargetEpoch: "5"},
+				},
+			},
+		},
+	}
+	if err := Import(ctx, validatorDB, genesisValidatorsRoot, interchange); err != nil {
+		t.Fatal(err)
+	}
+	watermarks, err := validatorDB.AttestationLowWatermarks(ctx, [][48]byte{pubKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watermark, ok := watermarks[pubKey]
+	if !ok {
+		t.Fatal("Expected a low watermark for the skipped attestations")
+	}
+	if watermark.SourceEpoch != 3 || watermark.TargetEpoch != 5 {
+		t.Errorf("Wanted low watermark source 3 and target 5, received %v", watermark)
+	}
+}
+
+func TestImport_InvalidFileWritesNothing(t *testing.T) {
+	ctx := context.Background()
+	pubKeys := [][48]byte{{1}, {2}}
+	validatorDB := db.SetupDB(t, pubKeys)
+	interchange := &Interchange{
+		Metadata: &Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", genesisValidatorsRoot),
+		},
+		Data: []*ValidatorData{
+			{
+				PubKey:       fmt.Sprintf("%#x", pubKeys[0]),
+				SignedBlocks: []*SignedBlock{{Slot: "3"}},
+			},
+			{
+				PubKey:             fmt.Sprintf("%#x", pubKeys[1]),
+				SignedAttestations: []*SignedAttestation{{SourceEpoch: "2", TargetEpoch: "1"}},
+			},
+		},
+	}
+	want := "after target epoch"
+	err := Import(ctx, validatorDB, genesisValidatorsRoot, interchange)
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("Expected error %q, received %v", want, err)
+	}
+	slotBits, err := validatorDB.ProposalHistoryForEpoch(ctx, pubKeys[0][:], 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slotBits.BitAt(3) {
+		t.Error("Expected no proposal to be imported from an invalid file")
+	}
+}
+
+func TestImport_GenesisValidatorsRootMismatch(t *testing.T) {
+	pubKey := [48]byte{1}
+	validatorDB := db.SetupDB(t, [][48]byte{pubKey})
+	interchange := &Interchange{
+		Metadata: &Metadata{
+			InterchangeFormatVersion: FormatVersion,
+			GenesisValidatorsRoot:    fmt.Sprintf("%#x", bytes.Repeat([]byte{0x05}, 32)),
+		},
+	}
+	want := "does not match the expected root"
+	err := Import(context.Background(), validatorDB, genesisValidatorsRoot, interchange)
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error %q, received %v", want, err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantedErr string
+	}{
+		{
+			name:      "Invalid JSON",
+			input:     "{",
+			wantedErr: "could not decode",
+		},
+		{
+			name:      "Missing metadata",
+			input:     `{"data": []}`,
+			wantedErr: "missing metadata",
+		},
+		{
+			name:      "Unsupported version",
+			input:     `{"metadata": {"interchange_format_version": "4", "genesis_validators_root": "0x00"}, "data": []}`,
+			wantedErr: "unsupported interchange format version",
+		},
+		{
+			name:  "Valid",
+			input: `{"metadata": {"interchange_format_version": "5", "genesis_validators_root": "0x00"}, "data": []}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.input))
+			if tt.wantedErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantedErr) {
+				t.Errorf("Expected error %q, received %v", tt.wantedErr, err)
+			}
+		})
+	}
+}
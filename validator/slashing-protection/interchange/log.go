@@ -0,0 +1,5 @@
+package interchange
+
+import "github.com/sirupsen/logrus"
+
+var log = logrus.WithField("prefix", "slashing-protection")
@@ -82,10 +82,15 @@ var appHelpFlagGroups = []flagGroup{
 			flags.KeyManager,
 			flags.KeyManagerOpts,
 			flags.KeystorePathFlag,
+			flags.KeysDirFlag,
+			flags.MnemonicPassphraseFlag,
+			flags.NumAccountsFlag,
+			flags.AccountStartIndexFlag,
 			flags.PasswordFlag,
 			flags.DisablePenaltyRewardLogFlag,
 			flags.UnencryptedKeysFlag,
 			flags.GraffitiFlag,
+			flags.DoppelgangerEpochsFlag,
 			flags.GrpcRetriesFlag,
 			flags.GrpcHeadersFlag,
 			flags.SlasherRPCProviderFlag,
@@ -93,6 +98,12 @@ var appHelpFlagGroups = []flagGroup{
 			flags.SourceDirectories,
 			flags.SourceDirectory,
 			flags.TargetDirectory,
+			flags.SlashingProtectionFileFlag,
+			flags.GenesisValidatorsRootFlag,
+			flags.EnableKeyManagerAPIFlag,
+			flags.KeyManagerAPIHostFlag,
+			flags.KeyManagerAPIPortFlag,
+			flags.KeyManagerAPITokenFileFlag,
 			flags.DisableAccountMetricsFlag,
 		},
 	},